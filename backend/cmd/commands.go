@@ -0,0 +1,107 @@
+package main
+
+import (
+	"cinema-booking/scripts"
+	"flag"
+	"fmt"
+	"os"
+)
+
+// runCommand - выполнить CLI подкоманду и вернуть код выхода
+//
+//	go run ./cmd import-schedule [-dry-run] schedule.csv
+//	go run ./cmd renew-subscriptions
+//	go run ./cmd expire-bookings
+//	go run ./cmd send-notifications
+//	go run ./cmd migrate-reviews
+//	go run ./cmd migrate-people
+//	go run ./cmd migrate-email-verified
+//	go run ./cmd recalculate-cinema-ratings
+//	go run ./cmd compute-recommendations
+//	go run ./cmd update-movie-status
+//	go run ./cmd import-movies [-dry-run] movies.json|dir
+func runCommand(args []string) int {
+	switch args[0] {
+	case "import-schedule":
+		fs := flag.NewFlagSet("import-schedule", flag.ContinueOnError)
+		dryRun := fs.Bool("dry-run", false, "validate rows without saving")
+		if err := fs.Parse(args[1:]); err != nil || fs.NArg() != 1 {
+			fmt.Fprintln(os.Stderr, "Usage: import-schedule [-dry-run] <file.csv|file.json>")
+			return 2
+		}
+		if !scripts.ImportScheduleFile(fs.Arg(0), *dryRun) {
+			return 1
+		}
+		return 0
+
+	case "renew-subscriptions":
+		if !scripts.RenewSubscriptions() {
+			return 1
+		}
+		return 0
+
+	case "expire-bookings":
+		if !scripts.ExpireBookings() {
+			return 1
+		}
+		return 0
+
+	case "send-notifications":
+		if !scripts.SendNotifications() {
+			return 1
+		}
+		return 0
+
+	case "migrate-reviews":
+		if !scripts.MigrateMovieReviews() {
+			return 1
+		}
+		return 0
+
+	case "migrate-people":
+		if !scripts.MigratePeople() {
+			return 1
+		}
+		return 0
+
+	case "migrate-email-verified":
+		if !scripts.MigrateEmailVerified() {
+			return 1
+		}
+		return 0
+
+	case "recalculate-cinema-ratings":
+		if !scripts.RecalculateCinemaRatings() {
+			return 1
+		}
+		return 0
+
+	case "compute-recommendations":
+		if !scripts.ComputeRecommendations() {
+			return 1
+		}
+		return 0
+
+	case "update-movie-status":
+		if !scripts.UpdateMovieStatuses() {
+			return 1
+		}
+		return 0
+
+	case "import-movies":
+		fs := flag.NewFlagSet("import-movies", flag.ContinueOnError)
+		dryRun := fs.Bool("dry-run", false, "report diffs without saving")
+		if err := fs.Parse(args[1:]); err != nil || fs.NArg() != 1 {
+			fmt.Fprintln(os.Stderr, "Usage: import-movies [-dry-run] <file.json|dir>")
+			return 2
+		}
+		if !scripts.ImportMovieMetadata(fs.Arg(0), *dryRun) {
+			return 1
+		}
+		return 0
+
+	default:
+		fmt.Fprintf(os.Stderr, "Unknown command %q. Available: import-schedule, renew-subscriptions, expire-bookings, send-notifications, migrate-reviews, migrate-people, migrate-email-verified, recalculate-cinema-ratings, compute-recommendations, update-movie-status, import-movies\n", args[0])
+		return 2
+	}
+}
@@ -2,9 +2,13 @@ package main
 
 import (
 	"cinema-booking/config"
+	"cinema-booking/handlers"
 	"cinema-booking/routes"
 	"cinema-booking/scripts"
 	"log"
+	"os"
+	"strings"
+	"time"
 
 	"github.com/gin-gonic/gin"
 )
@@ -19,10 +23,26 @@ func main() {
 	config.ConnectDB()
 	defer config.DisconnectDB()
 
+	// CLI подкоманды (вместо запуска сервера)
+	if len(os.Args) > 1 {
+		code := runCommand(os.Args[1:])
+		config.DisconnectDB()
+		os.Exit(code)
+	}
+
 	// 3. Создать индексы
 	log.Println("📊 Creating indexes...")
 	scripts.CreateIndexes()
 
+	// Аккаунты, созданные до подтверждения email, не должны терять доступ к оплате
+	scripts.MigrateEmailVerified()
+
+	// Поисковый индекс для подсказок (в памяти, обновляется при изменениях и раз в 10 минут)
+	handlers.StartSearchIndex(10 * time.Minute)
+
+	// Неоплаченные брони с истекшим сроком закрываются с возвратом мест и списаний
+	handlers.StartBookingExpiry(time.Minute)
+
 	// 4. Заполнить базу данными
 	// ⚠️ Раскомментируй только при первом запуске!
 	//log.Println("🌱 Seeding database...")
@@ -35,6 +55,13 @@ func main() {
 	// Создать router
 	router := gin.Default()
 
+	// IP клиента (лимиты входа) берется из X-Forwarded-For только от доверенных прокси
+	if proxies := config.AppConfig.TrustedProxies; proxies != "" {
+		if err := router.SetTrustedProxies(strings.Split(proxies, ",")); err != nil {
+			log.Fatal("❌ Invalid TRUSTED_PROXIES:", err)
+		}
+	}
+
 	// 6. Настроить маршруты
 	routes.SetupRoutes(router)
 
@@ -14,10 +14,21 @@ type Config struct {
 	MongoURI        string
 	MongoDatabase   string
 	JWTSecret       string
-	JWTExpiration   string
+	JWTExpiration   string // срок access токена, например "15m"
+	JWTRefreshTTL   string // срок refresh токена, например "720h"
 	MaxUploadSize   int64
 	UploadDir       string
 	GridFSBucket    string
+	LangFallback    string
+	AppURL          string // адрес фронтенда для ссылок в письмах
+	MailDriver      string // "smtp", "file", "console"
+	MailFrom        string
+	MailDir         string // каталог для MAIL_DRIVER=file
+	SMTPHost        string
+	SMTPPort        string
+	SMTPUsername    string
+	SMTPPassword    string
+	TrustedProxies  string // через запятую; X-Forwarded-For учитывается только от них
 }
 
 var AppConfig *Config
@@ -36,10 +47,21 @@ func LoadConfig() {
 		MongoURI:      getEnv("MONGO_URI", "mongodb://localhost:27017"),
 		MongoDatabase: getEnv("MONGO_DATABASE", "cinema_booking_db"),
 		JWTSecret:     getEnv("JWT_SECRET", "default-secret-key"),
-		JWTExpiration: getEnv("JWT_EXPIRATION", "24h"),
+		JWTExpiration: getEnv("JWT_EXPIRATION", "15m"),
+		JWTRefreshTTL: getEnv("JWT_REFRESH_EXPIRATION", "720h"),
 		MaxUploadSize: maxSize,
 		UploadDir:     getEnv("UPLOAD_DIR", "../uploads"),
 		GridFSBucket:  getEnv("GRIDFS_BUCKET", "cinema_files"),
+		LangFallback:  getEnv("LANG_FALLBACK", "en,ru,kk"),
+		AppURL:        getEnv("APP_URL", "http://localhost:3000"),
+		MailDriver:    getEnv("MAIL_DRIVER", "console"),
+		MailFrom:      getEnv("MAIL_FROM", "Cinema Booking <no-reply@cinema.local>"),
+		MailDir:       getEnv("MAIL_DIR", "../mail"),
+		SMTPHost:      getEnv("SMTP_HOST", ""),
+		SMTPPort:      getEnv("SMTP_PORT", "587"),
+		SMTPUsername:  getEnv("SMTP_USERNAME", ""),
+		SMTPPassword:  getEnv("SMTP_PASSWORD", ""),
+		TrustedProxies: getEnv("TRUSTED_PROXIES", ""),
 	}
 
 	log.Println("✅ Configuration loaded successfully")
@@ -6,6 +6,7 @@ import (
 	"time"
 
 	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/gridfs"
 	"go.mongodb.org/mongo-driver/mongo/options"
 )
 
@@ -50,3 +51,8 @@ func DisconnectDB() {
 func GetCollection(collectionName string) *mongo.Collection {
 	return DB.Collection(collectionName)
 }
+
+// GetGridFSBucket - GridFS bucket для постеров, трейлеров и изображений
+func GetGridFSBucket() (*gridfs.Bucket, error) {
+	return gridfs.NewBucket(DB, options.GridFSBucket().SetName(AppConfig.GridFSBucket))
+}
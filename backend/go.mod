@@ -3,11 +3,14 @@ module cinema-booking
 go 1.25.5
 
 require (
+	github.com/HugoSmits86/nativewebp v0.9.3
 	github.com/gin-gonic/gin v1.11.0
 	github.com/golang-jwt/jwt/v5 v5.3.1
 	github.com/joho/godotenv v1.5.1
 	go.mongodb.org/mongo-driver v1.17.8
 	golang.org/x/crypto v0.47.0
+	golang.org/x/image v0.33.0
+	golang.org/x/text v0.33.0
 )
 
 require (
@@ -45,6 +48,5 @@ require (
 	golang.org/x/net v0.49.0 // indirect
 	golang.org/x/sync v0.19.0 // indirect
 	golang.org/x/sys v0.40.0 // indirect
-	golang.org/x/text v0.33.0 // indirect
 	google.golang.org/protobuf v1.36.11 // indirect
 )
@@ -0,0 +1,369 @@
+package handlers
+
+import (
+	"cinema-booking/config"
+	"cinema-booking/i18n"
+	"cinema-booking/mailer"
+	"cinema-booking/models"
+	"cinema-booking/utils"
+	"context"
+	"fmt"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// Сроки действия ссылок из писем
+const (
+	passwordResetTTL     = time.Hour
+	emailVerificationTTL = 48 * time.Hour
+	// Повторное письмо не чаще раза в минуту
+	userTokenResendInterval = time.Minute
+)
+
+// ForgotPasswordRequest - запрос ссылки для сброса пароля
+type ForgotPasswordRequest struct {
+	Email string `json:"email" binding:"required"`
+}
+
+// ResetPasswordRequest - новый пароль по токену из письма
+type ResetPasswordRequest struct {
+	Token    string `json:"token" binding:"required"`
+	Password string `json:"password" binding:"required"`
+}
+
+// VerifyEmailRequest - подтверждение email по токену из письма
+type VerifyEmailRequest struct {
+	Token string `json:"token" binding:"required"`
+}
+
+var (
+	appMailer     mailer.Mailer
+	appMailerOnce sync.Once
+)
+
+// SetMailer - заменить способ отправки писем (тесты, живой провайдер)
+func SetMailer(m mailer.Mailer) {
+	appMailerOnce.Do(func() {})
+	appMailer = m
+}
+
+// getMailer - mailer из конфига (MAIL_DRIVER); при ошибке конфигурации письма печатаются в консоль
+func getMailer() mailer.Mailer {
+	appMailerOnce.Do(func() {
+		cfg := config.AppConfig
+		m, err := mailer.New(cfg.MailDriver, mailer.SMTPMailer{
+			Host:     cfg.SMTPHost,
+			Port:     cfg.SMTPPort,
+			Username: cfg.SMTPUsername,
+			Password: cfg.SMTPPassword,
+			From:     cfg.MailFrom,
+		}, cfg.MailDir)
+		if err != nil {
+			fmt.Printf("Warning: mailer is not configured, printing emails to console: %v\n", err)
+			m = mailer.ConsoleMailer{From: cfg.MailFrom}
+		}
+		appMailer = m
+	})
+	return appMailer
+}
+
+// appLink - ссылка на страницу фронтенда с токеном
+func appLink(path, token string) string {
+	return strings.TrimRight(config.AppConfig.AppURL, "/") + path + "?token=" + url.QueryEscape(token)
+}
+
+// createUserToken - выдать одноразовый токен для письма; прежние неиспользованные токены
+// того же назначения перестают действовать
+func createUserToken(ctx context.Context, user models.User, purpose string, ttl time.Duration) (string, error) {
+	token, err := utils.RandomToken(32)
+	if err != nil {
+		return "", err
+	}
+
+	userTokens := config.GetCollection("user_tokens")
+	if _, err := userTokens.DeleteMany(ctx, bson.M{
+		"userId":  user.ID,
+		"purpose": purpose,
+		"usedAt":  bson.M{"$exists": false},
+	}); err != nil {
+		return "", err
+	}
+
+	now := time.Now()
+	_, err = userTokens.InsertOne(ctx, models.UserToken{
+		UserID:    user.ID,
+		Purpose:   purpose,
+		TokenHash: utils.HashToken(token),
+		Email:     user.Email,
+		ExpiresAt: now.Add(ttl),
+		CreatedAt: now,
+	})
+	if err != nil {
+		return "", err
+	}
+	return token, nil
+}
+
+// recentlySent - письмо этого назначения уже отправлено меньше минуты назад
+func recentlySent(ctx context.Context, userID primitive.ObjectID, purpose string) bool {
+	count, err := config.GetCollection("user_tokens").CountDocuments(ctx, bson.M{
+		"userId":    userID,
+		"purpose":   purpose,
+		"createdAt": bson.M{"$gt": time.Now().Add(-userTokenResendInterval)},
+	})
+	return err == nil && count > 0
+}
+
+// consumeUserToken - атомарно пометить токен использованным.
+// Возвращает nil, если токен неизвестен, уже использован или истек.
+func consumeUserToken(ctx context.Context, token, purpose string) (*models.UserToken, error) {
+	now := time.Now()
+	var userToken models.UserToken
+	err := config.GetCollection("user_tokens").FindOneAndUpdate(ctx,
+		bson.M{
+			"tokenHash": utils.HashToken(token),
+			"purpose":   purpose,
+			"usedAt":    bson.M{"$exists": false},
+			"expiresAt": bson.M{"$gt": now},
+		},
+		bson.M{"$set": bson.M{"usedAt": now}},
+	).Decode(&userToken)
+	if err == mongo.ErrNoDocuments {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &userToken, nil
+}
+
+// sendVerificationEmail - письмо со ссылкой подтверждения email
+func sendVerificationEmail(ctx context.Context, lang string, user models.User) error {
+	token, err := createUserToken(ctx, user, models.UserTokenEmailVerification, emailVerificationTTL)
+	if err != nil {
+		return err
+	}
+
+	return getMailer().Send(ctx, mailer.Message{
+		To:      user.Email,
+		Subject: i18n.T(lang, "Confirm your email"),
+		Body: i18n.T(lang, "Hello") + ", " + user.FullName + "!\n\n" +
+			i18n.T(lang, "Open the link to confirm your email address (valid for 48 hours):") + "\n" +
+			appLink("/verify-email", token) + "\n\n" +
+			i18n.T(lang, "If you did not create an account, ignore this email."),
+	})
+}
+
+// sendPasswordResetEmail - письмо со ссылкой сброса пароля, если email зарегистрирован
+// (не чаще раза в минуту). Вызывается в отдельной горутине со своим контекстом.
+func sendPasswordResetEmail(email, lang string) {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	var user models.User
+	if err := config.GetCollection("users").FindOne(ctx, bson.M{"email": email}).Decode(&user); err != nil {
+		return
+	}
+	if recentlySent(ctx, user.ID, models.UserTokenPasswordReset) {
+		return
+	}
+
+	token, err := createUserToken(ctx, user, models.UserTokenPasswordReset, passwordResetTTL)
+	if err == nil {
+		err = getMailer().Send(ctx, mailer.Message{
+			To:      user.Email,
+			Subject: i18n.T(lang, "Password reset"),
+			Body: i18n.T(lang, "Hello") + ", " + user.FullName + "!\n\n" +
+				i18n.T(lang, "Open the link to set a new password (valid for 1 hour):") + "\n" +
+				appLink("/reset-password", token) + "\n\n" +
+				i18n.T(lang, "If you did not request a password reset, ignore this email."),
+		})
+	}
+	if err != nil {
+		fmt.Printf("Warning: failed to send password reset email to user %s: %v\n", user.ID.Hex(), err)
+	}
+}
+
+// ForgotPassword - отправить ссылку для сброса пароля.
+// Ответ одинаковый для любых email, чтобы нельзя было проверить, зарегистрирован ли адрес.
+// POST /api/auth/password/forgot
+func ForgotPassword(c *gin.Context) {
+	var req ForgotPasswordRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ErrorResponse(c, 400, "Invalid request data: "+err.Error())
+		return
+	}
+
+	req.Email = utils.SanitizeString(req.Email)
+	if !utils.ValidateEmail(req.Email) {
+		utils.ErrorResponse(c, 400, "Invalid email format")
+		return
+	}
+
+	// Поиск пользователя и отправка письма - в фоне: время ответа не выдает, зарегистрирован ли адрес
+	go sendPasswordResetEmail(req.Email, i18n.FromContext(c))
+
+	utils.SuccessWithMessage(c, 200, "If the email is registered, a password reset link has been sent", nil)
+}
+
+// ResetPassword - установить новый пароль по токену из письма.
+// Все сессии пользователя закрываются; email считается подтвержденным (письмо получено).
+// POST /api/auth/password/reset
+func ResetPassword(c *gin.Context) {
+	var req ResetPasswordRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ErrorResponse(c, 400, "Invalid request data: "+err.Error())
+		return
+	}
+
+	if valid, msg := utils.ValidatePassword(req.Password); !valid {
+		utils.ErrorResponse(c, 400, msg)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	userToken, err := consumeUserToken(ctx, req.Token, models.UserTokenPasswordReset)
+	if err != nil {
+		utils.ErrorResponse(c, 500, "Failed to reset password")
+		return
+	}
+	if userToken == nil {
+		utils.ErrorResponse(c, 400, "Invalid or expired reset link")
+		return
+	}
+
+	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(req.Password), bcrypt.DefaultCost)
+	if err != nil {
+		utils.ErrorResponse(c, 500, "Failed to hash password")
+		return
+	}
+
+	now := time.Now()
+	result, err := config.GetCollection("users").UpdateOne(ctx,
+		bson.M{"_id": userToken.UserID, "email": userToken.Email},
+		bson.M{"$set": bson.M{
+			"password":        string(hashedPassword),
+			"emailVerified":   true,
+			"emailVerifiedAt": now,
+			"updatedAt":       now,
+		}},
+	)
+	if err != nil {
+		utils.ErrorResponse(c, 500, "Failed to reset password")
+		return
+	}
+	if result.MatchedCount == 0 {
+		utils.ErrorResponse(c, 400, "Invalid or expired reset link")
+		return
+	}
+
+	// Старый пароль мог быть известен злоумышленнику - закрыть все сессии
+	if _, err := revokeSessions(ctx, bson.M{"userId": userToken.UserID}); err != nil {
+		fmt.Printf("Warning: failed to revoke sessions after password reset: %v\n", err)
+	}
+	// Новый пароль - снять блокировку входа
+	if err := clearLoginFailures(ctx, userToken.Email); err != nil {
+		fmt.Printf("Warning: failed to reset login attempts after password reset: %v\n", err)
+	}
+
+	utils.SuccessWithMessage(c, 200, "Password has been reset. Please log in again", nil)
+}
+
+// VerifyEmail - подтвердить email по токену из письма
+// POST /api/auth/email/verify
+func VerifyEmail(c *gin.Context) {
+	var req VerifyEmailRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ErrorResponse(c, 400, "Invalid request data: "+err.Error())
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	userToken, err := consumeUserToken(ctx, req.Token, models.UserTokenEmailVerification)
+	if err != nil {
+		utils.ErrorResponse(c, 500, "Failed to verify email")
+		return
+	}
+	if userToken == nil {
+		utils.ErrorResponse(c, 400, "Invalid or expired verification link")
+		return
+	}
+
+	now := time.Now()
+	result, err := config.GetCollection("users").UpdateOne(ctx,
+		bson.M{"_id": userToken.UserID, "email": userToken.Email},
+		bson.M{"$set": bson.M{"emailVerified": true, "emailVerifiedAt": now, "updatedAt": now}},
+	)
+	if err != nil {
+		utils.ErrorResponse(c, 500, "Failed to verify email")
+		return
+	}
+	if result.MatchedCount == 0 {
+		utils.ErrorResponse(c, 400, "Invalid or expired verification link")
+		return
+	}
+
+	utils.SuccessWithMessage(c, 200, "Email verified successfully", gin.H{
+		"email":         userToken.Email,
+		"emailVerified": true,
+	})
+}
+
+// MarkExistingEmailsVerified - считать подтвержденными email аккаунтов, созданных до появления
+// подтверждения (поле emailVerified отсутствует). Новые аккаунты пишут emailVerified: false явно.
+// Возвращает число обновленных пользователей.
+func MarkExistingEmailsVerified(ctx context.Context) (int64, error) {
+	now := time.Now()
+	result, err := config.GetCollection("users").UpdateMany(ctx,
+		bson.M{"emailVerified": bson.M{"$exists": false}},
+		bson.M{"$set": bson.M{"emailVerified": true, "emailVerifiedAt": now}},
+	)
+	if err != nil {
+		return 0, err
+	}
+	return result.ModifiedCount, nil
+}
+
+// ResendVerificationEmail - отправить письмо подтверждения повторно
+// POST /api/auth/email/resend
+func ResendVerificationEmail(c *gin.Context) {
+	userID, _ := c.Get("userId")
+	userObjectID, _ := primitive.ObjectIDFromHex(userID.(string))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	var user models.User
+	if err := config.GetCollection("users").FindOne(ctx, bson.M{"_id": userObjectID}).Decode(&user); err != nil {
+		utils.ErrorResponse(c, 404, "User not found")
+		return
+	}
+	if user.EmailVerified {
+		utils.ErrorResponse(c, 409, "Email is already verified")
+		return
+	}
+	if recentlySent(ctx, user.ID, models.UserTokenEmailVerification) {
+		utils.ErrorResponse(c, 429, "Verification email was sent recently. Try again in a minute")
+		return
+	}
+
+	if err := sendVerificationEmail(ctx, i18n.FromContext(c), user); err != nil {
+		fmt.Printf("Warning: failed to send verification email to user %s: %v\n", user.ID.Hex(), err)
+		utils.ErrorResponse(c, 500, "Failed to send verification email")
+		return
+	}
+
+	utils.SuccessWithMessage(c, 200, "Verification email sent", nil)
+}
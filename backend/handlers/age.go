@@ -0,0 +1,202 @@
+package handlers
+
+import (
+	"cinema-booking/config"
+	"cinema-booking/models"
+	"cinema-booking/utils"
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Минимальный возраст по рейтингу MPAA (если AgeRestriction фильма не задан)
+var ratingMinAge = map[string]int{
+	"G":     0,
+	"PG":    0,
+	"PG-13": 13,
+	"R":     17,
+	"NC-17": 18,
+}
+
+// Причины проверки возраста на входе
+const (
+	ageCheckUnderage = "underage"
+	ageCheckUnknown  = "unknown_age"
+)
+
+// movieMinAge - возрастное ограничение фильма: AgeRestriction или по рейтингу
+func movieMinAge(movie *models.Movie) int {
+	if movie == nil {
+		return 0
+	}
+	if movie.AgeRestriction > 0 {
+		return movie.AgeRestriction
+	}
+	return ratingMinAge[strings.ToUpper(movie.Rating)]
+}
+
+// cinemaAgePolicy - политика кинотеатра (по умолчанию "warn")
+func cinemaAgePolicy(cinema *models.Cinema) string {
+	if cinema != nil && cinema.AgePolicy == models.AgePolicyBlock {
+		return models.AgePolicyBlock
+	}
+	return models.AgePolicyWarn
+}
+
+// ageAt - полных лет на дату at
+func ageAt(dateOfBirth, at time.Time) int {
+	at = at.In(time.Local)
+	age := at.Year() - dateOfBirth.Year()
+	if at.Month() < dateOfBirth.Month() || (at.Month() == dateOfBirth.Month() && at.Day() < dateOfBirth.Day()) {
+		age--
+	}
+	return age
+}
+
+// parseDateOfBirth - дата рождения в формате YYYY-MM-DD (хранится как полночь UTC)
+func parseDateOfBirth(value string) (time.Time, error) {
+	dob, err := time.Parse("2006-01-02", strings.TrimSpace(value))
+	if err != nil {
+		return time.Time{}, fmt.Errorf("Invalid date of birth. Use: YYYY-MM-DD")
+	}
+	if age := ageAt(dob, time.Now()); age < 0 || age > 120 {
+		return time.Time{}, fmt.Errorf("Invalid date of birth. Use: YYYY-MM-DD")
+	}
+	return dob, nil
+}
+
+// ageRule - возрастное ограничение сеанса для клиента (в расчете цены): клиент сверяет его
+// с датой рождения и предупреждает заранее. Без даты рождения документы проверяются на входе.
+func (pc *pricingContext) ageRule() gin.H {
+	minAge := movieMinAge(pc.Movie)
+	rule := gin.H{
+		"minAge": minAge,
+		"policy": cinemaAgePolicy(pc.Cinema),
+	}
+	if pc.Movie != nil {
+		rule["rating"] = pc.Movie.Rating
+	}
+	return rule
+}
+
+// checkViewerAge - проверить возраст покупателя на момент сеанса.
+// Возвращает причину проверки документов на входе ("" - проверка не нужна)
+// или ошибку, если кинотеатр запрещает бронь младше возраста фильма.
+func (pc *pricingContext) checkViewerAge(user *models.User) (string, error) {
+	minAge := movieMinAge(pc.Movie)
+	if minAge == 0 {
+		return "", nil
+	}
+	if user == nil || user.DateOfBirth == nil {
+		return ageCheckUnknown, nil
+	}
+	if ageAt(*user.DateOfBirth, pc.Showtime.StartTime) >= minAge {
+		return "", nil
+	}
+	if cinemaAgePolicy(pc.Cinema) == models.AgePolicyBlock {
+		return "", fmt.Errorf("This movie is restricted to viewers aged %d+", minAge)
+	}
+	return ageCheckUnderage, nil
+}
+
+// SetDateOfBirthRequest - изменение даты рождения админом ("" - удалить)
+type SetDateOfBirthRequest struct {
+	DateOfBirth string `json:"dateOfBirth"`
+}
+
+// SetUserDateOfBirth - изменить дату рождения пользователя (admin only)
+// PUT /api/admin/users/:id/date-of-birth
+func SetUserDateOfBirth(c *gin.Context) {
+	userID, err := primitive.ObjectIDFromHex(c.Param("id"))
+	if err != nil {
+		utils.ErrorResponse(c, 400, "Invalid user ID")
+		return
+	}
+
+	var req SetDateOfBirthRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ErrorResponse(c, 400, "Invalid request data: "+err.Error())
+		return
+	}
+
+	update := bson.M{"$unset": bson.M{"dateOfBirth": ""}, "$set": bson.M{"updatedAt": time.Now()}}
+	var dob *time.Time
+	if req.DateOfBirth != "" {
+		parsed, err := parseDateOfBirth(req.DateOfBirth)
+		if err != nil {
+			utils.ErrorResponse(c, 400, err.Error())
+			return
+		}
+		dob = &parsed
+		update = bson.M{"$set": bson.M{"dateOfBirth": parsed, "updatedAt": time.Now()}}
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	result, err := config.GetCollection("users").UpdateOne(ctx, bson.M{"_id": userID}, update)
+	if err != nil {
+		utils.ErrorResponse(c, 500, "Failed to update user")
+		return
+	}
+	if result.MatchedCount == 0 {
+		utils.ErrorResponse(c, 404, "User not found")
+		return
+	}
+
+	utils.SuccessWithMessage(c, 200, "Date of birth updated successfully", gin.H{
+		"userId":      userID,
+		"dateOfBirth": dob,
+	})
+}
+
+// UpdateAgePolicyRequest - политика возрастных ограничений кинотеатра
+type UpdateAgePolicyRequest struct {
+	Policy string `json:"policy" binding:"required"`
+}
+
+// UpdateCinemaAgePolicy - отказывать в брони младше возраста фильма или проверять документы (admin only)
+// PUT /api/admin/cinemas/:id/age-policy
+func UpdateCinemaAgePolicy(c *gin.Context) {
+	cinemaID, err := primitive.ObjectIDFromHex(c.Param("id"))
+	if err != nil {
+		utils.ErrorResponse(c, 400, "Invalid cinema ID")
+		return
+	}
+
+	var req UpdateAgePolicyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ErrorResponse(c, 400, "Invalid request data: "+err.Error())
+		return
+	}
+	if req.Policy != models.AgePolicyBlock && req.Policy != models.AgePolicyWarn {
+		utils.ErrorResponse(c, 400, "Invalid age policy. Use: block or warn")
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	result, err := config.GetCollection("cinemas").UpdateOne(ctx,
+		bson.M{"_id": cinemaID},
+		bson.M{"$set": bson.M{"agePolicy": req.Policy}},
+	)
+	if err != nil {
+		utils.ErrorResponse(c, 500, "Failed to update cinema")
+		return
+	}
+	if result.MatchedCount == 0 {
+		utils.ErrorResponse(c, 404, "Cinema not found")
+		return
+	}
+
+	utils.SuccessWithMessage(c, 200, "Age policy updated successfully", gin.H{
+		"cinemaId": cinemaID,
+		"policy":   req.Policy,
+	})
+}
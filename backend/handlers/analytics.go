@@ -319,10 +319,12 @@ func GetRevenue(c *gin.Context) {
 	// === АГРЕГАЦИОННЫЙ PIPELINE ===
 
 	pipeline := bson.A{
-		// Шаг 1: Фильтр по дате и статусу
+		// Шаг 1: Фильтр по дате и статусу; билеты по абонементу оплачены ценой тарифа
+		// (выручка абонементов - в /admin/analytics/passes)
 		bson.M{"$match": bson.M{
-			"status":    "confirmed",
-			"createdAt": bson.M{"$gte": fromDate},
+			"status":         "confirmed",
+			"payment.method": bson.M{"$ne": "pass"},
+			"createdAt":      bson.M{"$gte": fromDate},
 		}},
 
 		// Шаг 2: Group - группировка по периоду
@@ -398,3 +400,317 @@ func GetRevenue(c *gin.Context) {
 		},
 	})
 }
+
+// GetRevenueByCategory - выручка в разрезе категорий билетов (Aggregation Pipeline, admin only)
+// GET /api/admin/analytics/revenue-by-category
+func GetRevenueByCategory(c *gin.Context) {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	// Параметры
+	daysStr := c.DefaultQuery("days", "30")
+	days, _ := strconv.Atoi(daysStr)
+	fromDate := time.Now().AddDate(0, 0, -days)
+
+	bookingsCollection := config.GetCollection("bookings")
+
+	// === АГРЕГАЦИОННЫЙ PIPELINE ===
+
+	pipeline := bson.A{
+		// Шаг 1: Фильтр по дате и статусу (билеты по абонементу не продаются поштучно)
+		bson.M{"$match": bson.M{
+			"status":         "confirmed",
+			"payment.method": bson.M{"$ne": "pass"},
+			"createdAt":      bson.M{"$gte": fromDate},
+		}},
+
+		// Шаг 2: Unwind - по одному документу на билет
+		bson.M{"$unwind": "$seats"},
+
+		// Шаг 3: Group - группировка по категории (старые брони без категории - "adult")
+		bson.M{"$group": bson.M{
+			"_id":           bson.M{"$ifNull": bson.A{"$seats.category", "adult"}},
+			"totalRevenue":  bson.M{"$sum": "$seats.price"},
+			"totalDiscount": bson.M{"$sum": bson.M{"$ifNull": bson.A{"$seats.discount", 0}}},
+			"totalTickets":  bson.M{"$sum": 1},
+			"bookings":      bson.M{"$addToSet": "$_id"},
+		}},
+
+		// Шаг 4: Project - форматирование результата
+		bson.M{"$project": bson.M{
+			"_id":                0,
+			"category":           "$_id",
+			"totalRevenue":       bson.M{"$round": bson.A{"$totalRevenue", 2}},
+			"totalDiscount":      bson.M{"$round": bson.A{"$totalDiscount", 2}},
+			"totalTickets":       1,
+			"totalBookings":      bson.M{"$size": "$bookings"},
+			"averageTicketPrice": bson.M{"$round": bson.A{bson.M{"$divide": bson.A{"$totalRevenue", "$totalTickets"}}, 2}},
+		}},
+
+		// Шаг 5: Sort - сортировка по выручке
+		bson.M{"$sort": bson.M{"totalRevenue": -1}},
+	}
+
+	cursor, err := bookingsCollection.Aggregate(ctx, pipeline)
+	if err != nil {
+		utils.ErrorResponse(c, 500, "Failed to aggregate revenue by category: "+err.Error())
+		return
+	}
+	defer cursor.Close(ctx)
+
+	var results []bson.M
+	if err = cursor.All(ctx, &results); err != nil {
+		utils.ErrorResponse(c, 500, "Failed to decode results")
+		return
+	}
+
+	utils.SuccessResponse(c, 200, gin.H{
+		"categories": results,
+		"period":     fmt.Sprintf("Last %d days", days),
+	})
+}
+
+// GetPassAnalytics - использование абонементов против выручки от их продажи (admin only)
+func GetPassAnalytics(c *gin.Context) {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	// Параметры
+	daysStr := c.DefaultQuery("days", "30")
+	days, _ := strconv.Atoi(daysStr)
+	fromDate := time.Now().AddDate(0, 0, -days)
+
+	// === PIPELINE 1: Выручка от продажи и продления абонементов ===
+
+	revenuePipeline := bson.A{
+		bson.M{"$match": bson.M{
+			"type":      "subscription",
+			"status":    "completed",
+			"createdAt": bson.M{"$gte": fromDate},
+		}},
+
+		// Lookup - тариф через абонемент
+		bson.M{"$lookup": bson.M{
+			"from":         "subscriptions",
+			"localField":   "subscriptionId",
+			"foreignField": "_id",
+			"as":           "subscription",
+		}},
+		bson.M{"$unwind": "$subscription"},
+
+		bson.M{"$group": bson.M{
+			"_id":         "$subscription.planId",
+			"planName":    bson.M{"$last": "$subscription.planName"},
+			"revenue":     bson.M{"$sum": bson.M{"$abs": "$amount"}},
+			"payments":    bson.M{"$sum": 1},
+			"subscribers": bson.M{"$addToSet": "$userId"},
+		}},
+	}
+
+	// === PIPELINE 2: Билеты, оплаченные абонементом ===
+
+	redemptionPipeline := bson.A{
+		bson.M{"$match": bson.M{
+			"status":         "confirmed",
+			"payment.method": "pass",
+			"createdAt":      bson.M{"$gte": fromDate},
+		}},
+
+		bson.M{"$lookup": bson.M{
+			"from":         "subscriptions",
+			"localField":   "payment.subscriptionId",
+			"foreignField": "_id",
+			"as":           "subscription",
+		}},
+		bson.M{"$unwind": "$subscription"},
+
+		// Номинальная стоимость билетов, которые были бы оплачены деньгами
+		bson.M{"$group": bson.M{
+			"_id":         "$subscription.planId",
+			"planName":    bson.M{"$last": "$subscription.planName"},
+			"redemptions": bson.M{"$sum": bson.M{"$size": "$seats"}},
+			"faceValue":   bson.M{"$sum": "$subtotal"},
+		}},
+	}
+
+	var revenueRows, redemptionRows []bson.M
+
+	cursor, err := config.GetCollection("transactions").Aggregate(ctx, revenuePipeline)
+	if err != nil {
+		utils.ErrorResponse(c, 500, "Failed to aggregate pass revenue: "+err.Error())
+		return
+	}
+	if err = cursor.All(ctx, &revenueRows); err != nil {
+		utils.ErrorResponse(c, 500, "Failed to decode results")
+		return
+	}
+
+	cursor, err = config.GetCollection("bookings").Aggregate(ctx, redemptionPipeline)
+	if err != nil {
+		utils.ErrorResponse(c, 500, "Failed to aggregate pass redemptions: "+err.Error())
+		return
+	}
+	if err = cursor.All(ctx, &redemptionRows); err != nil {
+		utils.ErrorResponse(c, 500, "Failed to decode results")
+		return
+	}
+
+	// Объединить результаты по тарифу
+	type planStats struct {
+		PlanID               interface{} `json:"planId"`
+		PlanName             interface{} `json:"planName"`
+		Revenue              float64     `json:"revenue"`
+		Payments             int         `json:"payments"`
+		Subscribers          int         `json:"subscribers"`
+		Redemptions          int         `json:"redemptions"`
+		FaceValue            float64     `json:"faceValue"`
+		RevenuePerRedemption float64     `json:"revenuePerRedemption"`
+	}
+
+	statsByPlan := make(map[string]*planStats)
+	var order []string
+	getStats := func(row bson.M) *planStats {
+		key := fmt.Sprint(row["_id"])
+		if stats, ok := statsByPlan[key]; ok {
+			return stats
+		}
+		stats := &planStats{PlanID: row["_id"], PlanName: row["planName"]}
+		statsByPlan[key] = stats
+		order = append(order, key)
+		return stats
+	}
+
+	for _, row := range revenueRows {
+		stats := getStats(row)
+		stats.Revenue = aggregateNumber(row["revenue"])
+		stats.Payments = int(aggregateNumber(row["payments"]))
+		if subscribers, ok := row["subscribers"].(bson.A); ok {
+			stats.Subscribers = len(subscribers)
+		}
+	}
+
+	for _, row := range redemptionRows {
+		stats := getStats(row)
+		stats.Redemptions = int(aggregateNumber(row["redemptions"]))
+		stats.FaceValue = aggregateNumber(row["faceValue"])
+	}
+
+	plans := []*planStats{}
+	var totalRevenue, totalFaceValue float64
+	var totalRedemptions int
+	for _, key := range order {
+		stats := statsByPlan[key]
+		stats.Revenue = roundMoney(stats.Revenue)
+		stats.FaceValue = roundMoney(stats.FaceValue)
+		if stats.Redemptions > 0 {
+			stats.RevenuePerRedemption = roundMoney(stats.Revenue / float64(stats.Redemptions))
+		}
+		totalRevenue += stats.Revenue
+		totalFaceValue += stats.FaceValue
+		totalRedemptions += stats.Redemptions
+		plans = append(plans, stats)
+	}
+
+	summary := gin.H{
+		"totalRevenue":     roundMoney(totalRevenue),
+		"totalRedemptions": totalRedemptions,
+		"totalFaceValue":   roundMoney(totalFaceValue),
+		// > 1: абонементы приносят больше, чем стоили бы билеты по обычной цене
+		"revenueToFaceValue": 0.0,
+	}
+	if totalFaceValue > 0 {
+		summary["revenueToFaceValue"] = roundMoney(totalRevenue / totalFaceValue)
+	}
+
+	utils.SuccessResponse(c, 200, gin.H{
+		"plans":   plans,
+		"summary": summary,
+		"period":  fmt.Sprintf("Last %d days", days),
+	})
+}
+
+// GetAnticipation - ожидаемость фильмов по watchlist (admin only)
+// GET /api/admin/analytics/anticipation?days=7&limit=20
+func GetAnticipation(c *gin.Context) {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "20"))
+	if limit < 1 || limit > 100 {
+		limit = 20
+	}
+	days, _ := strconv.Atoi(c.DefaultQuery("days", "7"))
+	if days < 1 {
+		days = 7
+	}
+	fromDate := time.Now().AddDate(0, 0, -days)
+
+	pipeline := bson.A{
+		// Шаг 1: Group - по фильму
+		bson.M{"$group": bson.M{
+			"_id":           "$movieId",
+			"watchlisted":   bson.M{"$sum": 1},
+			"addedInPeriod": bson.M{"$sum": bson.M{"$cond": bson.A{bson.M{"$gte": bson.A{"$createdAt", fromDate}}, 1, 0}}},
+			// Еще ждут сеансов в своем городе
+			"waitingForShowtimes": bson.M{"$sum": bson.M{"$cond": bson.A{bson.M{"$ifNull": bson.A{"$notifiedAt", false}}, 0, 1}}},
+		}},
+
+		// Шаг 2: Sort + Limit
+		bson.M{"$sort": bson.D{{Key: "watchlisted", Value: -1}, {Key: "addedInPeriod", Value: -1}}},
+		bson.M{"$limit": limit},
+
+		// Шаг 3: Lookup - информация о фильме
+		bson.M{"$lookup": bson.M{
+			"from":         "movies",
+			"localField":   "_id",
+			"foreignField": "_id",
+			"as":           "movie",
+		}},
+		bson.M{"$unwind": "$movie"},
+
+		// Шаг 4: Project
+		bson.M{"$project": bson.M{
+			"_id":                 1,
+			"title":               "$movie.title",
+			"titleRu":             "$movie.titleRu",
+			"titleKz":             "$movie.titleKz",
+			"releaseDate":         "$movie.releaseDate",
+			"isActive":            "$movie.isActive",
+			"watchlisted":         1,
+			"addedInPeriod":       1,
+			"waitingForShowtimes": 1,
+		}},
+	}
+
+	cursor, err := config.GetCollection("watchlist").Aggregate(ctx, pipeline)
+	if err != nil {
+		utils.ErrorResponse(c, 500, "Failed to aggregate watchlist: "+err.Error())
+		return
+	}
+	defer cursor.Close(ctx)
+
+	var results []bson.M
+	if err = cursor.All(ctx, &results); err != nil {
+		utils.ErrorResponse(c, 500, "Failed to decode results")
+		return
+	}
+
+	utils.SuccessResponse(c, 200, gin.H{
+		"movies":      results,
+		"period":      fmt.Sprintf("Last %d days", days),
+		"totalMovies": len(results),
+	})
+}
+
+// aggregateNumber - число из результата агрегации ($sum возвращает int32, int64 или double)
+func aggregateNumber(v interface{}) float64 {
+	switch n := v.(type) {
+	case int32:
+		return float64(n)
+	case int64:
+		return float64(n)
+	case float64:
+		return n
+	}
+	return 0
+}
@@ -0,0 +1,81 @@
+package handlers
+
+import (
+	"cinema-booking/config"
+	"cinema-booking/models"
+	"cinema-booking/utils"
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// recordAudit - добавить запись в журнал безопасности (ошибка только логируется)
+func recordAudit(ctx context.Context, entry models.AuditEntry) {
+	entry.CreatedAt = time.Now()
+	if _, err := config.GetCollection("audit_log").InsertOne(ctx, entry); err != nil {
+		fmt.Printf("Warning: failed to write audit entry %s: %v\n", entry.Type, err)
+	}
+}
+
+// GetAuditLog - журнал безопасности (admin only)
+// GET /api/admin/audit?type=account_locked&userId=...&page=1&limit=50
+func GetAuditLog(c *gin.Context) {
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "50"))
+	if page < 1 {
+		page = 1
+	}
+	if limit < 1 || limit > 200 {
+		limit = 50
+	}
+
+	filter := bson.M{}
+	if entryType := c.Query("type"); entryType != "" {
+		filter["type"] = entryType
+	}
+	if userID := c.Query("userId"); userID != "" {
+		userObjectID, err := primitive.ObjectIDFromHex(userID)
+		if err != nil {
+			utils.ErrorResponse(c, 400, "Invalid user ID")
+			return
+		}
+		filter["userId"] = userObjectID
+	}
+	if ip := c.Query("ip"); ip != "" {
+		filter["ip"] = ip
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	auditCollection := config.GetCollection("audit_log")
+	cursor, err := auditCollection.Find(ctx, filter,
+		options.Find().
+			SetSort(bson.D{{Key: "createdAt", Value: -1}}).
+			SetSkip(int64((page-1)*limit)).
+			SetLimit(int64(limit)),
+	)
+	if err != nil {
+		utils.ErrorResponse(c, 500, "Failed to fetch audit log")
+		return
+	}
+	entries := []models.AuditEntry{}
+	if err := cursor.All(ctx, &entries); err != nil {
+		utils.ErrorResponse(c, 500, "Failed to decode audit log")
+		return
+	}
+
+	total, err := auditCollection.CountDocuments(ctx, filter)
+	if err != nil {
+		utils.ErrorResponse(c, 500, "Failed to count audit entries")
+		return
+	}
+
+	utils.PaginatedResponse(c, entries, page, limit, int(total))
+}
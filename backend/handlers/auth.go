@@ -2,9 +2,11 @@ package handlers
 
 import (
 	"cinema-booking/config"
+	"cinema-booking/i18n"
 	"cinema-booking/models"
 	"cinema-booking/utils"
 	"context"
+	"fmt"
 	"time"
 
 	"github.com/gin-gonic/gin"
@@ -25,12 +27,16 @@ type RegisterRequest struct {
 type LoginRequest struct {
 	Email    string `json:"email" binding:"required"`
 	Password string `json:"password" binding:"required"`
+	DeviceID string `json:"deviceId"` // повторный вход с устройства закрывает его прежнюю сессию
 }
 
 // UpdateProfileRequest - структура для обновления профиля
 type UpdateProfileRequest struct {
 	FullName string `json:"fullName"`
 	Phone    string `json:"phone"`
+	City     string `json:"city"`
+	// Дату рождения можно указать один раз (изменить - только через админа)
+	DateOfBirth string `json:"dateOfBirth"`
 }
 
 // Register - регистрация нового пользователя
@@ -119,25 +125,35 @@ func Register(c *gin.Context) {
 	// Получить ID созданного пользователя
 	newUser.ID = result.InsertedID.(primitive.ObjectID)
 
-	// 7. Создать JWT токен
-	token, err := utils.GenerateToken(newUser)
+	// Письмо для подтверждения email (ошибка отправки не мешает регистрации)
+	if err := sendVerificationEmail(ctx, i18n.FromContext(c), newUser); err != nil {
+		fmt.Printf("Warning: failed to send verification email to user %s: %v\n", newUser.ID.Hex(), err)
+	}
+
+	// 7. Создать access и refresh токены
+	tokens, err := issueTokens(ctx, c, newUser, "", false, nil)
 	if err != nil {
 		utils.ErrorResponse(c, 500, "Failed to generate token")
 		return
 	}
 
 	// 8. Вернуть ответ (без пароля)
-	utils.SuccessResponse(c, 201, gin.H{
-		"token": token,
-		"user": gin.H{
-			"id":       newUser.ID.Hex(),
-			"email":    newUser.Email,
-			"fullName": newUser.FullName,
-			"phone":    newUser.Phone,
-			"role":     newUser.Role,
-			"wallet":   newUser.Wallet,
-		},
-	})
+	tokens["user"] = authUserResponse(newUser)
+	utils.SuccessResponse(c, 201, tokens)
+}
+
+// authUserResponse - данные пользователя в ответах входа и регистрации (без пароля)
+func authUserResponse(user models.User) gin.H {
+	return gin.H{
+		"id":            user.ID.Hex(),
+		"email":         user.Email,
+		"fullName":      user.FullName,
+		"phone":         user.Phone,
+		"role":          user.Role,
+		"wallet":        user.Wallet,
+		"emailVerified": user.EmailVerified,
+		"mfaEnabled":    user.MFAEnabled,
+	}
 }
 
 // Login - вход пользователя
@@ -161,12 +177,19 @@ func Login(c *gin.Context) {
 	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 	defer cancel()
 
+	// Задержка после неудачных попыток и блокировка - до проверки пароля
+	if !checkLoginAllowed(ctx, c, req.Email) {
+		return
+	}
+
 	usersCollection := config.GetCollection("users")
 
 	var user models.User
 	err := usersCollection.FindOne(ctx, bson.M{"email": req.Email}).Decode(&user)
 	if err != nil {
-		// Пользователь не найден
+		// Пользователь не найден: bcrypt все равно выполняется, чтобы время ответа не выдавало email
+		compareDummyPassword(req.Password)
+		handleLoginFailure(ctx, c, req.Email, nil)
 		utils.ErrorResponse(c, 401, "Invalid email or password")
 		return
 	}
@@ -175,29 +198,40 @@ func Login(c *gin.Context) {
 	err = bcrypt.CompareHashAndPassword([]byte(user.Password), []byte(req.Password))
 	if err != nil {
 		// Пароль неправильный
+		handleLoginFailure(ctx, c, req.Email, &user)
 		utils.ErrorResponse(c, 401, "Invalid email or password")
 		return
 	}
 
-	// 5. Создать JWT токен
-	token, err := utils.GenerateToken(user)
+	// 2FA: токены выдаются после кода; счетчик ошибок сбросится только тогда,
+	// иначе верный пароль обнулял бы перебор кодов
+	if user.MFAEnabled {
+		startMFAChallenge(ctx, c, user)
+		return
+	}
+
+	if err := clearLoginFailures(ctx, req.Email); err != nil {
+		fmt.Printf("Warning: failed to reset login attempts: %v\n", err)
+	}
+
+	// 5. Одна сессия на устройство: закрыть прежнюю
+	req.DeviceID = utils.SanitizeString(req.DeviceID)
+	if req.DeviceID != "" {
+		if _, err := revokeSessions(ctx, bson.M{"userId": user.ID, "deviceId": req.DeviceID}); err != nil {
+			fmt.Printf("Warning: failed to revoke previous device session: %v\n", err)
+		}
+	}
+
+	// 6. Создать access и refresh токены
+	tokens, err := issueTokens(ctx, c, user, req.DeviceID, false, nil)
 	if err != nil {
 		utils.ErrorResponse(c, 500, "Failed to generate token")
 		return
 	}
 
-	// 6. Вернуть ответ
-	utils.SuccessResponse(c, 200, gin.H{
-		"token": token,
-		"user": gin.H{
-			"id":       user.ID.Hex(),
-			"email":    user.Email,
-			"fullName": user.FullName,
-			"phone":    user.Phone,
-			"role":     user.Role,
-			"wallet":   user.Wallet,
-		},
-	})
+	// 7. Вернуть ответ
+	tokens["user"] = authUserResponse(user)
+	utils.SuccessResponse(c, 200, tokens)
 }
 
 // GetProfile - получить профиль текущего пользователя
@@ -231,14 +265,17 @@ func GetProfile(c *gin.Context) {
 
 	// 4. Вернуть профиль (без пароля)
 	utils.SuccessResponse(c, 200, gin.H{
-		"id":        user.ID.Hex(),
-		"email":     user.Email,
-		"fullName":  user.FullName,
-		"phone":     user.Phone,
-		"role":      user.Role,
-		"wallet":    user.Wallet,
-		"createdAt": user.CreatedAt,
-		"updatedAt": user.UpdatedAt,
+		"id":            user.ID.Hex(),
+		"email":         user.Email,
+		"fullName":      user.FullName,
+		"phone":         user.Phone,
+		"city":          user.City,
+		"dateOfBirth":   user.DateOfBirth,
+		"emailVerified": user.EmailVerified,
+		"role":          user.Role,
+		"wallet":        user.Wallet,
+		"createdAt":     user.CreatedAt,
+		"updatedAt":     user.UpdatedAt,
 	})
 }
 
@@ -279,6 +316,19 @@ func UpdateProfile(c *gin.Context) {
 		updateFields["phone"] = req.Phone
 	}
 
+	if req.City != "" {
+		updateFields["city"] = utils.SanitizeString(req.City)
+	}
+
+	if req.DateOfBirth != "" {
+		dob, err := parseDateOfBirth(req.DateOfBirth)
+		if err != nil {
+			utils.ErrorResponse(c, 400, err.Error())
+			return
+		}
+		updateFields["dateOfBirth"] = dob
+	}
+
 	// Если нет полей для обновления
 	if len(updateFields) == 0 {
 		utils.ErrorResponse(c, 400, "No fields to update")
@@ -299,13 +349,25 @@ func UpdateProfile(c *gin.Context) {
 	// Использовать $set оператор
 	update := bson.M{"$set": updateFields}
 
-	result, err := usersCollection.UpdateOne(ctx, bson.M{"_id": objectID}, update)
+	// Дата рождения сохраняется, только если еще не указана
+	filter := bson.M{"_id": objectID}
+	if _, ok := updateFields["dateOfBirth"]; ok {
+		filter["dateOfBirth"] = bson.M{"$exists": false}
+	}
+
+	result, err := usersCollection.UpdateOne(ctx, filter, update)
 	if err != nil {
 		utils.ErrorResponse(c, 500, "Failed to update profile")
 		return
 	}
 
 	if result.MatchedCount == 0 {
+		if _, ok := updateFields["dateOfBirth"]; ok {
+			if count, _ := usersCollection.CountDocuments(ctx, bson.M{"_id": objectID}); count > 0 {
+				utils.ErrorResponse(c, 403, "Date of birth is already set. Contact support to change it")
+				return
+			}
+		}
 		utils.ErrorResponse(c, 404, "User not found")
 		return
 	}
@@ -320,12 +382,15 @@ func UpdateProfile(c *gin.Context) {
 
 	// 6. Вернуть обновленный профиль
 	utils.SuccessWithMessage(c, 200, "Profile updated successfully", gin.H{
-		"id":        updatedUser.ID.Hex(),
-		"email":     updatedUser.Email,
-		"fullName":  updatedUser.FullName,
-		"phone":     updatedUser.Phone,
-		"role":      updatedUser.Role,
-		"wallet":    updatedUser.Wallet,
-		"updatedAt": updatedUser.UpdatedAt,
+		"id":            updatedUser.ID.Hex(),
+		"email":         updatedUser.Email,
+		"fullName":      updatedUser.FullName,
+		"phone":         updatedUser.Phone,
+		"city":          updatedUser.City,
+		"dateOfBirth":   updatedUser.DateOfBirth,
+		"emailVerified": updatedUser.EmailVerified,
+		"role":          updatedUser.Role,
+		"wallet":        updatedUser.Wallet,
+		"updatedAt":     updatedUser.UpdatedAt,
 	})
 }
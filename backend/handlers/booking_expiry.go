@@ -0,0 +1,125 @@
+package handlers
+
+import (
+	"cinema-booking/config"
+	"cinema-booking/models"
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// Сколько просроченных броней обрабатывается за один проход
+const bookingExpiryBatch = 500
+
+// releaseBookingHolds - вернуть все, что бронь удерживала: места, промокод,
+// оплату подарочной картой, баллы, абонемент и остатки бара
+func releaseBookingHolds(ctx context.Context, booking models.Booking) {
+	showtimesCollection := config.GetCollection("showtimes")
+	for _, seat := range booking.Seats {
+		_, err := showtimesCollection.UpdateOne(
+			ctx,
+			bson.M{"_id": booking.ShowtimeID},
+			bson.M{
+				"$pull": bson.M{
+					"bookedSeats": bson.M{
+						"row":    seat.Row,
+						"number": seat.Number,
+					},
+				},
+				"$inc": bson.M{
+					"availableSeats": 1,
+				},
+			},
+		)
+		if err != nil {
+			fmt.Printf("Warning: failed to release seat %s%d: %v\n", seat.Row, seat.Number, err)
+		}
+	}
+
+	returnPromoRedemption(ctx, booking)
+	refundGiftCardPayment(ctx, booking)
+	refundLoyaltyPoints(ctx, booking)
+	returnPassAllowance(ctx, booking)
+	releaseConcessionStock(ctx, booking.Concessions)
+}
+
+// expireBooking - атомарно перевести неоплаченную бронь в "expired" и вернуть удержания.
+// false - бронь уже подтверждена, отменена или обработана другим процессом.
+func expireBooking(ctx context.Context, booking models.Booking) (bool, error) {
+	result, err := config.GetCollection("bookings").UpdateOne(ctx,
+		bson.M{"_id": booking.ID, "status": "pending"},
+		bson.M{"$set": bson.M{
+			"status":         "expired",
+			"payment.status": "failed",
+			"updatedAt":      time.Now(),
+		}},
+	)
+	if err != nil {
+		return false, err
+	}
+	if result.ModifiedCount == 0 {
+		return false, nil
+	}
+
+	releaseBookingHolds(ctx, booking)
+	return true, nil
+}
+
+// ExpirePendingBookings - закрыть неоплаченные брони с истекшим сроком (периодически и из CLI).
+// Бронь не удаляется: удержания возвращаются через те же хелперы, что и при отмене.
+func ExpirePendingBookings(ctx context.Context) (int, error) {
+	cursor, err := config.GetCollection("bookings").Find(ctx,
+		bson.M{
+			"status":    "pending",
+			"expiresAt": bson.M{"$lte": time.Now()},
+		},
+		options.Find().SetSort(bson.D{{Key: "expiresAt", Value: 1}}).SetLimit(bookingExpiryBatch),
+	)
+	if err != nil {
+		return 0, err
+	}
+	defer cursor.Close(ctx)
+
+	var due []models.Booking
+	if err := cursor.All(ctx, &due); err != nil {
+		return 0, err
+	}
+
+	expired := 0
+	for _, booking := range due {
+		ok, err := expireBooking(ctx, booking)
+		if err != nil {
+			fmt.Printf("Warning: failed to expire booking %s: %v\n", booking.BookingNumber, err)
+			continue
+		}
+		if ok {
+			expired++
+		}
+	}
+
+	return expired, nil
+}
+
+// StartBookingExpiry - фоновая проверка просроченных броней
+func StartBookingExpiry(interval time.Duration) {
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for range ticker.C {
+			ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+			expired, err := ExpirePendingBookings(ctx)
+			cancel()
+			if err != nil {
+				fmt.Printf("Warning: failed to expire pending bookings: %v\n", err)
+				continue
+			}
+			if expired > 0 {
+				log.Printf("⌛ Expired %d unpaid bookings", expired)
+			}
+		}
+	}()
+}
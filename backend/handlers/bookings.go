@@ -6,6 +6,7 @@ import (
 	"cinema-booking/utils"
 	"context"
 	"fmt"
+	"math"
 	"strconv"
 	"time"
 
@@ -19,12 +20,25 @@ import (
 type CreateBookingRequest struct {
 	ShowtimeID    string        `json:"showtimeId" binding:"required"`
 	Seats         []SeatRequest `json:"seats" binding:"required,min=1"`
-	PaymentMethod string        `json:"paymentMethod" binding:"required"` // "wallet", "card", "cash"
+	PaymentMethod string        `json:"paymentMethod" binding:"required"` // "wallet", "card", "cash", "points", "pass"
+	PromoCode     string        `json:"promoCode"`
+
+	// Подарочная карта: сумма по умолчанию - весь доступный остаток
+	GiftCardCode   string  `json:"giftCardCode"`
+	GiftCardAmount float64 `json:"giftCardAmount"`
+
+	// Баллы лояльности (1 балл = 1 KZT) в счет оплаты
+	LoyaltyPoints int `json:"loyaltyPoints"`
+
+	// Заказ из бара и время выдачи (по умолчанию - за 15 минут до начала)
+	Concessions []ConcessionRequest `json:"concessions"`
+	PickupTime  *time.Time          `json:"pickupTime"`
 }
 
 type SeatRequest struct {
-	Row    string `json:"row" binding:"required"`
-	Number int    `json:"number" binding:"required"`
+	Row      string `json:"row" binding:"required"`
+	Number   int    `json:"number" binding:"required"`
+	Category string `json:"category"` // "adult" (по умолчанию), "child", "student", "senior", "pensioner"
 }
 
 // CreateBooking - создать бронь
@@ -46,8 +60,24 @@ func CreateBooking(c *gin.Context) {
 		return
 	}
 
-	if req.PaymentMethod != "wallet" && req.PaymentMethod != "card" && req.PaymentMethod != "cash" {
-		utils.ErrorResponse(c, 400, "Invalid payment method. Use: wallet, card, or cash")
+	if req.PaymentMethod != "wallet" && req.PaymentMethod != "card" && req.PaymentMethod != "cash" &&
+		req.PaymentMethod != "points" && req.PaymentMethod != "pass" {
+		utils.ErrorResponse(c, 400, "Invalid payment method. Use: wallet, card, cash, points, or pass")
+		return
+	}
+
+	// Абонемент оплачивает билет целиком и не комбинируется с другими скидками
+	if req.PaymentMethod == "pass" && (req.PromoCode != "" || req.GiftCardCode != "" || req.LoyaltyPoints > 0) {
+		utils.ErrorResponse(c, 400, "A pass cannot be combined with promo codes, gift cards or loyalty points")
+		return
+	}
+	if req.PaymentMethod == "pass" && len(req.Concessions) > 0 {
+		utils.ErrorResponse(c, 400, "Concessions for pass bookings are added after booking")
+		return
+	}
+
+	if req.LoyaltyPoints < 0 {
+		utils.ErrorResponse(c, 400, "loyaltyPoints must not be negative")
 		return
 	}
 
@@ -80,14 +110,26 @@ func CreateBooking(c *gin.Context) {
 
 	// === ШАГ 2: Проверить что места доступны ===
 
-	// Попытаться получить информацию о зале
-	hallsCollection := config.GetCollection("halls")
-	var hall models.Hall
-	hallFound := true
-	err = hallsCollection.FindOne(ctx, bson.M{"_id": showtime.HallID}).Decode(&hall)
+	// Загрузить зал, фильм и правила динамического ценообразования
+	pricing, err := loadPricingContext(ctx, showtime)
+	if err != nil {
+		utils.ErrorResponse(c, 500, "Failed to load pricing rules")
+		return
+	}
+	multiplier, surcharge, _ := pricing.adjustments()
+
+	// Возрастное ограничение фильма: отказ или проверка документов на входе (политика кинотеатра)
+	var viewer *models.User
+	if movieMinAge(pricing.Movie) > 0 {
+		var user models.User
+		if err := config.GetCollection("users").FindOne(ctx, bson.M{"_id": userObjectID}).Decode(&user); err == nil {
+			viewer = &user
+		}
+	}
+	ageCheck, err := pricing.checkViewerAge(viewer)
 	if err != nil {
-		// Зал не найден в БД - используем базовую цену сеанса
-		hallFound = false
+		utils.ErrorResponse(c, 403, err.Error())
+		return
 	}
 
 	// Создать map забронированных мест для быстрой проверки
@@ -110,38 +152,126 @@ func CreateBooking(c *gin.Context) {
 			return
 		}
 
-		// Определить цену места
-		var seatPrice float64
-
-		if hallFound {
-			// Найти место в зале и получить цену
-			seatFoundInHall := false
-			for _, hallSeat := range hall.Seats {
-				if hallSeat.Row == seatReq.Row && hallSeat.Number == seatReq.Number {
-					seatPrice = hallSeat.Price + showtime.BasePrice
-					seatFoundInHall = true
-					break
-				}
-			}
-			if !seatFoundInHall {
-				// Место не найдено в зале - используем базовую цену
-				seatPrice = showtime.BasePrice
-			}
-		} else {
-			// Зал не найден - используем базовую цену
-			seatPrice = showtime.BasePrice
+		// Категория билета (детский билет недоступен для фильмов с возрастным ограничением)
+		category, err := pricing.ticketCategory(seatReq.Category)
+		if err != nil {
+			utils.ErrorResponse(c, 400, err.Error())
+			return
 		}
 
+		// Определить цену места (цена в зале + базовая цена сеанса, с учетом правил и скидки категории)
+		seatPrice, discount := categoryPrice(pricing.seatPrice(seatReq.Row, seatReq.Number, multiplier, surcharge).Price, category)
+
 		// Добавить место в бронь
 		bookingSeats = append(bookingSeats, models.BookingSeat{
-			Row:    seatReq.Row,
-			Number: seatReq.Number,
-			Price:  seatPrice,
+			Row:                  seatReq.Row,
+			Number:               seatReq.Number,
+			Price:                seatPrice,
+			Category:             category.Code,
+			Discount:             discount,
+			RequiresVerification: category.RequiresVerification || ageCheck != "",
 		})
 
 		totalAmount += seatPrice
 	}
 
+	subtotal := totalAmount
+	var discounts []models.BookingDiscount
+
+	// Абонемент: проверить лимит периода и ограничения по формату/залу
+	var subscription *models.Subscription
+	if req.PaymentMethod == "pass" {
+		subscription, err = validatePassForBooking(ctx, userObjectID, pricing, len(req.Seats))
+		if err != nil {
+			utils.ErrorResponse(c, 400, err.Error())
+			return
+		}
+
+		// Билеты уже оплачены ценой тарифа: номинал остается в subtotal, к оплате - 0,
+		// иначе выручка и траты для уровня лояльности учтут их второй раз
+		discounts = append(discounts, models.BookingDiscount{
+			Type:   "pass",
+			Amount: totalAmount,
+		})
+		totalAmount = 0
+	}
+
+	// Промокод (скидка на всю бронь)
+	var promo *models.PromoCode
+	if req.PromoCode != "" {
+		promo, err = validatePromoForBooking(ctx, req.PromoCode, userObjectID, pricing, len(req.Seats))
+		if err != nil {
+			utils.ErrorResponse(c, 400, err.Error())
+			return
+		}
+
+		promoAmount := promoDiscount(promo, totalAmount)
+		discounts = append(discounts, models.BookingDiscount{
+			Type:          "promo",
+			Code:          promo.Code,
+			PromoCodeID:   promo.ID,
+			DiscountType:  promo.DiscountType,
+			DiscountValue: promo.DiscountValue,
+			Amount:        promoAmount,
+		})
+		totalAmount = roundMoney(totalAmount - promoAmount)
+	}
+
+	// Заказ из бара (промокод на него не распространяется)
+	var concessions []models.BookingConcession
+	var concessionsTotal float64
+	var pickupTime time.Time
+	if len(req.Concessions) > 0 {
+		concessions, concessionsTotal, err = buildConcessionLines(ctx, showtime.CinemaID, req.Concessions)
+		if err != nil {
+			utils.ErrorResponse(c, 400, err.Error())
+			return
+		}
+		pickupTime, err = concessionPickupTime(req.PickupTime, showtime)
+		if err != nil {
+			utils.ErrorResponse(c, 400, err.Error())
+			return
+		}
+		subtotal = roundMoney(subtotal + concessionsTotal)
+		totalAmount = roundMoney(totalAmount + concessionsTotal)
+	}
+
+	// Подарочная карта (частичная или полная оплата)
+	var giftCard *models.GiftCard
+	var giftCardAmount float64
+	if req.GiftCardCode != "" {
+		giftCard, err = findUsableGiftCard(ctx, req.GiftCardCode)
+		if err != nil {
+			utils.ErrorResponse(c, 400, err.Error())
+			return
+		}
+
+		giftCardAmount = math.Min(giftCard.Balance, totalAmount)
+		if req.GiftCardAmount > 0 {
+			if req.GiftCardAmount > giftCardAmount {
+				utils.ErrorResponse(c, 400, fmt.Sprintf("Gift card amount cannot exceed %.2f KZT", giftCardAmount))
+				return
+			}
+			giftCardAmount = req.GiftCardAmount
+		}
+		giftCardAmount = roundMoney(giftCardAmount)
+	}
+
+	// Баллы лояльности: при оплате "points" покрывают весь остаток
+	loyaltyPoints := req.LoyaltyPoints
+	remaining := roundMoney(totalAmount - giftCardAmount)
+	if req.PaymentMethod == "pass" {
+		remaining = 0
+	} else if req.PaymentMethod == "points" {
+		loyaltyPoints = int(math.Ceil(remaining))
+	} else if float64(loyaltyPoints) > math.Ceil(remaining) {
+		utils.ErrorResponse(c, 400, fmt.Sprintf("Loyalty points cannot exceed %.0f", math.Ceil(remaining)))
+		return
+	}
+
+	// Сумма к оплате основным методом
+	amountDue := roundMoney(math.Max(0, remaining-float64(loyaltyPoints)))
+
 	// === ШАГ 3: Проверить баланс (если оплата через wallet) ===
 
 	usersCollection := config.GetCollection("users")
@@ -154,15 +284,17 @@ func CreateBooking(c *gin.Context) {
 			return
 		}
 
-		if user.Wallet.Balance < totalAmount {
+		if user.Wallet.Balance < amountDue {
 			utils.ErrorResponse(c, 400, fmt.Sprintf("Insufficient wallet balance. Required: %.2f KZT, Available: %.2f KZT",
-				totalAmount, user.Wallet.Balance))
+				amountDue, user.Wallet.Balance))
 			return
 		}
 	}
 
 	// === ШАГ 4: Создать бронь ===
 
+	expiresAt := time.Now().Add(15 * time.Minute)
+
 	bookingNumber := fmt.Sprintf("BK-%s-%06d",
 		time.Now().Format("20060102"),
 		time.Now().UnixNano()%1000000)
@@ -172,6 +304,8 @@ func CreateBooking(c *gin.Context) {
 		UserID:        userObjectID,
 		ShowtimeID:    showtimeID,
 		Seats:         bookingSeats,
+		Subtotal:      subtotal,
+		Discounts:     discounts,
 		TotalAmount:   totalAmount,
 		Status:        "pending",
 		Payment: models.Payment{
@@ -179,28 +313,119 @@ func CreateBooking(c *gin.Context) {
 			Status: "pending",
 		},
 		QRCode:    fmt.Sprintf("QR-%s", bookingNumber),
-		ExpiresAt: time.Now().Add(15 * time.Minute),
+		AgeCheck:  ageCheck,
+		ExpiresAt: &expiresAt,
 		CreatedAt: time.Now(),
 		UpdatedAt: time.Now(),
 	}
 
-	// Если оплата через wallet - сразу подтверждаем
-	if req.PaymentMethod == "wallet" {
+	if giftCard != nil {
+		newBooking.Payment.GiftCardID = giftCard.ID
+		newBooking.Payment.GiftCardCode = giftCard.Code
+		newBooking.Payment.GiftCardAmount = giftCardAmount
+	}
+	newBooking.Payment.LoyaltyPoints = loyaltyPoints
+	if len(concessions) > 0 {
+		newBooking.Concessions = concessions
+		newBooking.ConcessionsTotal = concessionsTotal
+		newBooking.PickupTime = &pickupTime
+		newBooking.ConcessionStatus = "pending"
+	}
+	if subscription != nil {
+		newBooking.Payment.SubscriptionID = subscription.ID
+	}
+
+	// Если оплата через wallet (или все покрыто подарочной картой и баллами) - сразу подтверждаем
+	if req.PaymentMethod == "wallet" || amountDue == 0 {
 		newBooking.Status = "confirmed"
 		newBooking.Payment.Status = "completed"
 		newBooking.Payment.PaidAt = time.Now()
 		newBooking.Payment.TransactionID = fmt.Sprintf("TXN-%s", time.Now().Format("20060102150405"))
+		newBooking.ExpiresAt = nil // оплаченная бронь не истекает
+	}
+
+	// Занять использование промокода (атомарно, с учетом общего лимита)
+	if promo != nil {
+		if err := reservePromoCode(ctx, promo, userObjectID); err != nil {
+			utils.ErrorResponse(c, 400, err.Error())
+			return
+		}
+	}
+
+	// Списать часть суммы с подарочной карты (атомарно)
+	if giftCard != nil && giftCardAmount > 0 {
+		if err := chargeGiftCard(ctx, giftCard, giftCardAmount); err != nil {
+			if promo != nil {
+				releasePromoCode(ctx, promo.ID, userObjectID)
+			}
+			utils.ErrorResponse(c, 400, err.Error())
+			return
+		}
+	}
+
+	// Списать баллы лояльности (атомарно, только при достаточном балансе)
+	if loyaltyPoints > 0 {
+		if err := chargeLoyaltyPoints(ctx, userObjectID, loyaltyPoints); err != nil {
+			if promo != nil {
+				releasePromoCode(ctx, promo.ID, userObjectID)
+			}
+			refundGiftCardPayment(ctx, newBooking)
+			utils.ErrorResponse(c, 400, err.Error())
+			return
+		}
+	}
+
+	// Использовать фильм из лимита абонемента (атомарно)
+	if subscription != nil {
+		if err := consumePassAllowance(ctx, subscription); err != nil {
+			utils.ErrorResponse(c, 400, err.Error())
+			return
+		}
+	}
+
+	// Списать остатки бара (атомарно, все позиции или ни одной)
+	if len(concessions) > 0 {
+		if err := reserveConcessionStock(ctx, concessions); err != nil {
+			if promo != nil {
+				releasePromoCode(ctx, promo.ID, userObjectID)
+			}
+			refundGiftCardPayment(ctx, newBooking)
+			refundLoyaltyPoints(ctx, newBooking)
+			utils.ErrorResponse(c, 400, err.Error())
+			return
+		}
 	}
 
 	bookingsCollection := config.GetCollection("bookings")
 	result, err := bookingsCollection.InsertOne(ctx, newBooking)
 	if err != nil {
+		releaseConcessionStock(ctx, newBooking.Concessions)
+		returnPassAllowance(ctx, newBooking)
+		if promo != nil {
+			releasePromoCode(ctx, promo.ID, userObjectID)
+		}
+		refundGiftCardPayment(ctx, newBooking)
+		refundLoyaltyPoints(ctx, newBooking)
 		utils.ErrorResponse(c, 500, "Failed to create booking")
 		return
 	}
 
 	newBooking.ID = result.InsertedID.(primitive.ObjectID)
 
+	if promo != nil {
+		recordPromoRedemption(ctx, promo, userObjectID, newBooking.ID, discounts[0].Amount)
+	}
+
+	if giftCard != nil && giftCardAmount > 0 {
+		recordGiftCardTransaction(ctx, userObjectID, "gift_card_payment", -giftCardAmount, giftCard, newBooking.ID,
+			fmt.Sprintf("Gift card %s payment for %s", giftCard.Code, bookingNumber))
+	}
+
+	if loyaltyPoints > 0 {
+		recordLoyaltyTransaction(ctx, userObjectID, "redeem", -loyaltyPoints, newBooking.ID,
+			fmt.Sprintf("Points payment for %s", bookingNumber))
+	}
+
 	// === ШАГ 5: Обновить сеанс (добавить забронированные места) ===
 
 	updateSeats := bson.A{}
@@ -230,13 +455,13 @@ func CreateBooking(c *gin.Context) {
 
 	// === ШАГ 6: Списать с кошелька (если wallet) ===
 
-	if req.PaymentMethod == "wallet" {
+	if req.PaymentMethod == "wallet" && amountDue > 0 {
 		_, err = usersCollection.UpdateOne(
 			ctx,
 			bson.M{"_id": userObjectID},
 			bson.M{
 				"$inc": bson.M{
-					"wallet.balance": -totalAmount,
+					"wallet.balance": -amountDue,
 				},
 			},
 		)
@@ -249,7 +474,7 @@ func CreateBooking(c *gin.Context) {
 		transaction := models.Transaction{
 			UserID:      userObjectID,
 			Type:        "booking",
-			Amount:      -totalAmount,
+			Amount:      -amountDue,
 			BookingID:   newBooking.ID,
 			Status:      "completed",
 			Description: fmt.Sprintf("Booking payment for %s", bookingNumber),
@@ -262,10 +487,26 @@ func CreateBooking(c *gin.Context) {
 		}
 	}
 
+	// === ШАГ 7: Начислить баллы лояльности (если бронь подтверждена) ===
+
+	if newBooking.Status == "confirmed" {
+		newBooking.PointsEarned = awardLoyaltyPoints(ctx, newBooking, showtime)
+	}
+
 	// Успешно создано
 	utils.SuccessWithMessage(c, 201, "Booking created successfully", newBooking)
 }
 
+// bookingAmountDue - сумма брони, оплачиваемая основным методом (без подарочной карты, баллов и абонемента)
+func bookingAmountDue(booking models.Booking) float64 {
+	// По абонементу оплачиваются только дозаказы из бара
+	if booking.Payment.Method == "pass" {
+		return roundMoney(booking.ConcessionsTotal)
+	}
+	due := booking.TotalAmount - booking.Payment.GiftCardAmount - float64(booking.Payment.LoyaltyPoints)
+	return roundMoney(math.Max(0, due))
+}
+
 // GetMyBookings - получить мои брони
 func GetMyBookings(c *gin.Context) {
 	// Получить userID из контекста
@@ -360,15 +601,24 @@ func ConfirmBooking(c *gin.Context) {
 		return
 	}
 
-	if time.Now().After(booking.ExpiresAt) {
+	if booking.Status == "expired" {
+		utils.ErrorResponse(c, 400, "Booking has expired")
+		return
+	}
+
+	// Срок вышел, а фоновая проверка еще не дошла - закрыть бронь сразу
+	if booking.ExpiresAt != nil && time.Now().After(*booking.ExpiresAt) {
+		if _, err := expireBooking(ctx, booking); err != nil {
+			fmt.Printf("Warning: failed to expire booking %s: %v\n", booking.BookingNumber, err)
+		}
 		utils.ErrorResponse(c, 400, "Booking has expired")
 		return
 	}
 
-	// Обновить статус брони
-	_, err = bookingsCollection.UpdateOne(
+	// Обновить статус брони (только если она все еще ожидает оплаты)
+	result, err := bookingsCollection.UpdateOne(
 		ctx,
-		bson.M{"_id": bookingID},
+		bson.M{"_id": bookingID, "status": "pending"},
 		bson.M{
 			"$set": bson.M{
 				"status":                "confirmed",
@@ -377,12 +627,18 @@ func ConfirmBooking(c *gin.Context) {
 				"payment.transactionId": fmt.Sprintf("TXN-%s", time.Now().Format("20060102150405")),
 				"updatedAt":             time.Now(),
 			},
+			// Оплаченная бронь хранится до сеанса и после (check-in, отзывы, аналитика)
+			"$unset": bson.M{"expiresAt": ""},
 		},
 	)
 	if err != nil {
 		utils.ErrorResponse(c, 500, "Failed to confirm booking")
 		return
 	}
+	if result.MatchedCount == 0 {
+		utils.ErrorResponse(c, 409, "Booking is no longer pending")
+		return
+	}
 
 	// Создать транзакцию если оплата не через wallet
 	if booking.Payment.Method != "wallet" {
@@ -390,7 +646,7 @@ func ConfirmBooking(c *gin.Context) {
 		transaction := models.Transaction{
 			UserID:      userObjectID,
 			Type:        "booking",
-			Amount:      -booking.TotalAmount,
+			Amount:      -bookingAmountDue(booking),
 			BookingID:   bookingID,
 			Status:      "completed",
 			Description: fmt.Sprintf("Booking payment for %s", booking.BookingNumber),
@@ -399,6 +655,12 @@ func ConfirmBooking(c *gin.Context) {
 		transactionsCollection.InsertOne(ctx, transaction)
 	}
 
+	// Начислить баллы лояльности
+	var showtime models.Showtime
+	if err := config.GetCollection("showtimes").FindOne(ctx, bson.M{"_id": booking.ShowtimeID}).Decode(&showtime); err == nil {
+		awardLoyaltyPoints(ctx, booking, showtime)
+	}
+
 	// Получить обновленную бронь
 	var confirmedBooking models.Booking
 	bookingsCollection.FindOne(ctx, bson.M{"_id": bookingID}).Decode(&confirmedBooking)
@@ -440,6 +702,11 @@ func CancelBooking(c *gin.Context) {
 		return
 	}
 
+	if booking.Status == "expired" {
+		utils.ErrorResponse(c, 400, "Booking has expired")
+		return
+	}
+
 	// Проверить что сеанс еще не начался
 	showtimesCollection := config.GetCollection("showtimes")
 	var showtime models.Showtime
@@ -451,43 +718,32 @@ func CancelBooking(c *gin.Context) {
 		}
 	}
 
-	// ШАГ 1: Обновить статус брони
-	_, err = bookingsCollection.UpdateOne(
+	// ШАГ 1: Обновить статус брони (только если статус не изменился параллельно)
+	result, err := bookingsCollection.UpdateOne(
 		ctx,
-		bson.M{"_id": bookingID},
+		bson.M{"_id": bookingID, "status": booking.Status},
 		bson.M{
 			"$set": bson.M{
 				"status":    "cancelled",
 				"updatedAt": time.Now(),
 			},
+			"$unset": bson.M{"expiresAt": ""},
 		},
 	)
 	if err != nil {
 		utils.ErrorResponse(c, 500, "Failed to cancel booking")
 		return
 	}
-
-	// ШАГ 2: Освободить места в сеансе
-	for _, seat := range booking.Seats {
-		showtimesCollection.UpdateOne(
-			ctx,
-			bson.M{"_id": booking.ShowtimeID},
-			bson.M{
-				"$pull": bson.M{
-					"bookedSeats": bson.M{
-						"row":    seat.Row,
-						"number": seat.Number,
-					},
-				},
-				"$inc": bson.M{
-					"availableSeats": 1,
-				},
-			},
-		)
+	if result.MatchedCount == 0 {
+		utils.ErrorResponse(c, 409, "Booking status has changed, try again")
+		return
 	}
 
+	// ШАГ 2: Освободить места, вернуть промокод, оплату подарочной картой, баллами, абонементом и остатки бара
+	releaseBookingHolds(ctx, booking)
+
 	// ШАГ 3: Вернуть деньги (если оплачено)
-	if booking.Status == "confirmed" && booking.Payment.Status == "completed" {
+	if booking.Status == "confirmed" && booking.Payment.Status == "completed" && bookingAmountDue(booking) > 0 {
 		usersCollection := config.GetCollection("users")
 
 		_, err = usersCollection.UpdateOne(
@@ -495,7 +751,7 @@ func CancelBooking(c *gin.Context) {
 			bson.M{"_id": userObjectID},
 			bson.M{
 				"$inc": bson.M{
-					"wallet.balance": booking.TotalAmount,
+					"wallet.balance": bookingAmountDue(booking),
 				},
 			},
 		)
@@ -508,7 +764,7 @@ func CancelBooking(c *gin.Context) {
 		transaction := models.Transaction{
 			UserID:      userObjectID,
 			Type:        "refund",
-			Amount:      booking.TotalAmount,
+			Amount:      bookingAmountDue(booking),
 			BookingID:   bookingID,
 			Status:      "completed",
 			Description: fmt.Sprintf("Refund for cancelled booking %s", booking.BookingNumber),
@@ -517,6 +773,9 @@ func CancelBooking(c *gin.Context) {
 		transactionsCollection.InsertOne(ctx, transaction)
 	}
 
+	// ШАГ 4: Отменить начисленные за бронь баллы
+	reverseLoyaltyPoints(ctx, booking)
+
 	utils.SuccessWithMessage(c, 200, "Booking cancelled successfully. Refund processed.", gin.H{
 		"bookingId": bookingIDStr,
 		"cancelled": true,
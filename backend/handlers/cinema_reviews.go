@@ -0,0 +1,350 @@
+package handlers
+
+import (
+	"cinema-booking/config"
+	"cinema-booking/models"
+	"cinema-booking/utils"
+	"context"
+	"fmt"
+	"math"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// RecalculateCinemaRating - пересчитать Rating/TotalReviews и оценки по критериям кинотеатра
+// по всем его отзывам
+func RecalculateCinemaRating(ctx context.Context, cinemaID primitive.ObjectID) error {
+	pipeline := bson.A{
+		bson.M{"$match": bson.M{"cinemaId": cinemaID}},
+		bson.M{"$group": bson.M{
+			"_id":         nil,
+			"rating":      bson.M{"$avg": "$rating"},
+			"cleanliness": bson.M{"$avg": "$cleanliness"},
+			"sound":       bson.M{"$avg": "$sound"},
+			"seats":       bson.M{"$avg": "$seats"},
+			"staff":       bson.M{"$avg": "$staff"},
+			"count":       bson.M{"$sum": 1},
+		}},
+	}
+
+	cursor, err := config.GetCollection("cinema_reviews").Aggregate(ctx, pipeline)
+	if err != nil {
+		return err
+	}
+
+	var results []bson.M
+	if err := cursor.All(ctx, &results); err != nil {
+		return err
+	}
+
+	// Нет отзывов - рейтинг сбрасывается
+	round := func(value interface{}) float64 {
+		return math.Round(aggregateNumber(value)*10) / 10
+	}
+	var rating float64
+	var count int
+	subRatings := models.CinemaSubRatings{}
+	if len(results) > 0 {
+		row := results[0]
+		rating = round(row["rating"])
+		count = int(aggregateNumber(row["count"]))
+		subRatings = models.CinemaSubRatings{
+			Cleanliness: round(row["cleanliness"]),
+			Sound:       round(row["sound"]),
+			Seats:       round(row["seats"]),
+			Staff:       round(row["staff"]),
+			Count:       count,
+		}
+	}
+
+	_, err = config.GetCollection("cinemas").UpdateOne(ctx,
+		bson.M{"_id": cinemaID},
+		bson.M{"$set": bson.M{
+			"rating":       rating,
+			"totalReviews": count,
+			"subRatings":   subRatings,
+		}},
+	)
+	return err
+}
+
+// RecalculateCinemaRatings - пересчитать рейтинги всех кинотеатров (например, после удаления
+// выдуманных значений из старого seed). Возвращает число кинотеатров.
+func RecalculateCinemaRatings(ctx context.Context) (int, error) {
+	cinemaIDs, err := config.GetCollection("cinemas").Distinct(ctx, "_id", bson.M{})
+	if err != nil {
+		return 0, err
+	}
+
+	for i, id := range cinemaIDs {
+		cinemaID, ok := id.(primitive.ObjectID)
+		if !ok {
+			continue
+		}
+		if err := RecalculateCinemaRating(ctx, cinemaID); err != nil {
+			return i, err
+		}
+	}
+	return len(cinemaIDs), nil
+}
+
+// updateCinemaRating - пересчитать рейтинг после изменения отзыва (ошибка не отменяет изменение)
+func updateCinemaRating(ctx context.Context, cinemaID primitive.ObjectID) {
+	if err := RecalculateCinemaRating(ctx, cinemaID); err != nil {
+		fmt.Printf("Warning: failed to update cinema rating: %v\n", err)
+	}
+}
+
+// CinemaReviewRequest - отзыв о кинотеатре (все оценки 1-5)
+type CinemaReviewRequest struct {
+	Rating      float64 `json:"rating" binding:"required"`
+	Cleanliness float64 `json:"cleanliness" binding:"required"`
+	Sound       float64 `json:"sound" binding:"required"`
+	Seats       float64 `json:"seats" binding:"required"`
+	Staff       float64 `json:"staff" binding:"required"`
+	Comment     string  `json:"comment"`
+}
+
+// validateCinemaReviewRequest - проверить оценки и текст отзыва
+func validateCinemaReviewRequest(req *CinemaReviewRequest) string {
+	req.Comment = utils.SanitizeString(req.Comment)
+	for _, score := range []float64{req.Rating, req.Cleanliness, req.Sound, req.Seats, req.Staff} {
+		if score < 1 || score > 5 {
+			return "All scores must be between 1 and 5"
+		}
+	}
+	if len([]rune(req.Comment)) > maxReviewLength {
+		return "Comment is too long"
+	}
+	return ""
+}
+
+// GetCinemaReviews - отзывы о кинотеатре с пагинацией
+func GetCinemaReviews(c *gin.Context) {
+	cinemaID, err := primitive.ObjectIDFromHex(c.Param("id"))
+	if err != nil {
+		utils.ErrorResponse(c, 400, "Invalid cinema ID")
+		return
+	}
+
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "10"))
+	if page < 1 {
+		page = 1
+	}
+	if limit < 1 || limit > 100 {
+		limit = 10
+	}
+	skip := (page - 1) * limit
+
+	sortBy := c.DefaultQuery("sortBy", "createdAt")
+	if sortBy != "createdAt" && sortBy != "rating" {
+		sortBy = "createdAt"
+	}
+	sortDirection := -1
+	if c.DefaultQuery("sortOrder", "desc") == "asc" {
+		sortDirection = 1
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	reviewsCollection := config.GetCollection("cinema_reviews")
+	filter := bson.M{"cinemaId": cinemaID}
+
+	cursor, err := reviewsCollection.Find(ctx, filter, options.Find().
+		SetSkip(int64(skip)).
+		SetLimit(int64(limit)).
+		SetSort(bson.D{{Key: sortBy, Value: sortDirection}, {Key: "_id", Value: -1}}))
+	if err != nil {
+		utils.ErrorResponse(c, 500, "Failed to fetch reviews")
+		return
+	}
+	defer cursor.Close(ctx)
+
+	reviews := []models.CinemaReview{}
+	if err = cursor.All(ctx, &reviews); err != nil {
+		utils.ErrorResponse(c, 500, "Failed to decode reviews")
+		return
+	}
+
+	total, err := reviewsCollection.CountDocuments(ctx, filter)
+	if err != nil {
+		utils.ErrorResponse(c, 500, "Failed to count reviews")
+		return
+	}
+
+	utils.PaginatedResponse(c, reviews, page, limit, int(total))
+}
+
+// CreateCinemaReview - оставить отзыв о кинотеатре (только посетители прошедших сеансов)
+func CreateCinemaReview(c *gin.Context) {
+	userID, _ := c.Get("userId")
+	userObjectID, _ := primitive.ObjectIDFromHex(userID.(string))
+
+	cinemaID, err := primitive.ObjectIDFromHex(c.Param("id"))
+	if err != nil {
+		utils.ErrorResponse(c, 400, "Invalid cinema ID")
+		return
+	}
+
+	var req CinemaReviewRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ErrorResponse(c, 400, "Invalid request data: "+err.Error())
+		return
+	}
+
+	if msg := validateCinemaReviewRequest(&req); msg != "" {
+		utils.ErrorResponse(c, 400, msg)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	count, err := config.GetCollection("cinemas").CountDocuments(ctx, bson.M{"_id": cinemaID})
+	if err != nil || count == 0 {
+		utils.ErrorResponse(c, 404, "Cinema not found")
+		return
+	}
+
+	attended, err := hasAttendedShowtime(ctx, userObjectID, bson.M{"cinemaId": cinemaID})
+	if err != nil {
+		utils.ErrorResponse(c, 500, "Failed to verify booking history")
+		return
+	}
+	if !attended {
+		utils.ErrorResponse(c, 403, "Only visitors with a confirmed booking for a past showtime can review this cinema")
+		return
+	}
+
+	var user models.User
+	config.GetCollection("users").FindOne(ctx, bson.M{"_id": userObjectID}).Decode(&user)
+
+	review := models.CinemaReview{
+		CinemaID:    cinemaID,
+		UserID:      userObjectID,
+		UserName:    user.FullName,
+		Rating:      req.Rating,
+		Cleanliness: req.Cleanliness,
+		Sound:       req.Sound,
+		Seats:       req.Seats,
+		Staff:       req.Staff,
+		Comment:     req.Comment,
+		CreatedAt:   time.Now(),
+		UpdatedAt:   time.Now(),
+	}
+
+	// Уникальный индекс (cinemaId, userId) - один отзыв на пользователя
+	result, err := config.GetCollection("cinema_reviews").InsertOne(ctx, review)
+	if mongo.IsDuplicateKeyError(err) {
+		utils.ErrorResponse(c, 409, "You have already reviewed this cinema")
+		return
+	}
+	if err != nil {
+		utils.ErrorResponse(c, 500, "Failed to create review")
+		return
+	}
+
+	review.ID = result.InsertedID.(primitive.ObjectID)
+	updateCinemaRating(ctx, cinemaID)
+
+	utils.SuccessWithMessage(c, 201, "Review added successfully", review)
+}
+
+// UpdateCinemaReview - изменить свой отзыв о кинотеатре
+func UpdateCinemaReview(c *gin.Context) {
+	userID, _ := c.Get("userId")
+	userObjectID, _ := primitive.ObjectIDFromHex(userID.(string))
+
+	cinemaID, err := primitive.ObjectIDFromHex(c.Param("id"))
+	if err != nil {
+		utils.ErrorResponse(c, 400, "Invalid cinema ID")
+		return
+	}
+
+	var req CinemaReviewRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ErrorResponse(c, 400, "Invalid request data: "+err.Error())
+		return
+	}
+
+	if msg := validateCinemaReviewRequest(&req); msg != "" {
+		utils.ErrorResponse(c, 400, msg)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	// Прежняя версия нужна для ответа (имя автора, дата создания)
+	var oldReview models.CinemaReview
+	err = config.GetCollection("cinema_reviews").FindOneAndUpdate(ctx,
+		bson.M{"cinemaId": cinemaID, "userId": userObjectID},
+		bson.M{"$set": bson.M{
+			"rating":      req.Rating,
+			"cleanliness": req.Cleanliness,
+			"sound":       req.Sound,
+			"seats":       req.Seats,
+			"staff":       req.Staff,
+			"comment":     req.Comment,
+			"updatedAt":   time.Now(),
+		}},
+		options.FindOneAndUpdate().SetReturnDocument(options.Before),
+	).Decode(&oldReview)
+	if err != nil {
+		utils.ErrorResponse(c, 404, "Review not found")
+		return
+	}
+
+	review := oldReview
+	review.Rating = req.Rating
+	review.Cleanliness = req.Cleanliness
+	review.Sound = req.Sound
+	review.Seats = req.Seats
+	review.Staff = req.Staff
+	review.Comment = req.Comment
+	review.UpdatedAt = time.Now()
+
+	updateCinemaRating(ctx, cinemaID)
+
+	utils.SuccessWithMessage(c, 200, "Review updated successfully", review)
+}
+
+// DeleteCinemaReview - удалить свой отзыв о кинотеатре
+func DeleteCinemaReview(c *gin.Context) {
+	userID, _ := c.Get("userId")
+	userObjectID, _ := primitive.ObjectIDFromHex(userID.(string))
+
+	cinemaIDStr := c.Param("id")
+	cinemaID, err := primitive.ObjectIDFromHex(cinemaIDStr)
+	if err != nil {
+		utils.ErrorResponse(c, 400, "Invalid cinema ID")
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	var oldReview models.CinemaReview
+	err = config.GetCollection("cinema_reviews").FindOneAndDelete(ctx,
+		bson.M{"cinemaId": cinemaID, "userId": userObjectID},
+	).Decode(&oldReview)
+	if err != nil {
+		utils.ErrorResponse(c, 404, "Review not found")
+		return
+	}
+
+	updateCinemaRating(ctx, cinemaID)
+
+	utils.SuccessWithMessage(c, 200, "Review deleted successfully", gin.H{
+		"cinemaId": cinemaIDStr,
+		"deleted":  true,
+	})
+}
@@ -5,11 +5,13 @@ import (
 	"cinema-booking/models"
 	"cinema-booking/utils"
 	"context"
+	"fmt"
 	"strconv"
 	"time"
 
 	"github.com/gin-gonic/gin"
 	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
 	"go.mongodb.org/mongo-driver/mongo/options"
 )
 
@@ -41,6 +43,21 @@ func GetCinemas(c *gin.Context) {
 	lngStr := c.Query("lng")                 // ?lng=76.8512
 	maxDistanceStr := c.Query("maxDistance") // ?maxDistance=5000 (метры)
 
+	// Сортировка: name (по умолчанию), rating, totalReviews
+	sortBy := c.DefaultQuery("sortBy", "name") // ?sortBy=rating
+	if sortBy != "name" && sortBy != "rating" && sortBy != "totalReviews" {
+		utils.ErrorResponse(c, 400, "Invalid sortBy. Use: name, rating, or totalReviews")
+		return
+	}
+	defaultOrder := "desc" // лучшие кинотеатры первыми
+	if sortBy == "name" {
+		defaultOrder = "asc"
+	}
+	sortDirection := -1
+	if c.DefaultQuery("sortOrder", defaultOrder) == "asc" { // ?sortOrder=asc
+		sortDirection = 1
+	}
+
 	// === ПОСТРОЕНИЕ ФИЛЬТРА ===
 
 	filter := bson.M{}
@@ -50,6 +67,11 @@ func GetCinemas(c *gin.Context) {
 		filter["city"] = city
 	}
 
+	// Фильтр по рейтингу
+	if minRating, err := strconv.ParseFloat(c.Query("minRating"), 64); err == nil {
+		filter["rating"] = bson.M{"$gte": minRating}
+	}
+
 	// Геопоиск
 	var useGeoQuery bool
 	var geoQuery bson.M
@@ -101,9 +123,14 @@ func GetCinemas(c *gin.Context) {
 			}},
 		}
 
-		// Добавить фильтр по городу если есть
-		if city != "" {
-			pipeline = append(pipeline, bson.M{"$match": bson.M{"city": city}})
+		// Добавить фильтр по городу и рейтингу если есть
+		if len(filter) > 0 {
+			pipeline = append(pipeline, bson.M{"$match": filter})
+		}
+
+		// По умолчанию - по расстоянию, иначе по выбранному полю
+		if c.Query("sortBy") != "" {
+			pipeline = append(pipeline, bson.M{"$sort": bson.D{{Key: sortBy, Value: sortDirection}, {Key: "distance", Value: 1}}})
 		}
 
 		// Пагинация
@@ -134,8 +161,8 @@ func GetCinemas(c *gin.Context) {
 				"spherical":     true,
 			}},
 		}
-		if city != "" {
-			countPipeline = append(countPipeline, bson.M{"$match": bson.M{"city": city}})
+		if len(filter) > 0 {
+			countPipeline = append(countPipeline, bson.M{"$match": filter})
 		}
 		countPipeline = append(countPipeline, bson.M{"$count": "total"})
 
@@ -153,7 +180,7 @@ func GetCinemas(c *gin.Context) {
 		findOptions := options.Find()
 		findOptions.SetSkip(int64(skip))
 		findOptions.SetLimit(int64(limit))
-		findOptions.SetSort(bson.D{{Key: "name", Value: 1}}) // Сортировка по имени
+		findOptions.SetSort(bson.D{{Key: sortBy, Value: sortDirection}, {Key: "name", Value: 1}})
 
 		cursor, err := cinemasCollection.Find(ctx, filter, findOptions)
 		if err != nil {
@@ -189,3 +216,116 @@ func GetCinemas(c *gin.Context) {
 
 	utils.PaginatedResponse(c, cinemas, page, limit, int(total))
 }
+
+// UploadCinemaImage - загрузить фото кинотеатра (admin only).
+// Сохраняются оригинал и уменьшенные варианты, в Cinema.Images добавляется URL оригинала.
+func UploadCinemaImage(c *gin.Context) {
+	cinemaID, err := primitive.ObjectIDFromHex(c.Param("id"))
+	if err != nil {
+		utils.ErrorResponse(c, 400, "Invalid cinema ID")
+		return
+	}
+
+	file, header, contentType, reader, ok := receiveUpload(c, config.AppConfig.MaxUploadSize, imageContentTypes)
+	if !ok {
+		return
+	}
+	defer file.Close()
+
+	data, img, ok := readUploadedImage(c, reader, contentType)
+	if !ok {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	cinemasCollection := config.GetCollection("cinemas")
+
+	count, err := cinemasCollection.CountDocuments(ctx, bson.M{"_id": cinemaID})
+	if err != nil || count == 0 {
+		utils.ErrorResponse(c, 404, "Cinema not found")
+		return
+	}
+
+	bucket, err := config.GetGridFSBucket()
+	if err != nil {
+		utils.ErrorResponse(c, 500, "Failed to open file storage")
+		return
+	}
+
+	fileID, err := storeImage(bucket, header.Filename, data, img, bson.M{
+		"contentType": contentType,
+		"kind":        "cinema_image",
+		"cinemaId":    cinemaID,
+	})
+	if err != nil {
+		utils.ErrorResponse(c, 500, "Failed to store file")
+		return
+	}
+
+	result, err := cinemasCollection.UpdateOne(ctx,
+		bson.M{"_id": cinemaID},
+		bson.M{"$push": bson.M{"images": fileURL(fileID)}},
+	)
+	if err != nil || result.MatchedCount == 0 {
+		if delErr := deleteStoredFile(ctx, bucket, fileID); delErr != nil {
+			fmt.Printf("Warning: failed to delete orphaned file %s: %v\n", fileID.Hex(), delErr)
+		}
+		utils.ErrorResponse(c, 500, "Failed to update cinema")
+		return
+	}
+
+	utils.SuccessWithMessage(c, 201, "Image uploaded successfully", gin.H{
+		"cinemaId":    cinemaID.Hex(),
+		"fileId":      fileID.Hex(),
+		"url":         fileURL(fileID),
+		"contentType": contentType,
+		"size":        header.Size,
+	})
+}
+
+// DeleteCinemaImage - удалить фото кинотеатра вместе с вариантами (admin only)
+func DeleteCinemaImage(c *gin.Context) {
+	cinemaID, err := primitive.ObjectIDFromHex(c.Param("id"))
+	if err != nil {
+		utils.ErrorResponse(c, 400, "Invalid cinema ID")
+		return
+	}
+
+	fileID, err := primitive.ObjectIDFromHex(c.Param("fileId"))
+	if err != nil {
+		utils.ErrorResponse(c, 400, "Invalid file ID")
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	result, err := config.GetCollection("cinemas").UpdateOne(ctx,
+		bson.M{"_id": cinemaID, "images": fileURL(fileID)},
+		bson.M{"$pull": bson.M{"images": fileURL(fileID)}},
+	)
+	if err != nil {
+		utils.ErrorResponse(c, 500, "Failed to update cinema")
+		return
+	}
+	if result.MatchedCount == 0 {
+		utils.ErrorResponse(c, 404, "Image not found")
+		return
+	}
+
+	bucket, err := config.GetGridFSBucket()
+	if err == nil {
+		err = deleteStoredFile(ctx, bucket, fileID)
+	}
+	if err != nil {
+		fmt.Printf("Warning: failed to delete cinema image %s: %v\n", fileID.Hex(), err)
+	}
+
+	utils.SuccessWithMessage(c, 200, "Image deleted successfully", gin.H{
+		"cinemaId": cinemaID.Hex(),
+		"fileId":   fileID.Hex(),
+		"deleted":  true,
+	})
+}
@@ -0,0 +1,730 @@
+package handlers
+
+import (
+	"cinema-booking/config"
+	"cinema-booking/models"
+	"cinema-booking/utils"
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// Ограничения заказа из бара
+const (
+	maxConcessionQuantity = 20
+	// Выдача заказа: не раньше чем за час до начала и не позже конца сеанса
+	pickupWindowBefore = 60 * time.Minute
+	// Время выдачи по умолчанию - за 15 минут до начала сеанса
+	defaultPickupBefore = 15 * time.Minute
+)
+
+// ConcessionRequest - позиция бара в запросе
+type ConcessionRequest struct {
+	ItemID   string `json:"itemId" binding:"required"`
+	Quantity int    `json:"quantity"`
+}
+
+// buildConcessionLines - найти позиции кинотеатра и посчитать сумму заказа
+func buildConcessionLines(ctx context.Context, cinemaID primitive.ObjectID, reqs []ConcessionRequest) ([]models.BookingConcession, float64, error) {
+	// Объединить повторяющиеся позиции
+	quantities := make(map[primitive.ObjectID]int)
+	var order []primitive.ObjectID
+	for _, req := range reqs {
+		itemID, err := primitive.ObjectIDFromHex(req.ItemID)
+		if err != nil {
+			return nil, 0, fmt.Errorf("Invalid concession item ID %q", req.ItemID)
+		}
+		if req.Quantity == 0 {
+			req.Quantity = 1
+		}
+		if req.Quantity < 1 {
+			return nil, 0, errors.New("Concession quantity must be positive")
+		}
+		if _, ok := quantities[itemID]; !ok {
+			order = append(order, itemID)
+		}
+		quantities[itemID] += req.Quantity
+		if quantities[itemID] > maxConcessionQuantity {
+			return nil, 0, fmt.Errorf("Maximum %d of each concession item per order", maxConcessionQuantity)
+		}
+	}
+
+	cursor, err := config.GetCollection("concessions").Find(ctx, bson.M{
+		"_id":      bson.M{"$in": order},
+		"cinemaId": cinemaID,
+		"isActive": true,
+	})
+	if err != nil {
+		return nil, 0, errors.New("Failed to load concessions")
+	}
+	defer cursor.Close(ctx)
+
+	var items []models.ConcessionItem
+	if err := cursor.All(ctx, &items); err != nil {
+		return nil, 0, errors.New("Failed to load concessions")
+	}
+
+	itemsByID := make(map[primitive.ObjectID]models.ConcessionItem)
+	for _, item := range items {
+		itemsByID[item.ID] = item
+	}
+
+	var lines []models.BookingConcession
+	var total float64
+	for _, itemID := range order {
+		item, ok := itemsByID[itemID]
+		if !ok {
+			return nil, 0, fmt.Errorf("Concession item %s is not available at this cinema", itemID.Hex())
+		}
+		qty := quantities[itemID]
+		line := models.BookingConcession{
+			ItemID:     item.ID,
+			Name:       item.Name,
+			Quantity:   qty,
+			UnitPrice:  item.Price,
+			Total:      roundMoney(item.Price * float64(qty)),
+			ComboItems: item.ComboItems,
+		}
+		lines = append(lines, line)
+		total += line.Total
+	}
+
+	return lines, roundMoney(total), nil
+}
+
+// concessionStockDemand - сколько единиц каждой позиции списать (комбо раскладываются на состав)
+func concessionStockDemand(lines []models.BookingConcession) map[primitive.ObjectID]int {
+	demand := make(map[primitive.ObjectID]int)
+	for _, line := range lines {
+		demand[line.ItemID] += line.Quantity
+		for _, component := range line.ComboItems {
+			demand[component.ItemID] += component.Quantity * line.Quantity
+		}
+	}
+	return demand
+}
+
+// adjustConcessionStock - атомарно изменить остаток позиции (только для позиций с учетом остатков).
+// При списании (delta < 0) условие stock >= -delta не дает уйти в минус.
+func adjustConcessionStock(ctx context.Context, itemID primitive.ObjectID, delta int) (bool, error) {
+	filter := bson.M{"_id": itemID}
+	if delta < 0 {
+		filter["$or"] = bson.A{
+			bson.M{"trackStock": false},
+			bson.M{"stock": bson.M{"$gte": -delta}},
+		}
+	}
+
+	result, err := config.GetCollection("concessions").UpdateOne(ctx, filter, bson.A{
+		bson.M{"$set": bson.M{
+			"stock":     bson.M{"$cond": bson.A{"$trackStock", bson.M{"$add": bson.A{"$stock", delta}}, "$stock"}},
+			"updatedAt": time.Now(),
+		}},
+	})
+	if err != nil {
+		return false, err
+	}
+	return result.MatchedCount > 0, nil
+}
+
+// reserveConcessionStock - списать остатки под заказ (все или ничего)
+func reserveConcessionStock(ctx context.Context, lines []models.BookingConcession) error {
+	reserved := make(map[primitive.ObjectID]int)
+	for itemID, qty := range concessionStockDemand(lines) {
+		ok, err := adjustConcessionStock(ctx, itemID, -qty)
+		if err != nil || !ok {
+			// Вернуть уже списанное
+			for reservedID, reservedQty := range reserved {
+				adjustConcessionStock(ctx, reservedID, reservedQty)
+			}
+			if err != nil {
+				return errors.New("Failed to reserve concessions")
+			}
+			return errors.New("Some concession items are out of stock")
+		}
+		reserved[itemID] = qty
+	}
+	return nil
+}
+
+// releaseConcessionStock - вернуть остатки (отмена брони)
+func releaseConcessionStock(ctx context.Context, lines []models.BookingConcession) {
+	for itemID, qty := range concessionStockDemand(lines) {
+		if _, err := adjustConcessionStock(ctx, itemID, qty); err != nil {
+			fmt.Printf("Warning: failed to return concession stock: %v\n", err)
+		}
+	}
+}
+
+// concessionPickupTime - проверить время выдачи заказа (по умолчанию - до начала сеанса)
+func concessionPickupTime(pickup *time.Time, showtime models.Showtime) (time.Time, error) {
+	if pickup == nil || pickup.IsZero() {
+		defaultPickup := showtime.StartTime.Add(-defaultPickupBefore)
+		if defaultPickup.Before(time.Now()) {
+			defaultPickup = time.Now()
+		}
+		return defaultPickup, nil
+	}
+
+	earliest := showtime.StartTime.Add(-pickupWindowBefore)
+	latest := showtime.EndTime
+	if latest.IsZero() {
+		latest = showtime.StartTime
+	}
+
+	if pickup.Before(time.Now()) {
+		return time.Time{}, errors.New("Pickup time is in the past")
+	}
+	if pickup.Before(earliest) || pickup.After(latest) {
+		return time.Time{}, fmt.Errorf("Pickup time must be between %s and %s",
+			earliest.Format("15:04"), latest.Format("15:04"))
+	}
+	return *pickup, nil
+}
+
+// validateConcessionItem - проверить позицию каталога, вернуть текст ошибки
+func validateConcessionItem(ctx context.Context, item *models.ConcessionItem) string {
+	item.Name = utils.SanitizeString(item.Name)
+	item.Description = utils.SanitizeString(item.Description)
+
+	if item.CinemaID.IsZero() {
+		return "cinemaId is required"
+	}
+	if item.Name == "" {
+		return "Item name is required"
+	}
+	if item.Price <= 0 {
+		return "price must be greater than 0"
+	}
+	if item.Stock < 0 {
+		return "stock must not be negative"
+	}
+
+	if len(item.ComboItems) == 0 {
+		if item.Category == "combo" {
+			return "A combo must contain at least one item"
+		}
+		if item.Category != "snack" && item.Category != "drink" {
+			return "Invalid category. Use: snack, drink, or combo"
+		}
+		return ""
+	}
+
+	// Состав комбо: позиции того же кинотеатра, без вложенных комбо
+	item.Category = "combo"
+	var componentIDs []primitive.ObjectID
+	for _, component := range item.ComboItems {
+		if component.Quantity < 1 {
+			return "Combo item quantity must be positive"
+		}
+		componentIDs = append(componentIDs, component.ItemID)
+	}
+
+	count, err := config.GetCollection("concessions").CountDocuments(ctx, bson.M{
+		"_id":        bson.M{"$in": componentIDs},
+		"cinemaId":   item.CinemaID,
+		"comboItems": bson.M{"$in": bson.A{nil, bson.A{}}},
+	})
+	if err != nil {
+		return "Failed to validate combo items"
+	}
+	if int(count) != len(componentIDs) {
+		return "Combo items must be existing non-combo items of the same cinema"
+	}
+
+	return ""
+}
+
+// GetCinemaConcessions - меню бара кинотеатра
+func GetCinemaConcessions(c *gin.Context) {
+	cinemaID, err := primitive.ObjectIDFromHex(c.Param("id"))
+	if err != nil {
+		utils.ErrorResponse(c, 400, "Invalid cinema ID")
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	filter := bson.M{"cinemaId": cinemaID, "isActive": true}
+	if category := c.Query("category"); category != "" {
+		filter["category"] = category
+	}
+
+	cursor, err := config.GetCollection("concessions").Find(ctx, filter,
+		options.Find().SetSort(bson.D{{Key: "category", Value: 1}, {Key: "name", Value: 1}}))
+	if err != nil {
+		utils.ErrorResponse(c, 500, "Failed to fetch concessions")
+		return
+	}
+	defer cursor.Close(ctx)
+
+	items := []models.ConcessionItem{}
+	if err = cursor.All(ctx, &items); err != nil {
+		utils.ErrorResponse(c, 500, "Failed to decode concessions")
+		return
+	}
+
+	utils.SuccessResponse(c, 200, items)
+}
+
+// CreateConcessionItem - добавить позицию в меню бара (admin, cinema_manager)
+func CreateConcessionItem(c *gin.Context) {
+	var item models.ConcessionItem
+	if err := c.ShouldBindJSON(&item); err != nil {
+		utils.ErrorResponse(c, 400, "Invalid request data: "+err.Error())
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	if msg := validateConcessionItem(ctx, &item); msg != "" {
+		utils.ErrorResponse(c, 400, msg)
+		return
+	}
+
+	item.ID = primitive.NilObjectID
+	item.IsActive = true
+	item.CreatedAt = time.Now()
+	item.UpdatedAt = time.Now()
+
+	result, err := config.GetCollection("concessions").InsertOne(ctx, item)
+	if err != nil {
+		utils.ErrorResponse(c, 500, "Failed to create concession item")
+		return
+	}
+
+	item.ID = result.InsertedID.(primitive.ObjectID)
+
+	utils.SuccessWithMessage(c, 201, "Concession item created successfully", item)
+}
+
+// UpdateConcessionItemRequest - изменение позиции; isActive не передан - видимость в меню не меняется
+type UpdateConcessionItemRequest struct {
+	models.ConcessionItem
+	IsActive *bool `json:"isActive"`
+}
+
+// UpdateConcessionItem - изменить позицию меню (admin, cinema_manager)
+// Остаток меняется только через restock, чтобы не затереть параллельные списания.
+func UpdateConcessionItem(c *gin.Context) {
+	itemID, err := primitive.ObjectIDFromHex(c.Param("id"))
+	if err != nil {
+		utils.ErrorResponse(c, 400, "Invalid concession item ID")
+		return
+	}
+
+	var req UpdateConcessionItemRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ErrorResponse(c, 400, "Invalid request data: "+err.Error())
+		return
+	}
+	item := req.ConcessionItem
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	concessionsCollection := config.GetCollection("concessions")
+
+	var existing models.ConcessionItem
+	if err := concessionsCollection.FindOne(ctx, bson.M{"_id": itemID}).Decode(&existing); err != nil {
+		utils.ErrorResponse(c, 404, "Concession item not found")
+		return
+	}
+
+	item.CinemaID = existing.CinemaID
+	item.Stock = existing.Stock
+	item.IsActive = existing.IsActive
+	if msg := validateConcessionItem(ctx, &item); msg != "" {
+		utils.ErrorResponse(c, 400, msg)
+		return
+	}
+	for _, component := range item.ComboItems {
+		if component.ItemID == itemID {
+			utils.ErrorResponse(c, 400, "A combo cannot contain itself")
+			return
+		}
+	}
+
+	update := bson.M{
+		"name":        item.Name,
+		"category":    item.Category,
+		"description": item.Description,
+		"price":       item.Price,
+		"trackStock":  item.TrackStock,
+		"comboItems":  item.ComboItems,
+		"updatedAt":   time.Now(),
+	}
+	// Позиция скрывается из меню только явным isActive: false
+	if req.IsActive != nil {
+		update["isActive"] = *req.IsActive
+	}
+
+	_, err = concessionsCollection.UpdateOne(ctx, bson.M{"_id": itemID}, bson.M{"$set": update})
+	if err != nil {
+		utils.ErrorResponse(c, 500, "Failed to update concession item")
+		return
+	}
+
+	var updated models.ConcessionItem
+	concessionsCollection.FindOne(ctx, bson.M{"_id": itemID}).Decode(&updated)
+
+	utils.SuccessWithMessage(c, 200, "Concession item updated successfully", updated)
+}
+
+// RestockRequest - пополнение (или списание) остатка
+type RestockRequest struct {
+	Quantity int `json:"quantity" binding:"required"` // > 0 - поступление, < 0 - списание
+}
+
+// RestockConcessionItem - изменить остаток позиции (admin, cinema_manager)
+func RestockConcessionItem(c *gin.Context) {
+	itemID, err := primitive.ObjectIDFromHex(c.Param("id"))
+	if err != nil {
+		utils.ErrorResponse(c, 400, "Invalid concession item ID")
+		return
+	}
+
+	var req RestockRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ErrorResponse(c, 400, "Invalid request data: "+err.Error())
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	// $inc атомарен; списание не может увести остаток в минус
+	filter := bson.M{"_id": itemID}
+	if req.Quantity < 0 {
+		filter["stock"] = bson.M{"$gte": -req.Quantity}
+	}
+
+	var item models.ConcessionItem
+	err = config.GetCollection("concessions").FindOneAndUpdate(ctx, filter,
+		bson.M{
+			"$inc": bson.M{"stock": req.Quantity},
+			"$set": bson.M{"trackStock": true, "updatedAt": time.Now()},
+		},
+		options.FindOneAndUpdate().SetReturnDocument(options.After),
+	).Decode(&item)
+	if err != nil {
+		utils.ErrorResponse(c, 404, "Concession item not found or insufficient stock")
+		return
+	}
+
+	utils.SuccessWithMessage(c, 200, "Stock updated successfully", item)
+}
+
+// DeleteConcessionItem - убрать позицию из меню (admin, cinema_manager)
+func DeleteConcessionItem(c *gin.Context) {
+	itemIDStr := c.Param("id")
+	itemID, err := primitive.ObjectIDFromHex(itemIDStr)
+	if err != nil {
+		utils.ErrorResponse(c, 400, "Invalid concession item ID")
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	// Soft delete - позиция остается в старых заказах
+	result, err := config.GetCollection("concessions").UpdateOne(ctx,
+		bson.M{"_id": itemID},
+		bson.M{"$set": bson.M{"isActive": false, "updatedAt": time.Now()}},
+	)
+	if err != nil {
+		utils.ErrorResponse(c, 500, "Failed to delete concession item")
+		return
+	}
+
+	if result.MatchedCount == 0 {
+		utils.ErrorResponse(c, 404, "Concession item not found")
+		return
+	}
+
+	utils.SuccessWithMessage(c, 200, "Concession item deleted successfully", gin.H{
+		"itemId":  itemIDStr,
+		"deleted": true,
+	})
+}
+
+// AddConcessionsRequest - дозаказ из бара к существующей брони
+type AddConcessionsRequest struct {
+	Items      []ConcessionRequest `json:"items" binding:"required,min=1"`
+	PickupTime *time.Time          `json:"pickupTime"`
+}
+
+// AddBookingConcessions - добавить заказ из бара к брони
+// Для неоплаченной брони сумма добавляется к оплате, для оплаченной - списывается с кошелька.
+func AddBookingConcessions(c *gin.Context) {
+	userID, _ := c.Get("userId")
+	userObjectID, _ := primitive.ObjectIDFromHex(userID.(string))
+
+	bookingID, err := primitive.ObjectIDFromHex(c.Param("id"))
+	if err != nil {
+		utils.ErrorResponse(c, 400, "Invalid booking ID")
+		return
+	}
+
+	var req AddConcessionsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ErrorResponse(c, 400, "Invalid request data: "+err.Error())
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	bookingsCollection := config.GetCollection("bookings")
+
+	var booking models.Booking
+	err = bookingsCollection.FindOne(ctx, bson.M{"_id": bookingID, "userId": userObjectID}).Decode(&booking)
+	if err != nil {
+		utils.ErrorResponse(c, 404, "Booking not found")
+		return
+	}
+
+	if booking.Status != "pending" && booking.Status != "confirmed" {
+		utils.ErrorResponse(c, 400, "Concessions can be added only to active bookings")
+		return
+	}
+
+	if booking.ConcessionStatus == "ready" || booking.ConcessionStatus == "collected" {
+		utils.ErrorResponse(c, 400, "The concession order has already been prepared")
+		return
+	}
+
+	var showtime models.Showtime
+	if err := config.GetCollection("showtimes").FindOne(ctx, bson.M{"_id": booking.ShowtimeID}).Decode(&showtime); err != nil {
+		utils.ErrorResponse(c, 404, "Showtime not found")
+		return
+	}
+
+	if time.Now().After(showtime.StartTime) {
+		utils.ErrorResponse(c, 400, "Showtime has already started")
+		return
+	}
+
+	lines, amount, err := buildConcessionLines(ctx, showtime.CinemaID, req.Items)
+	if err != nil {
+		utils.ErrorResponse(c, 400, err.Error())
+		return
+	}
+
+	pickupTime := booking.PickupTime
+	if req.PickupTime != nil || pickupTime == nil {
+		pickup, err := concessionPickupTime(req.PickupTime, showtime)
+		if err != nil {
+			utils.ErrorResponse(c, 400, err.Error())
+			return
+		}
+		pickupTime = &pickup
+	}
+
+	if err := reserveConcessionStock(ctx, lines); err != nil {
+		utils.ErrorResponse(c, 400, err.Error())
+		return
+	}
+
+	usersCollection := config.GetCollection("users")
+	paid := booking.Status == "confirmed"
+
+	// Оплаченная бронь: доплата с кошелька (атомарно)
+	if paid {
+		result, err := usersCollection.UpdateOne(ctx,
+			bson.M{"_id": userObjectID, "wallet.balance": bson.M{"$gte": amount}},
+			bson.M{
+				"$inc": bson.M{"wallet.balance": -amount},
+				"$set": bson.M{"updatedAt": time.Now()},
+			},
+		)
+		if err != nil || result.ModifiedCount == 0 {
+			releaseConcessionStock(ctx, lines)
+			utils.ErrorResponse(c, 400, fmt.Sprintf("Insufficient wallet balance. Required: %.2f KZT", amount))
+			return
+		}
+	}
+
+	// Статус брони в условии защищает от одновременной отмены
+	result, err := bookingsCollection.UpdateOne(ctx,
+		bson.M{"_id": bookingID, "status": booking.Status},
+		bson.M{
+			"$push": bson.M{"concessions": bson.M{"$each": lines}},
+			"$inc":  bson.M{"totalAmount": amount, "concessionsTotal": amount},
+			"$set": bson.M{
+				"pickupTime":       pickupTime,
+				"concessionStatus": "pending",
+				"updatedAt":        time.Now(),
+			},
+		},
+	)
+	if err != nil || result.ModifiedCount == 0 {
+		releaseConcessionStock(ctx, lines)
+		if paid {
+			usersCollection.UpdateOne(ctx, bson.M{"_id": userObjectID}, bson.M{"$inc": bson.M{"wallet.balance": amount}})
+		}
+		utils.ErrorResponse(c, 409, "Booking was changed, please try again")
+		return
+	}
+
+	if paid {
+		transaction := models.Transaction{
+			UserID:      userObjectID,
+			Type:        "booking",
+			Amount:      -amount,
+			BookingID:   bookingID,
+			Status:      "completed",
+			Description: fmt.Sprintf("Concessions for %s", booking.BookingNumber),
+			CreatedAt:   time.Now(),
+		}
+		if _, err := config.GetCollection("transactions").InsertOne(ctx, transaction); err != nil {
+			fmt.Printf("Warning: failed to create transaction record: %v\n", err)
+		}
+	}
+
+	var updated models.Booking
+	bookingsCollection.FindOne(ctx, bson.M{"_id": bookingID}).Decode(&updated)
+
+	utils.SuccessWithMessage(c, 200, "Concessions added successfully", updated)
+}
+
+// GetKitchenQueue - заказы бара на ближайшие сеансы кинотеатра (admin, cinema_manager)
+func GetKitchenQueue(c *gin.Context) {
+	cinemaID, err := primitive.ObjectIDFromHex(c.Query("cinemaId"))
+	if err != nil {
+		utils.ErrorResponse(c, 400, "cinemaId is required")
+		return
+	}
+
+	hours, _ := strconv.Atoi(c.DefaultQuery("hours", "3"))
+	if hours < 1 || hours > 24 {
+		hours = 3
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	// Сеансы, которые идут сейчас или начнутся в ближайшие часы
+	now := time.Now()
+	cursor, err := config.GetCollection("showtimes").Find(ctx, bson.M{
+		"cinemaId":  cinemaID,
+		"startTime": bson.M{"$lte": now.Add(time.Duration(hours) * time.Hour)},
+		"endTime":   bson.M{"$gte": now},
+	})
+	if err != nil {
+		utils.ErrorResponse(c, 500, "Failed to fetch showtimes")
+		return
+	}
+	var showtimes []models.Showtime
+	if err := cursor.All(ctx, &showtimes); err != nil {
+		utils.ErrorResponse(c, 500, "Failed to decode showtimes")
+		return
+	}
+
+	showtimesByID := make(map[primitive.ObjectID]models.Showtime)
+	showtimeIDs := []primitive.ObjectID{}
+	for _, showtime := range showtimes {
+		showtimesByID[showtime.ID] = showtime
+		showtimeIDs = append(showtimeIDs, showtime.ID)
+	}
+
+	cursor, err = config.GetCollection("bookings").Find(ctx,
+		bson.M{
+			"showtimeId":       bson.M{"$in": showtimeIDs},
+			"status":           "confirmed",
+			"concessionStatus": bson.M{"$in": bson.A{"pending", "preparing", "ready"}},
+		},
+		options.Find().SetSort(bson.D{{Key: "pickupTime", Value: 1}}),
+	)
+	if err != nil {
+		utils.ErrorResponse(c, 500, "Failed to fetch orders")
+		return
+	}
+	var bookings []models.Booking
+	if err := cursor.All(ctx, &bookings); err != nil {
+		utils.ErrorResponse(c, 500, "Failed to decode orders")
+		return
+	}
+
+	// Очередь заказов и сводка "что приготовить"
+	orders := []gin.H{}
+	toPrepare := make(map[string]int)
+	for _, booking := range bookings {
+		showtime := showtimesByID[booking.ShowtimeID]
+		orders = append(orders, gin.H{
+			"bookingId":     booking.ID,
+			"bookingNumber": booking.BookingNumber,
+			"showtimeId":    booking.ShowtimeID,
+			"startTime":     showtime.StartTime,
+			"pickupTime":    booking.PickupTime,
+			"status":        booking.ConcessionStatus,
+			"items":         booking.Concessions,
+		})
+		if booking.ConcessionStatus == "ready" {
+			continue
+		}
+		for _, line := range booking.Concessions {
+			toPrepare[line.Name] += line.Quantity
+		}
+	}
+
+	utils.SuccessResponse(c, 200, gin.H{
+		"orders":    orders,
+		"toPrepare": toPrepare,
+		"total":     len(orders),
+	})
+}
+
+// UpdateConcessionStatusRequest - смена статуса заказа бара
+type UpdateConcessionStatusRequest struct {
+	Status string `json:"status" binding:"required"` // "preparing", "ready", "collected"
+}
+
+// UpdateConcessionStatus - отметить заказ бара как готовящийся, готовый или выданный (admin, cinema_manager)
+func UpdateConcessionStatus(c *gin.Context) {
+	bookingID, err := primitive.ObjectIDFromHex(c.Param("id"))
+	if err != nil {
+		utils.ErrorResponse(c, 400, "Invalid booking ID")
+		return
+	}
+
+	var req UpdateConcessionStatusRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ErrorResponse(c, 400, "Invalid request data: "+err.Error())
+		return
+	}
+
+	if req.Status != "preparing" && req.Status != "ready" && req.Status != "collected" {
+		utils.ErrorResponse(c, 400, "Invalid status. Use: preparing, ready, or collected")
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	var booking models.Booking
+	err = config.GetCollection("bookings").FindOneAndUpdate(ctx,
+		bson.M{
+			"_id":              bookingID,
+			"status":           "confirmed",
+			"concessionStatus": bson.M{"$exists": true, "$ne": "collected"},
+		},
+		bson.M{"$set": bson.M{"concessionStatus": req.Status, "updatedAt": time.Now()}},
+		options.FindOneAndUpdate().SetReturnDocument(options.After),
+	).Decode(&booking)
+	if err != nil {
+		utils.ErrorResponse(c, 404, "Active concession order not found")
+		return
+	}
+
+	utils.SuccessWithMessage(c, 200, "Concession order updated successfully", booking)
+}
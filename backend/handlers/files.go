@@ -0,0 +1,641 @@
+package handlers
+
+import (
+	"bytes"
+	"cinema-booking/config"
+	"cinema-booking/models"
+	"cinema-booking/utils"
+	"context"
+	"errors"
+	"fmt"
+	"image"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/gridfs"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// Лимит для трейлеров; постеры ограничены config.AppConfig.MaxUploadSize
+const maxTrailerSize int64 = 500 << 20
+
+// mediaKind - тип загружаемого медиафайла фильма
+type mediaKind struct {
+	Name         string          // "poster", "trailer"
+	Field        string          // поле фильма с ID файла в GridFS
+	AllowedTypes map[string]bool // MIME, определенный по содержимому файла
+	MaxSize      func() int64
+	IsImage      bool // генерировать уменьшенные варианты
+}
+
+// Допустимые форматы изображений (постеры, фото кинотеатров)
+var imageContentTypes = map[string]bool{
+	"image/jpeg": true,
+	"image/png":  true,
+	"image/webp": true,
+	"image/gif":  true,
+}
+
+var (
+	posterMedia = mediaKind{
+		Name:         "poster",
+		Field:        "posterFileId",
+		AllowedTypes: imageContentTypes,
+		MaxSize:      func() int64 { return config.AppConfig.MaxUploadSize },
+		IsImage:      true,
+	}
+	trailerMedia = mediaKind{
+		Name:  "trailer",
+		Field: "trailerFileId",
+		AllowedTypes: map[string]bool{
+			"video/mp4":  true,
+			"video/webm": true,
+		},
+		MaxSize: func() int64 { return maxTrailerSize },
+	}
+)
+
+// storedVariant - уменьшенная версия изображения (отдельный файл в GridFS)
+type storedVariant struct {
+	FileID primitive.ObjectID `bson:"fileId"`
+	Name   string             `bson:"name"` // "thumbnail", "card", "full"
+	Width  int                `bson:"width"`
+	Height int                `bson:"height"`
+	Format string             `bson:"format"` // "jpeg", "webp"
+	Size   int64              `bson:"size,omitempty"`
+}
+
+// storedFileMetadata - metadata файла в GridFS
+type storedFileMetadata struct {
+	ContentType string          `bson:"contentType"`
+	Kind        string          `bson:"kind"`
+	Variants    []storedVariant `bson:"variants,omitempty"`
+}
+
+// fileURL - публичный URL файла из GridFS
+func fileURL(fileID primitive.ObjectID) string {
+	return "/api/files/" + fileID.Hex()
+}
+
+// sniffContentType - определить MIME по первым 512 байтам (заголовку клиента не доверяем).
+// Возвращает reader, который снова отдает прочитанные байты.
+func sniffContentType(r io.Reader) (string, io.Reader, error) {
+	head := make([]byte, 512)
+	n, err := io.ReadFull(r, head)
+	if err != nil && err != io.ErrUnexpectedEOF && err != io.EOF {
+		return "", nil, err
+	}
+	head = head[:n]
+
+	contentType := http.DetectContentType(head)
+	if i := strings.Index(contentType, ";"); i >= 0 {
+		contentType = contentType[:i]
+	}
+	return contentType, io.MultiReader(bytes.NewReader(head), r), nil
+}
+
+// receiveUpload - принять multipart поле "file", проверить размер и тип по содержимому.
+// При ошибке ответ уже отправлен и ok = false; file закрывает вызывающий.
+func receiveUpload(c *gin.Context, maxSize int64, allowed map[string]bool) (file multipart.File, header *multipart.FileHeader, contentType string, reader io.Reader, ok bool) {
+	// Ограничить тело запроса (запас на заголовки multipart)
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, maxSize+1<<20)
+
+	file, header, err := c.Request.FormFile("file")
+	if err != nil {
+		var maxBytesErr *http.MaxBytesError
+		if errors.As(err, &maxBytesErr) {
+			utils.ErrorResponse(c, 413, fmt.Sprintf("File is too large (max %d MB)", maxSize>>20))
+			return nil, nil, "", nil, false
+		}
+		utils.ErrorResponse(c, 400, "File is required (multipart field \"file\")")
+		return nil, nil, "", nil, false
+	}
+
+	fail := func(status int, message string) {
+		file.Close()
+		utils.ErrorResponse(c, status, message)
+	}
+
+	if header.Size > maxSize {
+		fail(413, fmt.Sprintf("File is too large (max %d MB)", maxSize>>20))
+		return nil, nil, "", nil, false
+	}
+	if header.Size == 0 {
+		fail(400, "File is empty")
+		return nil, nil, "", nil, false
+	}
+
+	contentType, reader, err = sniffContentType(file)
+	if err != nil {
+		fail(400, "Failed to read file")
+		return nil, nil, "", nil, false
+	}
+	if !allowed[contentType] {
+		fail(415, "Unsupported file type: "+contentType)
+		return nil, nil, "", nil, false
+	}
+
+	return file, header, contentType, io.LimitReader(reader, maxSize), true
+}
+
+// readUploadedImage - прочитать изображение и проверить, что оно настоящее
+// и не превышает лимит пикселей. При ошибке ответ уже отправлен.
+func readUploadedImage(c *gin.Context, reader io.Reader, contentType string) ([]byte, image.Image, bool) {
+	data, err := io.ReadAll(reader)
+	if err != nil {
+		utils.ErrorResponse(c, 400, "Failed to read file")
+		return nil, nil, false
+	}
+
+	img, err := utils.DecodeImage(data, contentType)
+	if err != nil {
+		utils.ErrorResponse(c, 422, "Invalid image: "+err.Error())
+		return nil, nil, false
+	}
+	return data, img, true
+}
+
+// storeImage - сохранить оригинал и уменьшенные варианты (thumbnail/card/full в JPEG,
+// плюс WebP там, где он меньше JPEG). Варианты сохраняются первыми, их список записывается
+// в metadata оригинала.
+func storeImage(bucket *gridfs.Bucket, filename string, data []byte, img image.Image, metadata bson.M) (primitive.ObjectID, error) {
+	originalID := primitive.NewObjectID()
+	variants := []storedVariant{}
+
+	// Удалить уже сохраненные варианты при ошибке
+	rollback := func() {
+		for _, v := range variants {
+			if err := bucket.Delete(v.FileID); err != nil {
+				fmt.Printf("Warning: failed to delete image variant %s: %v\n", v.FileID.Hex(), err)
+			}
+		}
+	}
+
+	prevWidth := 0
+	for _, variant := range utils.ImageVariants {
+		resized := utils.ResizeImage(img, variant.Width)
+		width, height := resized.Bounds().Dx(), resized.Bounds().Dy()
+		// Исходник уже меньше размера варианта - такой вариант уже сохранен
+		if width == prevWidth {
+			continue
+		}
+		prevWidth = width
+
+		jpegSize := 0
+		for _, format := range utils.ImageFormats {
+			encoded, contentType, err := utils.EncodeImage(resized, format)
+			if err != nil {
+				rollback()
+				return primitive.NilObjectID, err
+			}
+			if format == "jpeg" {
+				jpegSize = len(encoded)
+			} else if len(encoded) >= jpegSize {
+				// Вариант не меньше JPEG - мобильным клиентам отдается JPEG
+				continue
+			}
+
+			variantOpts := options.GridFSUpload().SetMetadata(bson.M{
+				"contentType": contentType,
+				"kind":        "variant",
+				"originalId":  originalID,
+				"variant":     variant.Name,
+			})
+			variantName := fmt.Sprintf("%s_%s.%s", filename, variant.Name, format)
+			variantID, err := bucket.UploadFromStream(variantName, bytes.NewReader(encoded), variantOpts)
+			if err != nil {
+				rollback()
+				return primitive.NilObjectID, err
+			}
+
+			variants = append(variants, storedVariant{
+				FileID: variantID,
+				Name:   variant.Name,
+				Width:  width,
+				Height: height,
+				Format: format,
+				Size:   int64(len(encoded)),
+			})
+		}
+	}
+
+	metadata["width"] = img.Bounds().Dx()
+	metadata["height"] = img.Bounds().Dy()
+	metadata["variants"] = variants
+
+	err := bucket.UploadFromStreamWithID(originalID, filename, bytes.NewReader(data), options.GridFSUpload().SetMetadata(metadata))
+	if err != nil {
+		rollback()
+		return primitive.NilObjectID, err
+	}
+	return originalID, nil
+}
+
+// findStoredFile - найти файл в GridFS и разобрать его metadata
+func findStoredFile(ctx context.Context, bucket *gridfs.Bucket, fileID primitive.ObjectID) (*gridfs.File, storedFileMetadata, error) {
+	var metadata storedFileMetadata
+
+	cursor, err := bucket.FindContext(ctx, bson.M{"_id": fileID})
+	if err != nil {
+		return nil, metadata, err
+	}
+	defer cursor.Close(ctx)
+
+	if !cursor.Next(ctx) {
+		if err := cursor.Err(); err != nil {
+			return nil, metadata, err
+		}
+		return nil, metadata, gridfs.ErrFileNotFound
+	}
+
+	var file gridfs.File
+	if err := cursor.Decode(&file); err != nil {
+		return nil, metadata, err
+	}
+	if file.Metadata != nil {
+		bson.Unmarshal(file.Metadata, &metadata)
+	}
+	return &file, metadata, nil
+}
+
+// deleteStoredFile - удалить файл из GridFS вместе с его вариантами
+func deleteStoredFile(ctx context.Context, bucket *gridfs.Bucket, fileID primitive.ObjectID) error {
+	_, metadata, err := findStoredFile(ctx, bucket, fileID)
+	if err != nil {
+		return err
+	}
+
+	for _, v := range metadata.Variants {
+		if err := bucket.DeleteContext(ctx, v.FileID); err != nil && err != gridfs.ErrFileNotFound {
+			fmt.Printf("Warning: failed to delete image variant %s: %v\n", v.FileID.Hex(), err)
+		}
+	}
+	return bucket.DeleteContext(ctx, fileID)
+}
+
+// variantsForFormat - варианты для отдачи в формате format: по одному на размер.
+// WebP заменяет JPEG того же размера, только если он меньше; у старых загрузок размер
+// не записан - для них отдается JPEG.
+func variantsForFormat(variants []storedVariant, format string) []storedVariant {
+	webp := map[string]storedVariant{}
+	for _, v := range variants {
+		if v.Format == "webp" {
+			webp[v.Name] = v
+		}
+	}
+
+	result := []storedVariant{}
+	for _, v := range variants {
+		if v.Format != "jpeg" {
+			continue
+		}
+		if w, ok := webp[v.Name]; format == "webp" && ok && w.Size > 0 && w.Size < v.Size {
+			v = w
+		}
+		result = append(result, v)
+	}
+	return result
+}
+
+// pickVariant - вариант для формата format с ближайшей шириной не меньше width,
+// иначе самый большой. width = 0 - самый большой вариант.
+func pickVariant(variants []storedVariant, width int, format string) (storedVariant, bool) {
+	var best storedVariant
+	found := false
+	for _, v := range variantsForFormat(variants, format) {
+		switch {
+		case !found:
+			best, found = v, true
+		case width > 0 && v.Width >= width:
+			// Подходящий по ширине - берем наименьший из подходящих
+			if best.Width < width || v.Width < best.Width {
+				best = v
+			}
+		case best.Width < width || width == 0:
+			// Подходящих пока нет - берем самый большой
+			if v.Width > best.Width {
+				best = v
+			}
+		}
+	}
+	return best, found
+}
+
+// uploadMovieMedia - загрузить файл в GridFS и привязать его к фильму.
+// Старый файл удаляется только после успешного обновления фильма.
+func uploadMovieMedia(c *gin.Context, kind mediaKind) {
+	movieID, err := primitive.ObjectIDFromHex(c.Param("id"))
+	if err != nil {
+		utils.ErrorResponse(c, 400, "Invalid movie ID")
+		return
+	}
+
+	file, header, contentType, reader, ok := receiveUpload(c, kind.MaxSize(), kind.AllowedTypes)
+	if !ok {
+		return
+	}
+	defer file.Close()
+
+	var imageData []byte
+	var img image.Image
+	if kind.IsImage {
+		if imageData, img, ok = readUploadedImage(c, reader, contentType); !ok {
+			return
+		}
+	}
+
+	moviesCollection := config.GetCollection("movies")
+
+	// Таймауты - только на запросы к базе: загрузка трейлера (до maxTrailerSize) идет дольше
+	checkCtx, cancelCheck := context.WithTimeout(context.Background(), 10*time.Second)
+	count, err := moviesCollection.CountDocuments(checkCtx, bson.M{"_id": movieID})
+	cancelCheck()
+	if err != nil || count == 0 {
+		utils.ErrorResponse(c, 404, "Movie not found")
+		return
+	}
+
+	bucket, err := config.GetGridFSBucket()
+	if err != nil {
+		utils.ErrorResponse(c, 500, "Failed to open file storage")
+		return
+	}
+
+	metadata := bson.M{
+		"contentType": contentType,
+		"kind":        kind.Name,
+		"movieId":     movieID,
+	}
+	var fileID primitive.ObjectID
+	if kind.IsImage {
+		fileID, err = storeImage(bucket, header.Filename, imageData, img, metadata)
+	} else {
+		fileID, err = bucket.UploadFromStream(header.Filename, reader, options.GridFSUpload().SetMetadata(metadata))
+	}
+	if err != nil {
+		utils.ErrorResponse(c, 500, "Failed to store file")
+		return
+	}
+
+	set := bson.M{kind.Field: fileID}
+	if kind.Name == "poster" {
+		set["posterUrl"] = fileURL(fileID)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	// Удаление файлов - со своим контекстом: большой файл удаляется дольше, чем живет ctx запроса
+	cleanupCtx, cancelCleanup := context.WithTimeout(context.Background(), 2*time.Minute)
+	defer cancelCleanup()
+
+	// Вернуть старую версию фильма, чтобы удалить замененный файл
+	var oldMovie models.Movie
+	err = moviesCollection.FindOneAndUpdate(ctx,
+		bson.M{"_id": movieID},
+		bson.M{"$set": set},
+		options.FindOneAndUpdate().SetReturnDocument(options.Before),
+	).Decode(&oldMovie)
+	if err != nil {
+		if delErr := deleteStoredFile(cleanupCtx, bucket, fileID); delErr != nil {
+			fmt.Printf("Warning: failed to delete orphaned file %s: %v\n", fileID.Hex(), delErr)
+		}
+		if err == mongo.ErrNoDocuments {
+			utils.ErrorResponse(c, 404, "Movie not found")
+			return
+		}
+		utils.ErrorResponse(c, 500, "Failed to update movie")
+		return
+	}
+
+	oldFileID := oldMovie.PosterFileID
+	if kind.Name == "trailer" {
+		oldFileID = oldMovie.TrailerFileID
+	}
+	if !oldFileID.IsZero() && oldFileID != fileID {
+		if err := deleteStoredFile(cleanupCtx, bucket, oldFileID); err != nil && err != gridfs.ErrFileNotFound {
+			fmt.Printf("Warning: failed to delete replaced file %s: %v\n", oldFileID.Hex(), err)
+		}
+	}
+
+	utils.SuccessWithMessage(c, 200, "File uploaded successfully", gin.H{
+		"movieId":     movieID.Hex(),
+		"fileId":      fileID.Hex(),
+		"url":         fileURL(fileID),
+		"contentType": contentType,
+		"size":        header.Size,
+	})
+}
+
+// UploadMoviePoster - загрузить постер фильма (admin only)
+func UploadMoviePoster(c *gin.Context) {
+	uploadMovieMedia(c, posterMedia)
+}
+
+// UploadMovieTrailer - загрузить трейлер фильма (admin only)
+func UploadMovieTrailer(c *gin.Context) {
+	uploadMovieMedia(c, trailerMedia)
+}
+
+// gridFSReader - io.ReadSeeker поверх чанков GridFS.
+// Позволяет http.ServeContent отдавать Range без чтения файла с начала.
+type gridFSReader struct {
+	ctx       context.Context
+	chunks    *mongo.Collection
+	fileID    primitive.ObjectID
+	length    int64
+	chunkSize int64
+
+	offset int64
+	cursor *mongo.Cursor
+	chunk  []byte // текущий чанк
+	chunkN int64  // номер текущего чанка
+}
+
+// Seek - переместить позицию; курсор чанков открывается заново при следующем Read
+func (r *gridFSReader) Seek(offset int64, whence int) (int64, error) {
+	switch whence {
+	case io.SeekStart:
+	case io.SeekCurrent:
+		offset += r.offset
+	case io.SeekEnd:
+		offset += r.length
+	default:
+		return 0, errors.New("invalid whence")
+	}
+	if offset < 0 {
+		return 0, errors.New("negative position")
+	}
+
+	if offset/r.chunkSize != r.chunkN || r.chunk == nil {
+		r.closeCursor()
+	}
+	r.offset = offset
+	return offset, nil
+}
+
+// Read - прочитать данные начиная с текущей позиции
+func (r *gridFSReader) Read(p []byte) (int, error) {
+	if r.offset >= r.length {
+		return 0, io.EOF
+	}
+
+	n := r.offset / r.chunkSize
+	if r.chunk == nil || n != r.chunkN {
+		if err := r.loadChunk(n); err != nil {
+			return 0, err
+		}
+	}
+
+	start := r.offset - n*r.chunkSize
+	if start >= int64(len(r.chunk)) {
+		return 0, io.ErrUnexpectedEOF
+	}
+	copied := copy(p, r.chunk[start:])
+	r.offset += int64(copied)
+	return copied, nil
+}
+
+// loadChunk - получить чанк n; последовательные чанки читаются одним курсором
+func (r *gridFSReader) loadChunk(n int64) error {
+	if r.cursor == nil || n != r.chunkN+1 {
+		r.closeCursor()
+		cursor, err := r.chunks.Find(r.ctx,
+			bson.M{"files_id": r.fileID, "n": bson.M{"$gte": n}},
+			options.Find().SetSort(bson.D{{Key: "n", Value: 1}}))
+		if err != nil {
+			return err
+		}
+		r.cursor = cursor
+	}
+
+	if !r.cursor.Next(r.ctx) {
+		if err := r.cursor.Err(); err != nil {
+			return err
+		}
+		return io.ErrUnexpectedEOF
+	}
+
+	var chunk struct {
+		N    int64            `bson:"n"`
+		Data primitive.Binary `bson:"data"`
+	}
+	if err := r.cursor.Decode(&chunk); err != nil {
+		return err
+	}
+	if chunk.N != n {
+		return fmt.Errorf("missing chunk %d of file %s", n, r.fileID.Hex())
+	}
+
+	r.chunk = chunk.Data.Data
+	r.chunkN = n
+	return nil
+}
+
+func (r *gridFSReader) closeCursor() {
+	if r.cursor != nil {
+		r.cursor.Close(r.ctx)
+		r.cursor = nil
+	}
+	r.chunk = nil
+	r.chunkN = -1
+}
+
+// ServeFile - отдать файл из GridFS (GET /api/files/:id[?w=300&format=webp]).
+// Поддерживает Range (перемотка видео), ETag/If-None-Match и долгий Cache-Control:
+// файлы неизменяемы, при замене постера/трейлера создается новый ID.
+func ServeFile(c *gin.Context) {
+	fileID, err := primitive.ObjectIDFromHex(c.Param("id"))
+	if err != nil {
+		utils.ErrorResponse(c, 400, "Invalid file ID")
+		return
+	}
+
+	bucket, err := config.GetGridFSBucket()
+	if err != nil {
+		utils.ErrorResponse(c, 500, "Failed to open file storage")
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	file, metadata, err := findStoredFile(ctx, bucket, fileID)
+	if err == gridfs.ErrFileNotFound {
+		utils.ErrorResponse(c, 404, "File not found")
+		return
+	}
+	if err != nil {
+		utils.ErrorResponse(c, 500, "Failed to fetch file")
+		return
+	}
+
+	// ?w=&format= - отдать ближайший уменьшенный вариант изображения
+	widthParam, formatParam := c.Query("w"), c.Query("format")
+	if widthParam != "" || formatParam != "" {
+		width := 0
+		if widthParam != "" {
+			width, err = strconv.Atoi(widthParam)
+			if err != nil || width <= 0 {
+				utils.ErrorResponse(c, 400, "Invalid width")
+				return
+			}
+		}
+
+		format := strings.ToLower(formatParam)
+		switch format {
+		case "", "jpg", "jpeg":
+			format = "jpeg"
+		case "webp":
+		default:
+			utils.ErrorResponse(c, 400, "Unsupported format (allowed: jpeg, webp)")
+			return
+		}
+
+		// Файлы без вариантов (видео, старые загрузки) отдаются как есть
+		if variant, found := pickVariant(metadata.Variants, width, format); found {
+			fileID = variant.FileID
+			file, metadata, err = findStoredFile(ctx, bucket, fileID)
+			if err != nil {
+				utils.ErrorResponse(c, 404, "File not found")
+				return
+			}
+		}
+	}
+
+	if metadata.ContentType == "" {
+		metadata.ContentType = "application/octet-stream"
+	}
+
+	c.Header("Content-Type", metadata.ContentType)
+	c.Header("ETag", `"`+fileID.Hex()+`"`)
+	c.Header("Cache-Control", "public, max-age=31536000, immutable")
+	c.Header("X-Content-Type-Options", "nosniff")
+
+	chunkSize := int64(file.ChunkSize)
+	if chunkSize <= 0 {
+		chunkSize = int64(gridfs.DefaultChunkSize)
+	}
+
+	// Стриминг видео может длиться дольше обычного таймаута - привязываемся к запросу
+	reader := &gridFSReader{
+		ctx:       c.Request.Context(),
+		chunks:    bucket.GetChunksCollection(),
+		fileID:    fileID,
+		length:    file.Length,
+		chunkSize: chunkSize,
+		chunkN:    -1,
+	}
+	defer reader.closeCursor()
+
+	// ServeContent обрабатывает Range (206/416), If-None-Match (304) и HEAD
+	http.ServeContent(c.Writer, c.Request, file.Name, file.UploadDate, reader)
+}
@@ -0,0 +1,539 @@
+package handlers
+
+import (
+	"cinema-booking/config"
+	"cinema-booking/models"
+	"cinema-booking/utils"
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// Ограничения номинала подарочной карты
+const (
+	giftCardMinAmount = 1000
+	giftCardMaxAmount = 200000
+)
+
+// Срок действия подарочной карты по умолчанию
+const giftCardValidity = 365 * 24 * time.Hour
+
+// generateGiftCardCode - код вида "GC-XXXX-XXXX-XXXX"
+func generateGiftCardCode() (string, error) {
+	code, err := utils.RandomCode(12)
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("GC-%s-%s-%s", code[0:4], code[4:8], code[8:12]), nil
+}
+
+// normalizeGiftCardCode - коды хранятся в верхнем регистре
+func normalizeGiftCardCode(code string) string {
+	return strings.ToUpper(utils.SanitizeString(code))
+}
+
+// insertGiftCard - сохранить карту, перегенерировав код при коллизии
+func insertGiftCard(ctx context.Context, card *models.GiftCard) error {
+	giftCardsCollection := config.GetCollection("gift_cards")
+	for attempt := 0; attempt < 5; attempt++ {
+		code, err := generateGiftCardCode()
+		if err != nil {
+			return err
+		}
+		card.Code = code
+
+		result, err := giftCardsCollection.InsertOne(ctx, card)
+		if mongo.IsDuplicateKeyError(err) {
+			continue
+		}
+		if err != nil {
+			return err
+		}
+		card.ID = result.InsertedID.(primitive.ObjectID)
+		return nil
+	}
+	return errors.New("failed to generate a unique gift card code")
+}
+
+// recordGiftCardTransaction - запись в истории транзакций пользователя
+func recordGiftCardTransaction(ctx context.Context, userID primitive.ObjectID, txType string, amount float64, card *models.GiftCard, bookingID primitive.ObjectID, description string) {
+	transaction := models.Transaction{
+		UserID:      userID,
+		Type:        txType,
+		Amount:      amount,
+		BookingID:   bookingID,
+		GiftCardID:  card.ID,
+		Status:      "completed",
+		Description: description,
+		CreatedAt:   time.Now(),
+	}
+	if _, err := config.GetCollection("transactions").InsertOne(ctx, transaction); err != nil {
+		fmt.Printf("Warning: failed to create transaction record: %v\n", err)
+	}
+}
+
+// findUsableGiftCard - найти активную, не просроченную карту с положительным балансом
+func findUsableGiftCard(ctx context.Context, code string) (*models.GiftCard, error) {
+	var card models.GiftCard
+	err := config.GetCollection("gift_cards").FindOne(ctx, bson.M{"code": normalizeGiftCardCode(code)}).Decode(&card)
+	if err != nil {
+		return nil, errors.New("Gift card not found")
+	}
+	if card.Status != "active" {
+		return nil, fmt.Errorf("Gift card is %s", card.Status)
+	}
+	if time.Now().After(card.ExpiresAt) {
+		return nil, errors.New("Gift card has expired")
+	}
+	if card.Balance <= 0 {
+		return nil, errors.New("Gift card balance is empty")
+	}
+	return &card, nil
+}
+
+// chargeGiftCard - атомарно списать сумму с карты (только если баланса хватает)
+func chargeGiftCard(ctx context.Context, card *models.GiftCard, amount float64) error {
+	result, err := config.GetCollection("gift_cards").UpdateOne(ctx,
+		bson.M{
+			"_id":       card.ID,
+			"status":    "active",
+			"balance":   bson.M{"$gte": amount},
+			"expiresAt": bson.M{"$gt": time.Now()},
+		},
+		bson.M{
+			"$inc": bson.M{"balance": -amount},
+			"$set": bson.M{"updatedAt": time.Now()},
+		},
+	)
+	if err != nil {
+		return errors.New("Failed to charge gift card")
+	}
+	if result.ModifiedCount == 0 {
+		return errors.New("Gift card balance is insufficient")
+	}
+	card.Balance = roundMoney(card.Balance - amount)
+	return nil
+}
+
+// refundGiftCardPayment - вернуть оплаченную картой часть брони на карту.
+// Если карта уже отключена, погашена или просрочена - деньги зачисляются в кошелек.
+func refundGiftCardPayment(ctx context.Context, booking models.Booking) {
+	if booking.Payment.GiftCardID.IsZero() || booking.Payment.GiftCardAmount <= 0 {
+		return
+	}
+
+	amount := booking.Payment.GiftCardAmount
+	card := &models.GiftCard{ID: booking.Payment.GiftCardID}
+
+	result, err := config.GetCollection("gift_cards").UpdateOne(ctx,
+		bson.M{
+			"_id":       booking.Payment.GiftCardID,
+			"status":    "active",
+			"expiresAt": bson.M{"$gt": time.Now()},
+		},
+		bson.M{
+			"$inc": bson.M{"balance": amount},
+			"$set": bson.M{"updatedAt": time.Now()},
+		},
+	)
+	if err != nil {
+		fmt.Printf("Warning: failed to refund gift card: %v\n", err)
+		return
+	}
+	if result.MatchedCount > 0 {
+		recordGiftCardTransaction(ctx, booking.UserID, "gift_card_refund", amount, card, booking.ID,
+			fmt.Sprintf("Refund to gift card %s for cancelled booking %s", booking.Payment.GiftCardCode, booking.BookingNumber))
+		return
+	}
+
+	// Карта больше не принимает платежи - вернуть в кошелек, чтобы деньги не зависли
+	_, err = config.GetCollection("users").UpdateOne(ctx,
+		bson.M{"_id": booking.UserID},
+		bson.M{
+			"$inc": bson.M{"wallet.balance": amount},
+			"$set": bson.M{"updatedAt": time.Now()},
+		},
+	)
+	if err != nil {
+		fmt.Printf("Warning: failed to refund gift card payment to wallet: %v\n", err)
+		return
+	}
+
+	recordGiftCardTransaction(ctx, booking.UserID, "refund", amount, card, booking.ID,
+		fmt.Sprintf("Refund to wallet for cancelled booking %s: gift card %s is no longer active", booking.BookingNumber, booking.Payment.GiftCardCode))
+}
+
+// PurchaseGiftCardRequest - покупка подарочной карты
+type PurchaseGiftCardRequest struct {
+	Amount         float64 `json:"amount" binding:"required"`
+	PaymentMethod  string  `json:"paymentMethod" binding:"required"` // "wallet", "card"
+	RecipientName  string  `json:"recipientName"`
+	RecipientEmail string  `json:"recipientEmail"`
+	Message        string  `json:"message"`
+}
+
+// PurchaseGiftCard - купить подарочную карту
+func PurchaseGiftCard(c *gin.Context) {
+	userID, _ := c.Get("userId")
+	userObjectID, _ := primitive.ObjectIDFromHex(userID.(string))
+
+	var req PurchaseGiftCardRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ErrorResponse(c, 400, "Invalid request data: "+err.Error())
+		return
+	}
+
+	// Валидация
+	if req.Amount < giftCardMinAmount || req.Amount > giftCardMaxAmount {
+		utils.ErrorResponse(c, 400, fmt.Sprintf("Gift card amount must be between %d and %d KZT", giftCardMinAmount, giftCardMaxAmount))
+		return
+	}
+
+	if req.PaymentMethod != "wallet" && req.PaymentMethod != "card" {
+		utils.ErrorResponse(c, 400, "Invalid payment method. Use: wallet or card")
+		return
+	}
+
+	req.RecipientEmail = utils.SanitizeString(req.RecipientEmail)
+	if req.RecipientEmail != "" && !utils.ValidateEmail(req.RecipientEmail) {
+		utils.ErrorResponse(c, 400, "Invalid recipient email format")
+		return
+	}
+
+	if len(req.Message) > 500 {
+		utils.ErrorResponse(c, 400, "Message must be at most 500 characters")
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	usersCollection := config.GetCollection("users")
+
+	// Оплата с кошелька: атомарное списание только при достаточном балансе
+	if req.PaymentMethod == "wallet" {
+		result, err := usersCollection.UpdateOne(ctx,
+			bson.M{"_id": userObjectID, "wallet.balance": bson.M{"$gte": req.Amount}},
+			bson.M{
+				"$inc": bson.M{"wallet.balance": -req.Amount},
+				"$set": bson.M{"updatedAt": time.Now()},
+			},
+		)
+		if err != nil {
+			utils.ErrorResponse(c, 500, "Failed to charge wallet")
+			return
+		}
+		if result.ModifiedCount == 0 {
+			utils.ErrorResponse(c, 400, "Insufficient wallet balance")
+			return
+		}
+	}
+
+	card := models.GiftCard{
+		InitialAmount:  req.Amount,
+		Balance:        req.Amount,
+		Currency:       "KZT",
+		Status:         "active",
+		Source:         "purchase",
+		PurchasedBy:    userObjectID,
+		RecipientName:  utils.SanitizeString(req.RecipientName),
+		RecipientEmail: req.RecipientEmail,
+		Message:        utils.SanitizeString(req.Message),
+		ExpiresAt:      time.Now().Add(giftCardValidity),
+		CreatedAt:      time.Now(),
+		UpdatedAt:      time.Now(),
+	}
+
+	if err := insertGiftCard(ctx, &card); err != nil {
+		// Вернуть деньги на кошелек, если карту не удалось создать
+		if req.PaymentMethod == "wallet" {
+			usersCollection.UpdateOne(ctx, bson.M{"_id": userObjectID}, bson.M{"$inc": bson.M{"wallet.balance": req.Amount}})
+		}
+		utils.ErrorResponse(c, 500, "Failed to create gift card")
+		return
+	}
+
+	recordGiftCardTransaction(ctx, userObjectID, "gift_card_purchase", -req.Amount, &card, primitive.NilObjectID,
+		fmt.Sprintf("Gift card %s purchase (%s)", card.Code, req.PaymentMethod))
+
+	utils.SuccessWithMessage(c, 201, "Gift card purchased successfully", card)
+}
+
+// GetMyGiftCards - подарочные карты, купленные или зачисленные пользователем
+func GetMyGiftCards(c *gin.Context) {
+	userID, _ := c.Get("userId")
+	userObjectID, _ := primitive.ObjectIDFromHex(userID.(string))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	filter := bson.M{"$or": bson.A{
+		bson.M{"purchasedBy": userObjectID},
+		bson.M{"redeemedBy": userObjectID},
+	}}
+
+	cursor, err := config.GetCollection("gift_cards").Find(ctx, filter,
+		options.Find().SetSort(bson.D{{Key: "createdAt", Value: -1}}))
+	if err != nil {
+		utils.ErrorResponse(c, 500, "Failed to fetch gift cards")
+		return
+	}
+	defer cursor.Close(ctx)
+
+	cards := []models.GiftCard{}
+	if err = cursor.All(ctx, &cards); err != nil {
+		utils.ErrorResponse(c, 500, "Failed to decode gift cards")
+		return
+	}
+
+	utils.SuccessResponse(c, 200, cards)
+}
+
+// GetGiftCardBalance - проверить баланс карты по коду
+func GetGiftCardBalance(c *gin.Context) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	var card models.GiftCard
+	err := config.GetCollection("gift_cards").FindOne(ctx, bson.M{"code": normalizeGiftCardCode(c.Param("code"))}).Decode(&card)
+	if err != nil {
+		utils.ErrorResponse(c, 404, "Gift card not found")
+		return
+	}
+
+	status := card.Status
+	if status == "active" && time.Now().After(card.ExpiresAt) {
+		status = "expired"
+	}
+
+	// Только публичная информация о карте
+	utils.SuccessResponse(c, 200, gin.H{
+		"code":      card.Code,
+		"balance":   card.Balance,
+		"currency":  card.Currency,
+		"status":    status,
+		"expiresAt": card.ExpiresAt,
+	})
+}
+
+// RedeemGiftCardRequest - зачисление карты на кошелек
+type RedeemGiftCardRequest struct {
+	Code string `json:"code" binding:"required"`
+}
+
+// RedeemGiftCard - зачислить весь остаток подарочной карты на кошелек
+func RedeemGiftCard(c *gin.Context) {
+	userID, _ := c.Get("userId")
+	userObjectID, _ := primitive.ObjectIDFromHex(userID.(string))
+
+	var req RedeemGiftCardRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ErrorResponse(c, 400, "Invalid request data: "+err.Error())
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	card, err := findUsableGiftCard(ctx, req.Code)
+	if err != nil {
+		utils.ErrorResponse(c, 400, err.Error())
+		return
+	}
+
+	// Атомарно обнулить баланс карты (защита от двойного зачисления)
+	var redeemed models.GiftCard
+	err = config.GetCollection("gift_cards").FindOneAndUpdate(ctx,
+		bson.M{"_id": card.ID, "status": "active", "balance": bson.M{"$gt": 0}},
+		bson.M{"$set": bson.M{
+			"balance":    0,
+			"status":     "redeemed",
+			"redeemedBy": userObjectID,
+			"updatedAt":  time.Now(),
+		}},
+	).Decode(&redeemed)
+	if err != nil {
+		utils.ErrorResponse(c, 400, "Gift card has already been redeemed")
+		return
+	}
+
+	amount := redeemed.Balance
+
+	usersCollection := config.GetCollection("users")
+	_, err = usersCollection.UpdateOne(ctx,
+		bson.M{"_id": userObjectID},
+		bson.M{
+			"$inc": bson.M{"wallet.balance": amount},
+			"$set": bson.M{"updatedAt": time.Now()},
+		},
+	)
+	if err != nil {
+		utils.ErrorResponse(c, 500, "Failed to credit wallet")
+		return
+	}
+
+	recordGiftCardTransaction(ctx, userObjectID, "gift_card_redeem", amount, &redeemed, primitive.NilObjectID,
+		fmt.Sprintf("Gift card %s redeemed to wallet: +%.2f KZT", redeemed.Code, amount))
+
+	var updatedUser models.User
+	usersCollection.FindOne(ctx, bson.M{"_id": userObjectID}).Decode(&updatedUser)
+
+	utils.SuccessWithMessage(c, 200, "Gift card redeemed successfully", gin.H{
+		"amount": amount,
+		"wallet": updatedUser.Wallet,
+	})
+}
+
+// IssueGiftCardsRequest - выпуск пакета карт администратором
+type IssueGiftCardsRequest struct {
+	Count     int       `json:"count" binding:"required"`
+	Amount    float64   `json:"amount" binding:"required"`
+	Batch     string    `json:"batch" binding:"required"`
+	ExpiresAt time.Time `json:"expiresAt"`
+}
+
+// IssueGiftCards - выпустить пакет подарочных карт (admin only)
+func IssueGiftCards(c *gin.Context) {
+	var req IssueGiftCardsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ErrorResponse(c, 400, "Invalid request data: "+err.Error())
+		return
+	}
+
+	if req.Count < 1 || req.Count > 5000 {
+		utils.ErrorResponse(c, 400, "Count must be between 1 and 5000")
+		return
+	}
+
+	if req.Amount < giftCardMinAmount || req.Amount > giftCardMaxAmount {
+		utils.ErrorResponse(c, 400, fmt.Sprintf("Gift card amount must be between %d and %d KZT", giftCardMinAmount, giftCardMaxAmount))
+		return
+	}
+
+	if req.ExpiresAt.IsZero() {
+		req.ExpiresAt = time.Now().Add(giftCardValidity)
+	}
+	if req.ExpiresAt.Before(time.Now()) {
+		utils.ErrorResponse(c, 400, "expiresAt must be in the future")
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
+	defer cancel()
+
+	cards := make([]models.GiftCard, 0, req.Count)
+	for i := 0; i < req.Count; i++ {
+		card := models.GiftCard{
+			InitialAmount: req.Amount,
+			Balance:       req.Amount,
+			Currency:      "KZT",
+			Status:        "active",
+			Source:        "admin",
+			Batch:         utils.SanitizeString(req.Batch),
+			ExpiresAt:     req.ExpiresAt,
+			CreatedAt:     time.Now(),
+			UpdatedAt:     time.Now(),
+		}
+		if err := insertGiftCard(ctx, &card); err != nil {
+			utils.ErrorResponse(c, 500, fmt.Sprintf("Failed to issue gift cards (%d of %d created)", len(cards), req.Count))
+			return
+		}
+		cards = append(cards, card)
+	}
+
+	utils.SuccessWithMessage(c, 201, fmt.Sprintf("Issued %d gift cards", len(cards)), gin.H{
+		"batch": req.Batch,
+		"count": len(cards),
+		"cards": cards,
+	})
+}
+
+// GetGiftCards - список подарочных карт (admin only)
+func GetGiftCards(c *gin.Context) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	// Пагинация
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "20"))
+	if page < 1 {
+		page = 1
+	}
+	if limit < 1 || limit > 100 {
+		limit = 20
+	}
+	skip := (page - 1) * limit
+
+	filter := bson.M{}
+	if batch := c.Query("batch"); batch != "" {
+		filter["batch"] = batch
+	}
+	if status := c.Query("status"); status != "" {
+		filter["status"] = status
+	}
+
+	giftCardsCollection := config.GetCollection("gift_cards")
+
+	findOptions := options.Find()
+	findOptions.SetSkip(int64(skip))
+	findOptions.SetLimit(int64(limit))
+	findOptions.SetSort(bson.D{{Key: "createdAt", Value: -1}})
+
+	cursor, err := giftCardsCollection.Find(ctx, filter, findOptions)
+	if err != nil {
+		utils.ErrorResponse(c, 500, "Failed to fetch gift cards")
+		return
+	}
+	defer cursor.Close(ctx)
+
+	cards := []models.GiftCard{}
+	if err = cursor.All(ctx, &cards); err != nil {
+		utils.ErrorResponse(c, 500, "Failed to decode gift cards")
+		return
+	}
+
+	total, _ := giftCardsCollection.CountDocuments(ctx, filter)
+
+	utils.PaginatedResponse(c, cards, page, limit, int(total))
+}
+
+// DisableGiftCard - заблокировать подарочную карту (admin only)
+func DisableGiftCard(c *gin.Context) {
+	cardIDStr := c.Param("id")
+	cardID, err := primitive.ObjectIDFromHex(cardIDStr)
+	if err != nil {
+		utils.ErrorResponse(c, 400, "Invalid gift card ID")
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	result, err := config.GetCollection("gift_cards").UpdateOne(ctx,
+		bson.M{"_id": cardID},
+		bson.M{"$set": bson.M{"status": "disabled", "updatedAt": time.Now()}},
+	)
+	if err != nil {
+		utils.ErrorResponse(c, 500, "Failed to disable gift card")
+		return
+	}
+
+	if result.MatchedCount == 0 {
+		utils.ErrorResponse(c, 404, "Gift card not found")
+		return
+	}
+
+	utils.SuccessWithMessage(c, 200, "Gift card disabled successfully", gin.H{
+		"giftCardId": cardIDStr,
+		"disabled":   true,
+	})
+}
@@ -0,0 +1,108 @@
+package handlers
+
+import (
+	"cinema-booking/config"
+	"context"
+	"errors"
+	"sort"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// Блокировка зала держится не дольше hallLockLease (если процесс упал, не сняв ее)
+const (
+	hallLockLease = 2 * time.Minute
+	hallLockWait  = 5 * time.Second
+)
+
+// ErrHallBusy - расписание зала сейчас меняет другой запрос
+var ErrHallBusy = errors.New("hall schedule is being changed by another request")
+
+// countOverlappingShowtimes - число сеансов зала, пересекающихся с [start, end)
+func countOverlappingShowtimes(ctx context.Context, hallID primitive.ObjectID, start, end time.Time) (int64, error) {
+	return config.GetCollection("showtimes").CountDocuments(ctx, bson.M{
+		"hallId":    hallID,
+		"startTime": bson.M{"$lt": end},
+		"endTime":   bson.M{"$gt": start},
+	})
+}
+
+// lockHalls - взять блокировки залов (документы hall_locks) на время проверки и вставки сеансов.
+// Пока блокировка у одного запроса, другие (CreateShowtime, импорт) ждут до hallLockWait.
+// Транзакции требуют replica set, поэтому запись сериализуется через _id документа.
+func lockHalls(ctx context.Context, hallIDs []primitive.ObjectID) (func(), error) {
+	// Один порядок захвата для всех запросов - без взаимных блокировок
+	unique := make(map[primitive.ObjectID]bool)
+	var ids []primitive.ObjectID
+	for _, id := range hallIDs {
+		if !unique[id] {
+			unique[id] = true
+			ids = append(ids, id)
+		}
+	}
+	sort.Slice(ids, func(i, j int) bool { return ids[i].Hex() < ids[j].Hex() })
+
+	owner := primitive.NewObjectID()
+	var locked []primitive.ObjectID
+	release := func() {
+		if len(locked) == 0 {
+			return
+		}
+		// Снять блокировки даже если ctx запроса уже истек
+		releaseCtx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+		_, _ = config.GetCollection("hall_locks").DeleteMany(releaseCtx, bson.M{
+			"_id":   bson.M{"$in": locked},
+			"owner": owner,
+		})
+	}
+
+	for _, id := range ids {
+		if err := acquireHallLock(ctx, id, owner); err != nil {
+			release()
+			return nil, err
+		}
+		locked = append(locked, id)
+	}
+	return release, nil
+}
+
+// acquireHallLock - взять блокировку одного зала, повторяя попытки до hallLockWait
+func acquireHallLock(ctx context.Context, hallID, owner primitive.ObjectID) error {
+	locksCollection := config.GetCollection("hall_locks")
+	deadline := time.Now().Add(hallLockWait)
+
+	for {
+		now := time.Now()
+		// Upsert сработает только если блокировки нет или ее срок истек;
+		// иначе вставка документа с тем же _id упадет с duplicate key
+		_, err := locksCollection.UpdateOne(ctx,
+			bson.M{"_id": hallID, "lockedUntil": bson.M{"$lt": now}},
+			bson.M{"$set": bson.M{
+				"owner":       owner,
+				"lockedUntil": now.Add(hallLockLease),
+				"expiresAt":   now.Add(hallLockLease),
+			}},
+			options.Update().SetUpsert(true),
+		)
+		if err == nil {
+			return nil
+		}
+		if !mongo.IsDuplicateKeyError(err) {
+			return err
+		}
+		if time.Now().After(deadline) {
+			return ErrHallBusy
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(100 * time.Millisecond):
+		}
+	}
+}
@@ -0,0 +1,90 @@
+package handlers
+
+import (
+	"cinema-booking/i18n"
+	"cinema-booking/models"
+
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+// localizeMovie - title/description на языке запроса, переведенные жанры и языки в movie.Localized.
+// Переводы хранятся в TitleKz/TitleRu и DescriptionKz/DescriptionRu, английский - в Title/Description.
+// Исходные поля не меняются: клиенту нужен оригинальный title, а фильтр ?genre= ждет английские жанры.
+func localizeMovie(lang string, movie *models.Movie) {
+	movie.Localized = &models.MovieLocalization{
+		Lang: lang,
+		Title: i18n.Pick(lang, map[string]string{
+			i18n.English: movie.Title,
+			i18n.Russian: movie.TitleRu,
+			i18n.Kazakh:  movie.TitleKz,
+		}),
+		Description: i18n.Pick(lang, map[string]string{
+			i18n.English: movie.Description,
+			i18n.Russian: movie.DescriptionRu,
+			i18n.Kazakh:  movie.DescriptionKz,
+		}),
+		Genres:    i18n.Terms(lang, movie.Genres),
+		Language:  i18n.Terms(lang, movie.Language),
+		Subtitles: i18n.Terms(lang, movie.Subtitles),
+	}
+}
+
+// localizeShowtime - перевести формат, язык и субтитры сеанса
+func localizeShowtime(lang string, showtime *models.Showtime) {
+	showtime.Format = i18n.Term(lang, showtime.Format)
+	showtime.Language = i18n.Term(lang, showtime.Language)
+	showtime.Subtitles = i18n.Term(lang, showtime.Subtitles)
+}
+
+// localizeMovieDoc - то же, что localizeMovie, для документа из агрегации ($lookup)
+func localizeMovieDoc(lang string, doc bson.M) {
+	str := func(key string) string {
+		s, _ := doc[key].(string)
+		return s
+	}
+	strs := func(key string) []string {
+		arr, ok := doc[key].(bson.A)
+		if !ok {
+			return nil
+		}
+		result := make([]string, 0, len(arr))
+		for _, v := range arr {
+			if s, ok := v.(string); ok {
+				result = append(result, s)
+			}
+		}
+		return result
+	}
+
+	localized := bson.M{
+		"lang": lang,
+		"title": i18n.Pick(lang, map[string]string{
+			i18n.English: str("title"),
+			i18n.Russian: str("titleRu"),
+			i18n.Kazakh:  str("titleKz"),
+		}),
+		"description": i18n.Pick(lang, map[string]string{
+			i18n.English: str("description"),
+			i18n.Russian: str("descriptionRu"),
+			i18n.Kazakh:  str("descriptionKz"),
+		}),
+	}
+	for _, key := range []string{"genres", "language", "subtitles"} {
+		if values := strs(key); values != nil {
+			localized[key] = i18n.Terms(lang, values)
+		}
+	}
+	doc["localized"] = localized
+}
+
+// localizeShowtimeDoc - перевести сеанс из агрегации вместе с movieDetails
+func localizeShowtimeDoc(lang string, doc bson.M) {
+	for _, key := range []string{"format", "language", "subtitles"} {
+		if s, ok := doc[key].(string); ok {
+			doc[key] = i18n.Term(lang, s)
+		}
+	}
+	if movie, ok := doc["movieDetails"].(bson.M); ok {
+		localizeMovieDoc(lang, movie)
+	}
+}
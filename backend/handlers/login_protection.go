@@ -0,0 +1,333 @@
+package handlers
+
+import (
+	"cinema-booking/config"
+	"cinema-booking/i18n"
+	"cinema-booking/mailer"
+	"cinema-booking/models"
+	"cinema-booking/utils"
+	"context"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// loginLimit - порог неудачных входов для аккаунта или IP
+type loginLimit struct {
+	FreeFailures int           // без задержки
+	LockAfter    int           // блокировка после N ошибок подряд
+	LockDuration time.Duration // срок блокировки
+}
+
+var (
+	// С одного IP пробуют много аккаунтов (и за NAT сидит много людей) - порог выше
+	accountLoginLimit = loginLimit{FreeFailures: 3, LockAfter: 10, LockDuration: 30 * time.Minute}
+	ipLoginLimit      = loginLimit{FreeFailures: 10, LockAfter: 50, LockDuration: 15 * time.Minute}
+)
+
+const (
+	loginFailureWindow = time.Hour       // счетчик сбрасывается после часа без ошибок
+	maxLoginBackoff    = 5 * time.Minute // предел экспоненциальной задержки
+	accountUnlockTTL   = 24 * time.Hour  // срок ссылки разблокировки
+)
+
+// UnlockAccountRequest - разблокировка по токену из письма
+type UnlockAccountRequest struct {
+	Token string `json:"token" binding:"required"`
+}
+
+var (
+	dummyPasswordHash     []byte
+	dummyPasswordHashOnce sync.Once
+)
+
+// compareDummyPassword - проверка пароля для несуществующего email занимает столько же времени,
+// сколько для существующего (нельзя определить зарегистрированные адреса по времени ответа)
+func compareDummyPassword(password string) {
+	dummyPasswordHashOnce.Do(func() {
+		dummyPasswordHash, _ = bcrypt.GenerateFromPassword([]byte("dummy-password"), bcrypt.DefaultCost)
+	})
+	bcrypt.CompareHashAndPassword(dummyPasswordHash, []byte(password))
+}
+
+// accountAttemptsKey / ipAttemptsKey - ключи счетчиков в login_attempts
+func accountAttemptsKey(email string) string {
+	return "account:" + strings.ToLower(strings.TrimSpace(email))
+}
+
+func ipAttemptsKey(ip string) string {
+	return "ip:" + ip
+}
+
+// loginBackoff - задержка перед следующей попыткой: 1с, 2с, 4с... после FreeFailures ошибок
+func loginBackoff(failures int, limit loginLimit) time.Duration {
+	extra := failures - limit.FreeFailures
+	if extra <= 0 {
+		return 0
+	}
+	if extra > 20 {
+		return maxLoginBackoff
+	}
+	delay := time.Duration(math.Pow(2, float64(extra-1))) * time.Second
+	if delay > maxLoginBackoff {
+		return maxLoginBackoff
+	}
+	return delay
+}
+
+// loginRetryAfter - сколько ждать до следующей попытки (0 - можно), locked - идет блокировка
+func loginRetryAfter(ctx context.Context, key string, limit loginLimit) (time.Duration, bool, error) {
+	var attempts models.LoginAttempts
+	err := config.GetCollection("login_attempts").FindOne(ctx, bson.M{"_id": key}).Decode(&attempts)
+	if err == mongo.ErrNoDocuments {
+		return 0, false, nil
+	}
+	if err != nil {
+		return 0, false, err
+	}
+
+	now := time.Now()
+	if attempts.LockedUntil != nil && attempts.LockedUntil.After(now) {
+		return attempts.LockedUntil.Sub(now), true, nil
+	}
+	if now.Sub(attempts.LastFailureAt) > loginFailureWindow {
+		return 0, false, nil // TTL удаляет документы с задержкой
+	}
+	if wait := attempts.LastFailureAt.Add(loginBackoff(attempts.Failures, limit)).Sub(now); wait > 0 {
+		return wait, false, nil
+	}
+	return 0, false, nil
+}
+
+// recordLoginFailure - учесть неудачную попытку. Возвращает true, если эта попытка
+// привела к блокировке (ровно один запрос из одновременных).
+func recordLoginFailure(ctx context.Context, key string, limit loginLimit) (bool, error) {
+	attemptsCollection := config.GetCollection("login_attempts")
+	now := time.Now()
+
+	// Окно без ошибок прошло - начать счет заново
+	if _, err := attemptsCollection.DeleteOne(ctx, bson.M{
+		"_id":           key,
+		"lastFailureAt": bson.M{"$lt": now.Add(-loginFailureWindow)},
+		"$or": bson.A{
+			bson.M{"lockedUntil": bson.M{"$exists": false}},
+			bson.M{"lockedUntil": bson.M{"$lt": now}},
+		},
+	}); err != nil {
+		return false, err
+	}
+
+	var attempts models.LoginAttempts
+	err := attemptsCollection.FindOneAndUpdate(ctx,
+		bson.M{"_id": key},
+		bson.M{
+			"$inc": bson.M{"failures": 1},
+			"$set": bson.M{"lastFailureAt": now, "expiresAt": now.Add(loginFailureWindow)},
+		},
+		options.FindOneAndUpdate().SetUpsert(true).SetReturnDocument(options.After),
+	).Decode(&attempts)
+	if err != nil {
+		return false, err
+	}
+	if attempts.Failures < limit.LockAfter {
+		return false, nil
+	}
+
+	// Блокировка: счетчик обнуляется, после разблокировки задержки начинаются сначала
+	lockedUntil := now.Add(limit.LockDuration)
+	result, err := attemptsCollection.UpdateOne(ctx,
+		bson.M{"_id": key, "failures": bson.M{"$gte": limit.LockAfter}},
+		bson.M{
+			"$set": bson.M{"failures": 0, "lockedUntil": lockedUntil, "expiresAt": lockedUntil.Add(loginFailureWindow)},
+			"$inc": bson.M{"lockouts": 1},
+		},
+	)
+	if err != nil {
+		return false, err
+	}
+	return result.ModifiedCount > 0, nil
+}
+
+// clearLoginFailures - сбросить счетчик аккаунта (успешный вход, разблокировка, сброс пароля)
+func clearLoginFailures(ctx context.Context, email string) error {
+	_, err := config.GetCollection("login_attempts").DeleteOne(ctx, bson.M{"_id": accountAttemptsKey(email)})
+	return err
+}
+
+// checkLoginAllowed - проверить задержку и блокировку аккаунта и IP перед проверкой пароля.
+// Ответ не зависит от того, существует ли аккаунт. Возвращает false, если ответ уже отправлен.
+func checkLoginAllowed(ctx context.Context, c *gin.Context, email string) bool {
+	checks := []struct {
+		key   string
+		limit loginLimit
+	}{
+		{accountAttemptsKey(email), accountLoginLimit},
+		{ipAttemptsKey(c.ClientIP()), ipLoginLimit},
+	}
+
+	for _, check := range checks {
+		wait, locked, err := loginRetryAfter(ctx, check.key, check.limit)
+		if err != nil {
+			fmt.Printf("Warning: failed to check login attempts for %s: %v\n", check.key, err)
+			continue
+		}
+		if wait <= 0 {
+			continue
+		}
+
+		c.Header("Retry-After", strconv.Itoa(int(math.Ceil(wait.Seconds()))))
+		if locked {
+			utils.ErrorResponse(c, 429, "Too many failed login attempts. Sign-in is temporarily locked, check your email to unlock it or try again later")
+		} else {
+			utils.ErrorResponse(c, 429, "Too many failed login attempts. Try again later")
+		}
+		return false
+	}
+	return true
+}
+
+// handleLoginFailure - учесть неудачный вход; при блокировке - журнал и письмо для разблокировки
+func handleLoginFailure(ctx context.Context, c *gin.Context, email string, user *models.User) {
+	ip := c.ClientIP()
+
+	accountLocked, err := recordLoginFailure(ctx, accountAttemptsKey(email), accountLoginLimit)
+	if err != nil {
+		fmt.Printf("Warning: failed to record login failure: %v\n", err)
+	}
+	if accountLocked {
+		entry := models.AuditEntry{
+			Type:    models.AuditAccountLocked,
+			Email:   strings.ToLower(email),
+			IP:      ip,
+			Details: fmt.Sprintf("%d failed attempts, locked for %s", accountLoginLimit.LockAfter, accountLoginLimit.LockDuration),
+		}
+		if user != nil {
+			entry.UserID = user.ID
+		}
+		recordAudit(ctx, entry)
+
+		if user != nil {
+			if err := sendUnlockEmail(ctx, i18n.FromContext(c), *user); err != nil {
+				fmt.Printf("Warning: failed to send unlock email to user %s: %v\n", user.ID.Hex(), err)
+			}
+		}
+	}
+
+	ipLocked, err := recordLoginFailure(ctx, ipAttemptsKey(ip), ipLoginLimit)
+	if err != nil {
+		fmt.Printf("Warning: failed to record login failure: %v\n", err)
+	}
+	if ipLocked {
+		recordAudit(ctx, models.AuditEntry{
+			Type:    models.AuditIPLocked,
+			IP:      ip,
+			Details: fmt.Sprintf("%d failed attempts, locked for %s", ipLoginLimit.LockAfter, ipLoginLimit.LockDuration),
+		})
+	}
+}
+
+// sendUnlockEmail - письмо о блокировке со ссылкой для разблокировки
+func sendUnlockEmail(ctx context.Context, lang string, user models.User) error {
+	token, err := createUserToken(ctx, user, models.UserTokenAccountUnlock, accountUnlockTTL)
+	if err != nil {
+		return err
+	}
+
+	return getMailer().Send(ctx, mailer.Message{
+		To:      user.Email,
+		Subject: i18n.T(lang, "Sign-in to your account has been locked"),
+		Body: i18n.T(lang, "Hello") + ", " + user.FullName + "!\n\n" +
+			i18n.T(lang, "We locked sign-in to your account after several failed attempts.") + "\n" +
+			i18n.T(lang, "If it was you, open the link to unlock it (valid for 24 hours):") + "\n" +
+			appLink("/unlock-account", token) + "\n\n" +
+			i18n.T(lang, "If it was not you, we recommend resetting your password."),
+	})
+}
+
+// UnlockAccount - снять блокировку входа по ссылке из письма
+// POST /api/auth/unlock
+func UnlockAccount(c *gin.Context) {
+	var req UnlockAccountRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ErrorResponse(c, 400, "Invalid request data: "+err.Error())
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	userToken, err := consumeUserToken(ctx, req.Token, models.UserTokenAccountUnlock)
+	if err != nil {
+		utils.ErrorResponse(c, 500, "Failed to unlock account")
+		return
+	}
+	if userToken == nil {
+		utils.ErrorResponse(c, 400, "Invalid or expired unlock link")
+		return
+	}
+
+	if err := clearLoginFailures(ctx, userToken.Email); err != nil {
+		utils.ErrorResponse(c, 500, "Failed to unlock account")
+		return
+	}
+
+	recordAudit(ctx, models.AuditEntry{
+		Type:    models.AuditAccountUnlocked,
+		UserID:  userToken.UserID,
+		Email:   strings.ToLower(userToken.Email),
+		IP:      c.ClientIP(),
+		Details: "unlocked via email link",
+	})
+
+	utils.SuccessWithMessage(c, 200, "Account unlocked. You can sign in now", nil)
+}
+
+// AdminUnlockUser - снять блокировку входа пользователя (admin only)
+// POST /api/admin/users/:id/unlock
+func AdminUnlockUser(c *gin.Context) {
+	userID, err := primitive.ObjectIDFromHex(c.Param("id"))
+	if err != nil {
+		utils.ErrorResponse(c, 400, "Invalid user ID")
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	var user models.User
+	if err := config.GetCollection("users").FindOne(ctx, bson.M{"_id": userID}).Decode(&user); err != nil {
+		utils.ErrorResponse(c, 404, "User not found")
+		return
+	}
+
+	if err := clearLoginFailures(ctx, user.Email); err != nil {
+		utils.ErrorResponse(c, 500, "Failed to unlock account")
+		return
+	}
+
+	adminID, _ := c.Get("userId")
+	adminObjectID, _ := primitive.ObjectIDFromHex(adminID.(string))
+	recordAudit(ctx, models.AuditEntry{
+		Type:    models.AuditAccountUnlocked,
+		UserID:  user.ID,
+		ActorID: adminObjectID,
+		Email:   strings.ToLower(user.Email),
+		IP:      c.ClientIP(),
+		Details: "unlocked by admin",
+	})
+
+	utils.SuccessWithMessage(c, 200, "Account unlocked", gin.H{
+		"userId": user.ID,
+		"email":  user.Email,
+	})
+}
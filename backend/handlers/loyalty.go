@@ -0,0 +1,428 @@
+package handlers
+
+import (
+	"cinema-booking/config"
+	"cinema-booking/models"
+	"cinema-booking/utils"
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// Ставка начисления по умолчанию: баллов за каждые 100 KZT
+const defaultPointsPer100 = 3.0
+
+// loyaltyTier - уровень программы лояльности
+type loyaltyTier struct {
+	Name       string  `json:"name"`
+	MinSpend   float64 `json:"minSpend"`   // траты за последние 12 месяцев, KZT
+	Multiplier float64 `json:"multiplier"` // множитель начисления баллов
+}
+
+// Уровни по возрастанию порога трат
+var loyaltyTiers = []loyaltyTier{
+	{Name: "Bronze", MinSpend: 0, Multiplier: 1},
+	{Name: "Silver", MinSpend: 50000, Multiplier: 1.25},
+	{Name: "Gold", MinSpend: 150000, Multiplier: 1.5},
+	{Name: "Platinum", MinSpend: 400000, Multiplier: 2},
+}
+
+// tierForSpend - текущий и следующий уровень для суммы трат
+func tierForSpend(spend float64) (loyaltyTier, *loyaltyTier) {
+	current := loyaltyTiers[0]
+	for i, tier := range loyaltyTiers {
+		if spend < tier.MinSpend {
+			return current, &loyaltyTiers[i]
+		}
+		current = tier
+	}
+	return current, nil
+}
+
+// rollingSpend - сумма подтвержденных броней пользователя за последние 12 месяцев
+// (без билетов по абонементу: они оплачены ценой тарифа)
+func rollingSpend(ctx context.Context, userID primitive.ObjectID) (float64, error) {
+	pipeline := bson.A{
+		bson.M{"$match": bson.M{
+			"userId":         userID,
+			"status":         "confirmed",
+			"payment.method": bson.M{"$ne": "pass"},
+			"createdAt":      bson.M{"$gte": time.Now().AddDate(-1, 0, 0)},
+		}},
+		bson.M{"$group": bson.M{
+			"_id":   nil,
+			"total": bson.M{"$sum": "$totalAmount"},
+		}},
+	}
+
+	cursor, err := config.GetCollection("bookings").Aggregate(ctx, pipeline)
+	if err != nil {
+		return 0, err
+	}
+	defer cursor.Close(ctx)
+
+	var results []struct {
+		Total float64 `bson:"total"`
+	}
+	if err := cursor.All(ctx, &results); err != nil {
+		return 0, err
+	}
+	if len(results) == 0 {
+		return 0, nil
+	}
+	return results[0].Total, nil
+}
+
+// loyaltyRateFor - ставка начисления: кинотеатр+формат > кинотеатр > формат > по умолчанию
+func loyaltyRateFor(ctx context.Context, cinemaID primitive.ObjectID, format string) float64 {
+	cursor, err := config.GetCollection("loyalty_rates").Find(ctx, bson.M{
+		"isActive": true,
+		"cinemaId": bson.M{"$in": bson.A{cinemaID, nil}},
+		"format":   bson.M{"$in": bson.A{format, nil, ""}},
+	})
+	if err != nil {
+		return defaultPointsPer100
+	}
+	defer cursor.Close(ctx)
+
+	var rates []models.LoyaltyRate
+	if err := cursor.All(ctx, &rates); err != nil {
+		return defaultPointsPer100
+	}
+
+	rate, bestScore := defaultPointsPer100, -1
+	for _, r := range rates {
+		score := 0
+		if !r.CinemaID.IsZero() {
+			score += 2
+		}
+		if r.Format != "" {
+			score++
+		}
+		if score > bestScore {
+			rate, bestScore = r.PointsPer100, score
+		}
+	}
+	return rate
+}
+
+// recordLoyaltyTransaction - запись в истории баллов
+func recordLoyaltyTransaction(ctx context.Context, userID primitive.ObjectID, txType string, points int, bookingID primitive.ObjectID, description string) {
+	transaction := models.LoyaltyTransaction{
+		UserID:      userID,
+		Type:        txType,
+		Points:      points,
+		BookingID:   bookingID,
+		Description: description,
+		CreatedAt:   time.Now(),
+	}
+	if _, err := config.GetCollection("loyalty_transactions").InsertOne(ctx, transaction); err != nil {
+		fmt.Printf("Warning: failed to record loyalty transaction: %v\n", err)
+	}
+}
+
+// awardLoyaltyPoints - начислить баллы за подтвержденную бронь (один раз на бронь)
+func awardLoyaltyPoints(ctx context.Context, booking models.Booking, showtime models.Showtime) int {
+	// Баллы не начисляются на часть, оплаченную баллами, и на билеты по абонементу
+	base := booking.TotalAmount - float64(booking.Payment.LoyaltyPoints)
+	if base <= 0 || booking.Payment.Method == "pass" {
+		return 0
+	}
+
+	spend, _ := rollingSpend(ctx, booking.UserID)
+	tier, _ := tierForSpend(spend)
+	rate := loyaltyRateFor(ctx, showtime.CinemaID, showtime.Format)
+
+	points := int(math.Floor(base / 100 * rate * tier.Multiplier))
+	if points <= 0 {
+		return 0
+	}
+
+	// Отметить бронь атомарно, чтобы не начислить баллы дважды
+	result, err := config.GetCollection("bookings").UpdateOne(ctx,
+		bson.M{"_id": booking.ID, "pointsEarned": bson.M{"$exists": false}},
+		bson.M{"$set": bson.M{"pointsEarned": points}},
+	)
+	if err != nil || result.ModifiedCount == 0 {
+		return 0
+	}
+
+	_, err = config.GetCollection("users").UpdateOne(ctx,
+		bson.M{"_id": booking.UserID},
+		bson.M{"$inc": bson.M{"loyalty.points": points, "loyalty.lifetimeEarn": points}},
+	)
+	if err != nil {
+		fmt.Printf("Warning: failed to award loyalty points: %v\n", err)
+		return 0
+	}
+
+	recordLoyaltyTransaction(ctx, booking.UserID, "earn", points, booking.ID,
+		fmt.Sprintf("Earned for booking %s (%s tier)", booking.BookingNumber, tier.Name))
+
+	return points
+}
+
+// chargeLoyaltyPoints - атомарно списать баллы (только при достаточном балансе)
+func chargeLoyaltyPoints(ctx context.Context, userID primitive.ObjectID, points int) error {
+	result, err := config.GetCollection("users").UpdateOne(ctx,
+		bson.M{"_id": userID, "loyalty.points": bson.M{"$gte": points}},
+		bson.M{"$inc": bson.M{"loyalty.points": -points}},
+	)
+	if err != nil {
+		return errors.New("Failed to redeem loyalty points")
+	}
+	if result.ModifiedCount == 0 {
+		return errors.New("Insufficient loyalty points")
+	}
+	return nil
+}
+
+// refundLoyaltyPoints - вернуть баллы, которыми оплачена бронь
+func refundLoyaltyPoints(ctx context.Context, booking models.Booking) {
+	points := booking.Payment.LoyaltyPoints
+	if points <= 0 {
+		return
+	}
+
+	_, err := config.GetCollection("users").UpdateOne(ctx,
+		bson.M{"_id": booking.UserID},
+		bson.M{"$inc": bson.M{"loyalty.points": points}},
+	)
+	if err != nil {
+		fmt.Printf("Warning: failed to refund loyalty points: %v\n", err)
+		return
+	}
+
+	recordLoyaltyTransaction(ctx, booking.UserID, "refund", points, booking.ID,
+		fmt.Sprintf("Points returned for cancelled booking %s", booking.BookingNumber))
+}
+
+// reverseLoyaltyPoints - отменить начисление за отмененную бронь
+func reverseLoyaltyPoints(ctx context.Context, booking models.Booking) {
+	if booking.PointsEarned <= 0 {
+		return
+	}
+
+	// Баланс не уходит в минус, если баллы уже потрачены; lifetimeEarn уменьшается
+	// на все начисление, чтобы отмененная бронь не считалась заработанной
+	_, err := config.GetCollection("users").UpdateOne(ctx,
+		bson.M{"_id": booking.UserID},
+		bson.A{bson.M{"$set": bson.M{
+			"loyalty.points":       bson.M{"$max": bson.A{0, bson.M{"$subtract": bson.A{"$loyalty.points", booking.PointsEarned}}}},
+			"loyalty.lifetimeEarn": bson.M{"$max": bson.A{0, bson.M{"$subtract": bson.A{"$loyalty.lifetimeEarn", booking.PointsEarned}}}},
+		}}},
+	)
+	if err != nil {
+		fmt.Printf("Warning: failed to reverse loyalty points: %v\n", err)
+		return
+	}
+
+	recordLoyaltyTransaction(ctx, booking.UserID, "reverse", -booking.PointsEarned, booking.ID,
+		fmt.Sprintf("Reversed for cancelled booking %s", booking.BookingNumber))
+}
+
+// GetLoyalty - баланс баллов, уровень и прогресс до следующего уровня
+func GetLoyalty(c *gin.Context) {
+	userID, _ := c.Get("userId")
+	userObjectID, _ := primitive.ObjectIDFromHex(userID.(string))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	var user models.User
+	if err := config.GetCollection("users").FindOne(ctx, bson.M{"_id": userObjectID}).Decode(&user); err != nil {
+		utils.ErrorResponse(c, 404, "User not found")
+		return
+	}
+
+	spend, err := rollingSpend(ctx, userObjectID)
+	if err != nil {
+		utils.ErrorResponse(c, 500, "Failed to calculate spend")
+		return
+	}
+
+	tier, next := tierForSpend(spend)
+
+	progress := gin.H{
+		"spendLast12Months": roundMoney(spend),
+		"nextTier":          nil,
+		"amountToNextTier":  0,
+		"percent":           100,
+	}
+	if next != nil {
+		progress["nextTier"] = next.Name
+		progress["amountToNextTier"] = roundMoney(next.MinSpend - spend)
+		progress["percent"] = math.Round((spend-tier.MinSpend)/(next.MinSpend-tier.MinSpend)*1000) / 10
+	}
+
+	utils.SuccessResponse(c, 200, gin.H{
+		"points":       user.Loyalty.Points,
+		"lifetimeEarn": user.Loyalty.LifetimeEarn,
+		"pointValue":   1, // 1 балл = 1 KZT при оплате
+		"tier":         tier,
+		"progress":     progress,
+		"tiers":        loyaltyTiers,
+	})
+}
+
+// GetLoyaltyHistory - история начислений и списаний баллов
+func GetLoyaltyHistory(c *gin.Context) {
+	userID, _ := c.Get("userId")
+	userObjectID, _ := primitive.ObjectIDFromHex(userID.(string))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	// Пагинация
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "20"))
+	if page < 1 {
+		page = 1
+	}
+	if limit < 1 || limit > 100 {
+		limit = 20
+	}
+	skip := (page - 1) * limit
+
+	filter := bson.M{"userId": userObjectID}
+	if txType := c.Query("type"); txType != "" {
+		filter["type"] = txType
+	}
+
+	loyaltyCollection := config.GetCollection("loyalty_transactions")
+
+	findOptions := options.Find()
+	findOptions.SetSkip(int64(skip))
+	findOptions.SetLimit(int64(limit))
+	findOptions.SetSort(bson.D{{Key: "createdAt", Value: -1}})
+
+	cursor, err := loyaltyCollection.Find(ctx, filter, findOptions)
+	if err != nil {
+		utils.ErrorResponse(c, 500, "Failed to fetch loyalty history")
+		return
+	}
+	defer cursor.Close(ctx)
+
+	history := []models.LoyaltyTransaction{}
+	if err = cursor.All(ctx, &history); err != nil {
+		utils.ErrorResponse(c, 500, "Failed to decode loyalty history")
+		return
+	}
+
+	total, _ := loyaltyCollection.CountDocuments(ctx, filter)
+
+	utils.PaginatedResponse(c, history, page, limit, int(total))
+}
+
+// GetLoyaltyRates - ставки начисления баллов (admin only)
+func GetLoyaltyRates(c *gin.Context) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	cursor, err := config.GetCollection("loyalty_rates").Find(ctx, bson.M{},
+		options.Find().SetSort(bson.D{{Key: "createdAt", Value: -1}}))
+	if err != nil {
+		utils.ErrorResponse(c, 500, "Failed to fetch loyalty rates")
+		return
+	}
+	defer cursor.Close(ctx)
+
+	rates := []models.LoyaltyRate{}
+	if err = cursor.All(ctx, &rates); err != nil {
+		utils.ErrorResponse(c, 500, "Failed to decode loyalty rates")
+		return
+	}
+
+	utils.SuccessResponse(c, 200, gin.H{
+		"defaultPointsPer100": defaultPointsPer100,
+		"rates":               rates,
+	})
+}
+
+// CreateLoyaltyRate - задать ставку для кинотеатра и/или формата (admin only)
+func CreateLoyaltyRate(c *gin.Context) {
+	var rate models.LoyaltyRate
+	if err := c.ShouldBindJSON(&rate); err != nil {
+		utils.ErrorResponse(c, 400, "Invalid request data: "+err.Error())
+		return
+	}
+
+	if rate.PointsPer100 < 0 || rate.PointsPer100 > 100 {
+		utils.ErrorResponse(c, 400, "pointsPer100 must be between 0 and 100")
+		return
+	}
+
+	if rate.CinemaID.IsZero() && rate.Format == "" {
+		utils.ErrorResponse(c, 400, "cinemaId or format is required")
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	ratesCollection := config.GetCollection("loyalty_rates")
+
+	// Одна активная ставка на комбинацию кинотеатр+формат (пустые поля хранятся как отсутствующие)
+	sameScope := bson.M{"isActive": true, "cinemaId": nil, "format": bson.M{"$in": bson.A{nil, ""}}}
+	if !rate.CinemaID.IsZero() {
+		sameScope["cinemaId"] = rate.CinemaID
+	}
+	if rate.Format != "" {
+		sameScope["format"] = rate.Format
+	}
+	ratesCollection.UpdateMany(ctx, sameScope, bson.M{"$set": bson.M{"isActive": false}})
+
+	rate.ID = primitive.NilObjectID
+	rate.IsActive = true
+	rate.CreatedAt = time.Now()
+
+	result, err := ratesCollection.InsertOne(ctx, rate)
+	if err != nil {
+		utils.ErrorResponse(c, 500, "Failed to create loyalty rate")
+		return
+	}
+
+	rate.ID = result.InsertedID.(primitive.ObjectID)
+
+	utils.SuccessWithMessage(c, 201, "Loyalty rate created successfully", rate)
+}
+
+// DeleteLoyaltyRate - отключить ставку (admin only)
+func DeleteLoyaltyRate(c *gin.Context) {
+	rateIDStr := c.Param("id")
+	rateID, err := primitive.ObjectIDFromHex(rateIDStr)
+	if err != nil {
+		utils.ErrorResponse(c, 400, "Invalid loyalty rate ID")
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	result, err := config.GetCollection("loyalty_rates").UpdateOne(ctx,
+		bson.M{"_id": rateID},
+		bson.M{"$set": bson.M{"isActive": false}},
+	)
+	if err != nil {
+		utils.ErrorResponse(c, 500, "Failed to delete loyalty rate")
+		return
+	}
+
+	if result.MatchedCount == 0 {
+		utils.ErrorResponse(c, 404, "Loyalty rate not found")
+		return
+	}
+
+	utils.SuccessWithMessage(c, 200, "Loyalty rate deleted successfully", gin.H{
+		"rateId":  rateIDStr,
+		"deleted": true,
+	})
+}
@@ -0,0 +1,492 @@
+package handlers
+
+import (
+	"cinema-booking/config"
+	"cinema-booking/models"
+	"cinema-booking/utils"
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+const (
+	mfaIssuer         = "Cinema Booking" // название в приложении-аутентификаторе
+	mfaChallengeTTL   = 5 * time.Minute  // между вводом пароля и кода
+	recoveryCodeCount = 10
+	recoveryCodeHalf  = 5 // код вида XXXXX-XXXXX
+)
+
+// MFACodeRequest - код из приложения или резервный код
+type MFACodeRequest struct {
+	Code string `json:"code" binding:"required"`
+}
+
+// MFAVerifyRequest - второй шаг входа
+type MFAVerifyRequest struct {
+	MFAToken string `json:"mfaToken" binding:"required"`
+	Code     string `json:"code" binding:"required"` // TOTP или резервный код
+	DeviceID string `json:"deviceId"`
+}
+
+// currentUser - пользователь из токена (AuthMiddleware)
+func currentUser(ctx context.Context, c *gin.Context) (*models.User, bool) {
+	userID, _ := c.Get("userId")
+	userObjectID, _ := primitive.ObjectIDFromHex(userID.(string))
+
+	var user models.User
+	if err := config.GetCollection("users").FindOne(ctx, bson.M{"_id": userObjectID}).Decode(&user); err != nil {
+		utils.ErrorResponse(c, 404, "User not found")
+		return nil, false
+	}
+	return &user, true
+}
+
+// normalizeRecoveryCode - резервный код без дефисов и пробелов, в верхнем регистре
+func normalizeRecoveryCode(code string) string {
+	code = strings.ToUpper(code)
+	return strings.NewReplacer("-", "", " ", "").Replace(code)
+}
+
+// generateRecoveryCodes - новые резервные коды (показываются один раз) и их хеши для базы
+func generateRecoveryCodes() ([]string, []string, error) {
+	codes := make([]string, recoveryCodeCount)
+	hashes := make([]string, recoveryCodeCount)
+	for i := range codes {
+		code, err := utils.RandomCode(recoveryCodeHalf * 2)
+		if err != nil {
+			return nil, nil, err
+		}
+		codes[i] = code[:recoveryCodeHalf] + "-" + code[recoveryCodeHalf:]
+		hashes[i] = utils.HashToken(code)
+	}
+	return codes, hashes, nil
+}
+
+// verifySecondFactor - проверить код TOTP или резервный код (резервный код одноразовый).
+// Возвращает ok и признак того, что использован резервный код.
+func verifySecondFactor(ctx context.Context, user models.User, code string) (bool, bool, error) {
+	if !user.MFAEnabled || user.MFASecret == "" {
+		return false, false, nil
+	}
+	usersCollection := config.GetCollection("users")
+
+	if step, ok := utils.ValidateTOTP(user.MFASecret, code, time.Now()); ok {
+		// Каждый код принимается один раз: перехваченный код нельзя предъявить повторно
+		result, err := usersCollection.UpdateOne(ctx,
+			bson.M{
+				"_id": user.ID,
+				"$or": bson.A{
+					bson.M{"mfaLastStep": bson.M{"$exists": false}},
+					bson.M{"mfaLastStep": bson.M{"$lt": step}},
+				},
+			},
+			bson.M{"$set": bson.M{"mfaLastStep": step}},
+		)
+		if err != nil {
+			return false, false, err
+		}
+		return result.ModifiedCount > 0, false, nil
+	}
+
+	hash := utils.HashToken(normalizeRecoveryCode(code))
+	result, err := usersCollection.UpdateOne(ctx,
+		bson.M{"_id": user.ID, "mfaRecoveryCodes": hash},
+		bson.M{"$pull": bson.M{"mfaRecoveryCodes": hash}},
+	)
+	if err != nil {
+		return false, false, err
+	}
+	return result.ModifiedCount > 0, result.ModifiedCount > 0, nil
+}
+
+// startMFAChallenge - пароль верный, выдать одноразовый токен для ввода кода
+func startMFAChallenge(ctx context.Context, c *gin.Context, user models.User) {
+	mfaToken, err := createUserToken(ctx, user, models.UserTokenMFAChallenge, mfaChallengeTTL)
+	if err != nil {
+		utils.ErrorResponse(c, 500, "Failed to start two-factor authentication")
+		return
+	}
+
+	utils.SuccessResponse(c, 200, gin.H{
+		"mfaRequired": true,
+		"mfaToken":    mfaToken,
+		"expiresIn":   int(mfaChallengeTTL.Seconds()),
+	})
+}
+
+// VerifyMFA - второй шаг входа: код из приложения или резервный код
+// POST /api/auth/mfa/verify
+func VerifyMFA(c *gin.Context) {
+	var req MFAVerifyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ErrorResponse(c, 400, "Invalid request data: "+err.Error())
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	// Токен одноразовый: после неверного кода нужно снова ввести пароль
+	challenge, err := consumeUserToken(ctx, req.MFAToken, models.UserTokenMFAChallenge)
+	if err != nil {
+		utils.ErrorResponse(c, 500, "Failed to verify code")
+		return
+	}
+	if challenge == nil {
+		utils.ErrorResponse(c, 401, "Sign-in session expired. Please log in again")
+		return
+	}
+
+	var user models.User
+	if err := config.GetCollection("users").FindOne(ctx, bson.M{"_id": challenge.UserID, "email": challenge.Email}).Decode(&user); err != nil {
+		utils.ErrorResponse(c, 401, "Sign-in session expired. Please log in again")
+		return
+	}
+
+	ok, usedRecovery, err := verifySecondFactor(ctx, user, req.Code)
+	if err != nil {
+		utils.ErrorResponse(c, 500, "Failed to verify code")
+		return
+	}
+	if !ok {
+		// Неверные коды считаются неудачными входами: перебор упирается в блокировку
+		handleLoginFailure(ctx, c, user.Email, &user)
+		utils.ErrorResponse(c, 401, "Invalid authentication code")
+		return
+	}
+
+	if err := clearLoginFailures(ctx, user.Email); err != nil {
+		fmt.Printf("Warning: failed to reset login attempts: %v\n", err)
+	}
+
+	if usedRecovery {
+		recordAudit(ctx, models.AuditEntry{
+			Type:    models.AuditMFARecoveryUsed,
+			UserID:  user.ID,
+			Email:   strings.ToLower(user.Email),
+			IP:      c.ClientIP(),
+			Details: fmt.Sprintf("%d recovery codes left", len(user.MFARecoveryCodes)-1),
+		})
+	}
+
+	req.DeviceID = utils.SanitizeString(req.DeviceID)
+	if req.DeviceID != "" {
+		if _, err := revokeSessions(ctx, bson.M{"userId": user.ID, "deviceId": req.DeviceID}); err != nil {
+			fmt.Printf("Warning: failed to revoke previous device session: %v\n", err)
+		}
+	}
+
+	tokens, err := issueTokens(ctx, c, user, req.DeviceID, true, nil)
+	if err != nil {
+		utils.ErrorResponse(c, 500, "Failed to generate token")
+		return
+	}
+
+	tokens["user"] = authUserResponse(user)
+	if usedRecovery {
+		tokens["recoveryCodesLeft"] = len(user.MFARecoveryCodes) - 1
+	}
+	utils.SuccessResponse(c, 200, tokens)
+}
+
+// SetupMFA - начать подключение 2FA: секрет и ссылка otpauth:// для QR кода.
+// Включается только после подтверждения кодом (EnableMFA).
+// POST /api/auth/mfa/setup
+func SetupMFA(c *gin.Context) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	user, found := currentUser(ctx, c)
+	if !found {
+		return
+	}
+	if user.MFAEnabled {
+		utils.ErrorResponse(c, 409, "Two-factor authentication is already enabled")
+		return
+	}
+
+	secret, err := utils.GenerateTOTPSecret()
+	if err != nil {
+		utils.ErrorResponse(c, 500, "Failed to set up two-factor authentication")
+		return
+	}
+
+	_, err = config.GetCollection("users").UpdateOne(ctx,
+		bson.M{"_id": user.ID},
+		bson.M{"$set": bson.M{"mfaPendingSecret": secret, "updatedAt": time.Now()}},
+	)
+	if err != nil {
+		utils.ErrorResponse(c, 500, "Failed to set up two-factor authentication")
+		return
+	}
+
+	utils.SuccessResponse(c, 200, gin.H{
+		"secret":          secret,
+		"provisioningUri": utils.TOTPProvisioningURI(secret, mfaIssuer, user.Email),
+		"issuer":          mfaIssuer,
+		"account":         user.Email,
+	})
+}
+
+// EnableMFA - подтвердить подключение 2FA первым кодом из приложения.
+// Возвращает резервные коды (показываются один раз) и новую пару токенов с mfa;
+// остальные сессии закрываются.
+// POST /api/auth/mfa/enable
+func EnableMFA(c *gin.Context) {
+	var req MFACodeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ErrorResponse(c, 400, "Invalid request data: "+err.Error())
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	user, found := currentUser(ctx, c)
+	if !found {
+		return
+	}
+	if user.MFAEnabled {
+		utils.ErrorResponse(c, 409, "Two-factor authentication is already enabled")
+		return
+	}
+	if user.MFAPendingSecret == "" {
+		utils.ErrorResponse(c, 400, "Start two-factor authentication setup first")
+		return
+	}
+
+	step, ok := utils.ValidateTOTP(user.MFAPendingSecret, req.Code, time.Now())
+	if !ok {
+		utils.ErrorResponse(c, 400, "Invalid authentication code")
+		return
+	}
+
+	codes, hashes, err := generateRecoveryCodes()
+	if err != nil {
+		utils.ErrorResponse(c, 500, "Failed to enable two-factor authentication")
+		return
+	}
+
+	now := time.Now()
+	result, err := config.GetCollection("users").UpdateOne(ctx,
+		bson.M{"_id": user.ID, "mfaPendingSecret": user.MFAPendingSecret},
+		bson.M{
+			"$set": bson.M{
+				"mfaEnabled":       true,
+				"mfaEnabledAt":     now,
+				"mfaSecret":        user.MFAPendingSecret,
+				"mfaLastStep":      step,
+				"mfaRecoveryCodes": hashes,
+				"updatedAt":        now,
+			},
+			"$unset": bson.M{"mfaPendingSecret": ""},
+		},
+	)
+	if err != nil {
+		utils.ErrorResponse(c, 500, "Failed to enable two-factor authentication")
+		return
+	}
+	if result.MatchedCount == 0 {
+		utils.ErrorResponse(c, 409, "Two-factor authentication setup has changed. Start again")
+		return
+	}
+
+	recordAudit(ctx, models.AuditEntry{
+		Type:   models.AuditMFAEnabled,
+		UserID: user.ID,
+		Email:  strings.ToLower(user.Email),
+		IP:     c.ClientIP(),
+	})
+
+	// Сессии без второго фактора больше не нужны
+	if _, err := revokeSessions(ctx, bson.M{"userId": user.ID}); err != nil {
+		fmt.Printf("Warning: failed to revoke sessions after enabling 2FA: %v\n", err)
+	}
+	if err := utils.RevokeToken(ctx, c.GetString("tokenId"), user.ID, c.GetTime("tokenExpiresAt")); err != nil {
+		fmt.Printf("Warning: failed to revoke access token after enabling 2FA: %v\n", err)
+	}
+
+	user.MFAEnabled = true
+	tokens, err := issueTokens(ctx, c, *user, "", true, nil)
+	if err != nil {
+		utils.ErrorResponse(c, 500, "Failed to generate token")
+		return
+	}
+	tokens["user"] = authUserResponse(*user)
+	tokens["recoveryCodes"] = codes
+
+	utils.SuccessWithMessage(c, 200, "Two-factor authentication enabled. Save your recovery codes", tokens)
+}
+
+// RegenerateRecoveryCodes - выпустить новые резервные коды (прежние перестают действовать)
+// POST /api/auth/mfa/recovery-codes
+func RegenerateRecoveryCodes(c *gin.Context) {
+	var req MFACodeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ErrorResponse(c, 400, "Invalid request data: "+err.Error())
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	user, found := currentUser(ctx, c)
+	if !found {
+		return
+	}
+	if !user.MFAEnabled {
+		utils.ErrorResponse(c, 400, "Two-factor authentication is not enabled")
+		return
+	}
+
+	// Только код из приложения: резервным кодом нельзя выпустить новые
+	step, ok := utils.ValidateTOTP(user.MFASecret, req.Code, time.Now())
+	if !ok || step <= user.MFALastStep {
+		utils.ErrorResponse(c, 400, "Invalid authentication code")
+		return
+	}
+
+	codes, hashes, err := generateRecoveryCodes()
+	if err != nil {
+		utils.ErrorResponse(c, 500, "Failed to generate recovery codes")
+		return
+	}
+
+	result, err := config.GetCollection("users").UpdateOne(ctx,
+		bson.M{"_id": user.ID, "mfaLastStep": user.MFALastStep},
+		bson.M{"$set": bson.M{"mfaRecoveryCodes": hashes, "mfaLastStep": step, "updatedAt": time.Now()}},
+	)
+	if err != nil {
+		utils.ErrorResponse(c, 500, "Failed to generate recovery codes")
+		return
+	}
+	if result.MatchedCount == 0 {
+		utils.ErrorResponse(c, 400, "Invalid authentication code")
+		return
+	}
+
+	utils.SuccessWithMessage(c, 200, "New recovery codes generated. Previous codes no longer work", gin.H{
+		"recoveryCodes": codes,
+	})
+}
+
+// DisableMFA - отключить 2FA (код из приложения или резервный код).
+// Для админов и менеджеров кинотеатров 2FA обязательна.
+// POST /api/auth/mfa/disable
+func DisableMFA(c *gin.Context) {
+	var req MFACodeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ErrorResponse(c, 400, "Invalid request data: "+err.Error())
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	user, found := currentUser(ctx, c)
+	if !found {
+		return
+	}
+	if utils.RoleRequiresMFA(user.Role) {
+		utils.ErrorResponse(c, 403, "Two-factor authentication is mandatory for your role")
+		return
+	}
+	if !user.MFAEnabled {
+		utils.ErrorResponse(c, 400, "Two-factor authentication is not enabled")
+		return
+	}
+
+	ok, _, err := verifySecondFactor(ctx, *user, req.Code)
+	if err != nil {
+		utils.ErrorResponse(c, 500, "Failed to verify code")
+		return
+	}
+	if !ok {
+		utils.ErrorResponse(c, 400, "Invalid authentication code")
+		return
+	}
+
+	if err := disableUserMFA(ctx, user.ID); err != nil {
+		utils.ErrorResponse(c, 500, "Failed to disable two-factor authentication")
+		return
+	}
+
+	recordAudit(ctx, models.AuditEntry{
+		Type:   models.AuditMFADisabled,
+		UserID: user.ID,
+		Email:  strings.ToLower(user.Email),
+		IP:     c.ClientIP(),
+	})
+
+	utils.SuccessWithMessage(c, 200, "Two-factor authentication disabled", gin.H{
+		"mfaEnabled": false,
+	})
+}
+
+// disableUserMFA - удалить секрет и резервные коды пользователя
+func disableUserMFA(ctx context.Context, userID primitive.ObjectID) error {
+	_, err := config.GetCollection("users").UpdateOne(ctx,
+		bson.M{"_id": userID},
+		bson.M{
+			"$set": bson.M{"mfaEnabled": false, "updatedAt": time.Now()},
+			"$unset": bson.M{
+				"mfaEnabledAt":     "",
+				"mfaSecret":        "",
+				"mfaPendingSecret": "",
+				"mfaLastStep":      "",
+				"mfaRecoveryCodes": "",
+			},
+		},
+	)
+	return err
+}
+
+// AdminResetMFA - сбросить 2FA пользователя, потерявшего телефон и резервные коды (admin only).
+// Все сессии пользователя закрываются; админам и менеджерам нужно подключить 2FA заново.
+// POST /api/admin/users/:id/mfa/reset
+func AdminResetMFA(c *gin.Context) {
+	userID, err := primitive.ObjectIDFromHex(c.Param("id"))
+	if err != nil {
+		utils.ErrorResponse(c, 400, "Invalid user ID")
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	var user models.User
+	if err := config.GetCollection("users").FindOne(ctx, bson.M{"_id": userID}).Decode(&user); err != nil {
+		utils.ErrorResponse(c, 404, "User not found")
+		return
+	}
+
+	if err := disableUserMFA(ctx, user.ID); err != nil {
+		utils.ErrorResponse(c, 500, "Failed to disable two-factor authentication")
+		return
+	}
+	revoked, err := revokeSessions(ctx, bson.M{"userId": user.ID})
+	if err != nil {
+		fmt.Printf("Warning: failed to revoke sessions after 2FA reset: %v\n", err)
+	}
+
+	adminID, _ := c.Get("userId")
+	adminObjectID, _ := primitive.ObjectIDFromHex(adminID.(string))
+	recordAudit(ctx, models.AuditEntry{
+		Type:    models.AuditMFADisabled,
+		UserID:  user.ID,
+		ActorID: adminObjectID,
+		Email:   strings.ToLower(user.Email),
+		IP:      c.ClientIP(),
+		Details: "reset by admin",
+	})
+
+	utils.SuccessWithMessage(c, 200, "Two-factor authentication reset", gin.H{
+		"userId":          user.ID,
+		"revokedSessions": revoked,
+	})
+}
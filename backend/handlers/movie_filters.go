@@ -0,0 +1,198 @@
+package handlers
+
+import (
+	"cinema-booking/config"
+	"context"
+	"sort"
+	"strings"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// Сортировки GetMovies: поле фильма в MongoDB или вычисляемый ключ ("")
+var movieSortFields = map[string]string{
+	"createdAt":    "createdAt",
+	"title":        "title",
+	"releaseDate":  "releaseDate",
+	"imdbRating":   "imdbRating",
+	"reviewRating": "reviewRating",
+	"duration":     "duration",
+	"popularity":   "", // билеты за последние popularityWindowDays дней
+	"nextShowtime": "", // ближайший сеанс (с учетом фильтров по сеансам)
+}
+
+// Популярность - проданные билеты за последние N дней
+const popularityWindowDays = 30
+
+// queryList - "Action, Drama" -> ["Action", "Drama"]
+func queryList(value string) []string {
+	items := []string{}
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+// ageCeilingConditions - условия $or для фильмов, доступных зрителю maxAge лет: по AgeRestriction,
+// а если он не задан - по рейтингу MPAA (как movieMinAge)
+func ageCeilingConditions(maxAge int) bson.A {
+	restrictedRatings := []string{}
+	for rating, minAge := range ratingMinAge {
+		if minAge > maxAge {
+			restrictedRatings = append(restrictedRatings, rating)
+		}
+	}
+	sort.Strings(restrictedRatings)
+
+	return bson.A{
+		bson.M{"ageRestriction": bson.M{"$gt": 0, "$lte": maxAge}},
+		bson.M{
+			"ageRestriction": bson.M{"$in": bson.A{0, nil}},
+			"rating":         bson.M{"$nin": restrictedRatings},
+		},
+	}
+}
+
+// movieIDsWithShowtimes - фильмы, у которых есть сеансы по фильтру
+func movieIDsWithShowtimes(ctx context.Context, showtimeFilter bson.M) ([]primitive.ObjectID, error) {
+	values, err := config.GetCollection("showtimes").Distinct(ctx, "movieId", showtimeFilter)
+	if err != nil {
+		return nil, err
+	}
+	ids := []primitive.ObjectID{}
+	for _, value := range values {
+		if id, ok := value.(primitive.ObjectID); ok {
+			ids = append(ids, id)
+		}
+	}
+	return ids, nil
+}
+
+// moviePopularity - проданные билеты по фильмам (подтвержденные брони за последние N дней)
+func moviePopularity(ctx context.Context) (map[primitive.ObjectID]float64, error) {
+	cursor, err := config.GetCollection("bookings").Aggregate(ctx, bson.A{
+		bson.M{"$match": bson.M{
+			"status":    "confirmed",
+			"createdAt": bson.M{"$gte": time.Now().AddDate(0, 0, -popularityWindowDays)},
+		}},
+		bson.M{"$lookup": bson.M{
+			"from":         "showtimes",
+			"localField":   "showtimeId",
+			"foreignField": "_id",
+			"as":           "showtime",
+		}},
+		bson.M{"$unwind": "$showtime"},
+		bson.M{"$group": bson.M{
+			"_id":     "$showtime.movieId",
+			"tickets": bson.M{"$sum": bson.M{"$size": "$seats"}},
+		}},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var rows []struct {
+		MovieID primitive.ObjectID `bson:"_id"`
+		Tickets float64            `bson:"tickets"`
+	}
+	if err := cursor.All(ctx, &rows); err != nil {
+		return nil, err
+	}
+
+	scores := map[primitive.ObjectID]float64{}
+	for _, row := range rows {
+		scores[row.MovieID] = row.Tickets
+	}
+	return scores, nil
+}
+
+// movieNextShowtimes - ближайший сеанс каждого фильма по фильтру сеансов
+func movieNextShowtimes(ctx context.Context, showtimeFilter bson.M) (map[primitive.ObjectID]time.Time, error) {
+	cursor, err := config.GetCollection("showtimes").Aggregate(ctx, bson.A{
+		bson.M{"$match": showtimeFilter},
+		bson.M{"$group": bson.M{
+			"_id":  "$movieId",
+			"next": bson.M{"$min": "$startTime"},
+		}},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var rows []struct {
+		MovieID primitive.ObjectID `bson:"_id"`
+		Next    time.Time          `bson:"next"`
+	}
+	if err := cursor.All(ctx, &rows); err != nil {
+		return nil, err
+	}
+
+	next := map[primitive.ObjectID]time.Time{}
+	for _, row := range rows {
+		next[row.MovieID] = row.Next
+	}
+	return next, nil
+}
+
+// rankedMovieIDs - ID фильмов по фильтру, отсортированные по вычисляемому ключу.
+// Каталог небольшой, поэтому сортируем в памяти, а документы загружаем только для страницы.
+func rankedMovieIDs(ctx context.Context, filter bson.M, sortBy string, sortDirection int, showtimeFilter bson.M) ([]primitive.ObjectID, error) {
+	cursor, err := config.GetCollection("movies").Find(ctx, filter,
+		options.Find().
+			SetProjection(bson.M{"_id": 1, "title": 1}).
+			SetSort(bson.D{{Key: "title", Value: 1}, {Key: "_id", Value: 1}}),
+	)
+	if err != nil {
+		return nil, err
+	}
+	var movies []struct {
+		ID primitive.ObjectID `bson:"_id"`
+	}
+	if err := cursor.All(ctx, &movies); err != nil {
+		return nil, err
+	}
+
+	ids := make([]primitive.ObjectID, len(movies))
+	for i, movie := range movies {
+		ids[i] = movie.ID
+	}
+
+	switch sortBy {
+	case "popularity":
+		scores, err := moviePopularity(ctx)
+		if err != nil {
+			return nil, err
+		}
+		sort.SliceStable(ids, func(i, j int) bool {
+			if sortDirection < 0 {
+				return scores[ids[i]] > scores[ids[j]]
+			}
+			return scores[ids[i]] < scores[ids[j]]
+		})
+
+	case "nextShowtime":
+		next, err := movieNextShowtimes(ctx, showtimeFilter)
+		if err != nil {
+			return nil, err
+		}
+		// Фильмы без будущих сеансов - всегда в конце
+		sort.SliceStable(ids, func(i, j int) bool {
+			a, aOK := next[ids[i]]
+			b, bOK := next[ids[j]]
+			if aOK != bOK {
+				return aOK
+			}
+			if sortDirection < 0 {
+				return a.After(b)
+			}
+			return a.Before(b)
+		})
+	}
+
+	return ids, nil
+}
@@ -0,0 +1,741 @@
+package handlers
+
+import (
+	"bytes"
+	"cinema-booking/config"
+	"cinema-booking/models"
+	"cinema-booking/utils"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// Базовый адрес постеров TMDb (poster_path - относительный путь)
+const tmdbPosterBaseURL = "https://image.tmdb.org/t/p/w500"
+
+// Сколько актеров брать из полного списка TMDb
+const importCastLimit = 10
+
+// Жанры внешних источников -> наш справочник (i18n terms). Ключ - в нижнем регистре.
+var importGenreMap = map[string][]string{
+	"action":             {"Action"},
+	"adventure":          {"Adventure"},
+	"action & adventure": {"Action", "Adventure"},
+	"animation":          {"Animation"},
+	"biography":          {"Biography"},
+	"comedy":             {"Comedy"},
+	"crime":              {"Crime"},
+	"documentary":        {"Documentary"},
+	"drama":              {"Drama"},
+	"family":             {"Family"},
+	"kids":               {"Family"},
+	"fantasy":            {"Fantasy"},
+	"history":            {"History"},
+	"horror":             {"Horror"},
+	"music":              {"Musical"},
+	"musical":            {"Musical"},
+	"mystery":            {"Mystery"},
+	"romance":            {"Romance"},
+	"science fiction":    {"Sci-Fi"},
+	"sci-fi":             {"Sci-Fi"},
+	"sci-fi & fantasy":   {"Sci-Fi", "Fantasy"},
+	"sport":              {"Sport"},
+	"sports":             {"Sport"},
+	"thriller":           {"Thriller"},
+	"war":                {"War"},
+	"war & politics":     {"War"},
+	"western":            {"Western"},
+}
+
+// MovieDocument - JSON-документ из источника метаданных: один фильм, массив или {"results": [...]}
+type MovieDocument struct {
+	Source string // имя файла или адрес
+	Data   []byte
+}
+
+// MovieFetcher - источник документов метаданных. FileMovieFetcher читает файлы (фикстуры, выгрузки),
+// живой источник (TMDb/OMDb API) реализует тот же интерфейс.
+type MovieFetcher interface {
+	FetchMovies(ctx context.Context) ([]MovieDocument, error)
+}
+
+// FileMovieFetcher - JSON-файл или каталог с *.json файлами
+type FileMovieFetcher struct {
+	Path string
+}
+
+// FetchMovies - прочитать файл или все *.json файлы каталога (по имени)
+func (f FileMovieFetcher) FetchMovies(ctx context.Context) ([]MovieDocument, error) {
+	info, err := os.Stat(f.Path)
+	if err != nil {
+		return nil, err
+	}
+
+	paths := []string{f.Path}
+	if info.IsDir() {
+		paths, err = filepath.Glob(filepath.Join(f.Path, "*.json"))
+		if err != nil {
+			return nil, err
+		}
+		sort.Strings(paths)
+	}
+
+	docs := []MovieDocument{}
+	for _, path := range paths {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, err
+		}
+		docs = append(docs, MovieDocument{Source: filepath.Base(path), Data: data})
+	}
+	return docs, nil
+}
+
+// StaticMovieFetcher - документы, уже полученные целиком (загрузка через API)
+type StaticMovieFetcher []MovieDocument
+
+// FetchMovies - вернуть документы как есть
+func (f StaticMovieFetcher) FetchMovies(ctx context.Context) ([]MovieDocument, error) {
+	return f, nil
+}
+
+// ImportedMovie - фильм из внешнего источника, приведенный к нашей модели
+type ImportedMovie struct {
+	ExternalID     string   // основной ID: imdb, если известен, иначе tmdb
+	ExternalIDs    []string // все ID из источника - поиск существующего фильма по любому
+	Movie          models.Movie
+	Characters     map[string]string // актер -> персонаж
+	UnmappedGenres []string
+}
+
+// tmdbTranslations - переводы в формате TMDb (append_to_response=translations)
+type tmdbTranslations struct {
+	Translations []struct {
+		Language string `json:"iso_639_1"`
+		Data     struct {
+			Title    string `json:"title"`
+			Overview string `json:"overview"`
+		} `json:"data"`
+	} `json:"translations"`
+}
+
+// tmdbMovie - фильм в формате TMDb (/movie/{id}?append_to_response=credits,translations)
+type tmdbMovie struct {
+	ID          int    `json:"id"`
+	ImdbID      string `json:"imdb_id"`
+	Title       string `json:"title"`
+	Overview    string `json:"overview"`
+	ReleaseDate string `json:"release_date"`
+	Runtime     int    `json:"runtime"`
+	Genres      []struct {
+		Name string `json:"name"`
+	} `json:"genres"`
+	VoteAverage     float64 `json:"vote_average"`
+	Certification   string  `json:"certification"`
+	PosterPath      string  `json:"poster_path"`
+	SpokenLanguages []struct {
+		EnglishName string `json:"english_name"`
+	} `json:"spoken_languages"`
+	Credits struct {
+		Cast []struct {
+			Name      string `json:"name"`
+			Character string `json:"character"`
+			Order     int    `json:"order"`
+		} `json:"cast"`
+		Crew []struct {
+			Name string `json:"name"`
+			Job  string `json:"job"`
+		} `json:"crew"`
+	} `json:"credits"`
+	Translations tmdbTranslations `json:"translations"`
+}
+
+// omdbMovie - фильм в формате OMDb (все значения - строки, "N/A" - нет данных)
+type omdbMovie struct {
+	Title        string           `json:"Title"`
+	Year         string           `json:"Year"`
+	Rated        string           `json:"Rated"`
+	Released     string           `json:"Released"`
+	Runtime      string           `json:"Runtime"`
+	Genre        string           `json:"Genre"`
+	Director     string           `json:"Director"`
+	Actors       string           `json:"Actors"`
+	Plot         string           `json:"Plot"`
+	Language     string           `json:"Language"`
+	Poster       string           `json:"Poster"`
+	ImdbRating   string           `json:"imdbRating"`
+	ImdbID       string           `json:"imdbID"`
+	Translations tmdbTranslations `json:"translations"` // не из OMDb: переводы можно добавить в выгрузку
+}
+
+// ParseMovieFeed - разобрать документ: формат (TMDb или OMDb) определяется по ключам каждого объекта
+func ParseMovieFeed(data []byte) ([]ImportedMovie, error) {
+	data = bytes.TrimSpace(data)
+
+	var objects []json.RawMessage
+	switch {
+	case len(data) > 0 && data[0] == '[':
+		if err := json.Unmarshal(data, &objects); err != nil {
+			return nil, fmt.Errorf("invalid JSON: %v", err)
+		}
+	default:
+		var page struct {
+			Results []json.RawMessage `json:"results"`
+		}
+		if err := json.Unmarshal(data, &page); err != nil {
+			return nil, fmt.Errorf("invalid JSON: %v", err)
+		}
+		if len(page.Results) > 0 {
+			objects = page.Results
+		} else {
+			objects = []json.RawMessage{data}
+		}
+	}
+
+	movies := []ImportedMovie{}
+	for i, raw := range objects {
+		var keys map[string]json.RawMessage
+		if err := json.Unmarshal(raw, &keys); err != nil {
+			return nil, fmt.Errorf("item %d: invalid JSON object: %v", i+1, err)
+		}
+
+		// Ключи OMDb с заглавной буквы / imdbID, у TMDb - snake_case
+		_, hasImdbID := keys["imdbID"]
+		_, hasTitle := keys["Title"]
+		var movie ImportedMovie
+		var err error
+		if hasImdbID || hasTitle {
+			movie, err = parseOMDbMovie(raw)
+		} else {
+			movie, err = parseTMDbMovie(raw)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("item %d: %v", i+1, err)
+		}
+		movies = append(movies, movie)
+	}
+	return movies, nil
+}
+
+// parseTMDbMovie - TMDb -> ImportedMovie
+func parseTMDbMovie(raw json.RawMessage) (ImportedMovie, error) {
+	var src tmdbMovie
+	if err := json.Unmarshal(raw, &src); err != nil {
+		return ImportedMovie{}, fmt.Errorf("invalid TMDb movie: %v", err)
+	}
+
+	result := ImportedMovie{Characters: map[string]string{}}
+	if src.ImdbID != "" {
+		result.ExternalIDs = append(result.ExternalIDs, "imdb:"+src.ImdbID)
+	}
+	if src.ID > 0 {
+		result.ExternalIDs = append(result.ExternalIDs, "tmdb:"+strconv.Itoa(src.ID))
+	}
+	if len(result.ExternalIDs) > 0 {
+		result.ExternalID = result.ExternalIDs[0]
+	}
+
+	movie := &result.Movie
+	movie.Title = strings.TrimSpace(src.Title)
+	movie.Description = strings.TrimSpace(src.Overview)
+	movie.Duration = src.Runtime
+	movie.TMDbRating = src.VoteAverage // оценка пользователей TMDb, не IMDb
+	movie.Rating = src.Certification
+	if src.ReleaseDate != "" {
+		if date, err := time.Parse("2006-01-02", src.ReleaseDate); err == nil {
+			movie.ReleaseDate = date
+		}
+	}
+	if src.PosterPath != "" {
+		movie.PosterURL = tmdbPosterBaseURL + src.PosterPath
+	}
+
+	genres := []string{}
+	for _, g := range src.Genres {
+		genres = append(genres, g.Name)
+	}
+	movie.Genres, result.UnmappedGenres = mapImportGenres(genres)
+
+	for _, lang := range src.SpokenLanguages {
+		if lang.EnglishName != "" {
+			movie.Language = append(movie.Language, lang.EnglishName)
+		}
+	}
+
+	for _, member := range src.Credits.Crew {
+		if member.Job == "Director" {
+			movie.Director = member.Name
+			break
+		}
+	}
+	cast := src.Credits.Cast
+	sort.SliceStable(cast, func(i, j int) bool { return cast[i].Order < cast[j].Order })
+	for _, member := range cast {
+		if len(movie.Cast) == importCastLimit {
+			break
+		}
+		movie.Cast = append(movie.Cast, member.Name)
+		if member.Character != "" {
+			result.Characters[member.Name] = member.Character
+		}
+	}
+
+	applyImportTranslations(movie, src.Translations)
+	return result, validateImportedMovie(&result)
+}
+
+// omdbValue - значение OMDb без "N/A"
+func omdbValue(s string) string {
+	s = strings.TrimSpace(s)
+	if s == "N/A" {
+		return ""
+	}
+	return s
+}
+
+// omdbList - "Action, Sci-Fi" -> ["Action", "Sci-Fi"]
+func omdbList(s string) []string {
+	items := []string{}
+	for _, item := range strings.Split(omdbValue(s), ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+// parseOMDbMovie - OMDb -> ImportedMovie
+func parseOMDbMovie(raw json.RawMessage) (ImportedMovie, error) {
+	var src omdbMovie
+	if err := json.Unmarshal(raw, &src); err != nil {
+		return ImportedMovie{}, fmt.Errorf("invalid OMDb movie: %v", err)
+	}
+
+	result := ImportedMovie{Characters: map[string]string{}}
+	if id := omdbValue(src.ImdbID); id != "" {
+		result.ExternalID = "imdb:" + id
+		result.ExternalIDs = []string{result.ExternalID}
+	}
+
+	movie := &result.Movie
+	movie.Title = omdbValue(src.Title)
+	movie.Description = omdbValue(src.Plot)
+	movie.PosterURL = omdbValue(src.Poster)
+	if rated := omdbValue(src.Rated); rated != "Not Rated" && rated != "Unrated" {
+		movie.Rating = rated
+	}
+	if fields := strings.Fields(omdbValue(src.Runtime)); len(fields) > 0 {
+		movie.Duration, _ = strconv.Atoi(fields[0]) // "148 min"
+	}
+	if rating, err := strconv.ParseFloat(omdbValue(src.ImdbRating), 64); err == nil {
+		movie.IMDBRating = rating
+	}
+
+	// "16 Jul 2010", иначе 1 января года выхода
+	if date, err := time.Parse("02 Jan 2006", omdbValue(src.Released)); err == nil {
+		movie.ReleaseDate = date
+	} else if year, err := strconv.Atoi(strings.TrimSuffix(omdbValue(src.Year), "–")); err == nil {
+		movie.ReleaseDate = time.Date(year, time.January, 1, 0, 0, 0, 0, time.UTC)
+	}
+
+	movie.Genres, result.UnmappedGenres = mapImportGenres(omdbList(src.Genre))
+	movie.Language = omdbList(src.Language)
+	if directors := omdbList(src.Director); len(directors) > 0 {
+		movie.Director = directors[0]
+	}
+	if actors := omdbList(src.Actors); len(actors) > 0 {
+		movie.Cast = actors
+	}
+
+	applyImportTranslations(movie, src.Translations)
+	return result, validateImportedMovie(&result)
+}
+
+// applyImportTranslations - русские и казахские названия и описания
+func applyImportTranslations(movie *models.Movie, translations tmdbTranslations) {
+	for _, t := range translations.Translations {
+		title := strings.TrimSpace(t.Data.Title)
+		overview := strings.TrimSpace(t.Data.Overview)
+		switch strings.ToLower(t.Language) {
+		case "ru":
+			movie.TitleRu, movie.DescriptionRu = title, overview
+		case "kk", "kz":
+			movie.TitleKz, movie.DescriptionKz = title, overview
+		}
+	}
+}
+
+// mapImportGenres - привести жанры к нашему справочнику; неизвестные возвращаются отдельно
+func mapImportGenres(names []string) ([]string, []string) {
+	mapped := []string{}
+	unmapped := []string{}
+	seen := map[string]bool{}
+	for _, name := range names {
+		genres, ok := importGenreMap[strings.ToLower(strings.TrimSpace(name))]
+		if !ok {
+			unmapped = append(unmapped, name)
+			continue
+		}
+		for _, genre := range genres {
+			if !seen[genre] {
+				seen[genre] = true
+				mapped = append(mapped, genre)
+			}
+		}
+	}
+	return mapped, unmapped
+}
+
+// validateImportedMovie - обязательные поля
+func validateImportedMovie(m *ImportedMovie) error {
+	if m.ExternalID == "" {
+		return fmt.Errorf("external ID (imdb_id/imdbID or TMDb id) is required")
+	}
+	if m.Movie.Title == "" {
+		return fmt.Errorf("%s: title is required", m.ExternalID)
+	}
+	return nil
+}
+
+// MovieImportDiff - изменившееся поле существующего фильма
+type MovieImportDiff struct {
+	Field string      `json:"field"`
+	Old   interface{} `json:"old"`
+	New   interface{} `json:"new"`
+}
+
+// MovieImportItem - результат по одному фильму
+type MovieImportItem struct {
+	Source     string             `json:"source"`
+	ExternalID string             `json:"externalId"`
+	Title      string             `json:"title"`
+	MovieID    primitive.ObjectID `json:"movieId,omitempty"`
+	Action     string             `json:"action"` // "created", "updated", "unchanged"
+	Diffs      []MovieImportDiff  `json:"diffs,omitempty"`
+	Warnings   []string           `json:"warnings,omitempty"`
+}
+
+// MovieImportError - документ или фильм, который не удалось обработать
+type MovieImportError struct {
+	Source string `json:"source"`
+	Error  string `json:"error"`
+}
+
+// MovieImportResult - результат импорта метаданных
+type MovieImportResult struct {
+	Documents int                `json:"documents"`
+	Created   int                `json:"created"`
+	Updated   int                `json:"updated"`
+	Unchanged int                `json:"unchanged"`
+	Failed    int                `json:"failed"`
+	DryRun    bool               `json:"dryRun"`
+	Movies    []MovieImportItem  `json:"movies"`
+	Errors    []MovieImportError `json:"errors"`
+}
+
+// movieImportDiffs - отличия импортированных данных от фильма в базе.
+// Пустые значения источника не затирают данные, введенные вручную.
+func movieImportDiffs(existing *models.Movie, imported *ImportedMovie) ([]MovieImportDiff, bson.M) {
+	diffs := []MovieImportDiff{}
+	set := bson.M{}
+	check := func(field string, old, new interface{}, provided bool) {
+		if !provided || reflect.DeepEqual(old, new) {
+			return
+		}
+		diffs = append(diffs, MovieImportDiff{Field: field, Old: old, New: new})
+		set[field] = new
+	}
+
+	m := &imported.Movie
+	check("externalId", existing.ExternalID, imported.ExternalID, true)
+	check("externalIds", existing.ExternalIDs, mergeExternalIDs(existing, imported), true)
+	check("title", existing.Title, m.Title, m.Title != "")
+	check("titleRu", existing.TitleRu, m.TitleRu, m.TitleRu != "")
+	check("titleKz", existing.TitleKz, m.TitleKz, m.TitleKz != "")
+	check("description", existing.Description, m.Description, m.Description != "")
+	check("descriptionRu", existing.DescriptionRu, m.DescriptionRu, m.DescriptionRu != "")
+	check("descriptionKz", existing.DescriptionKz, m.DescriptionKz, m.DescriptionKz != "")
+	check("director", existing.Director, m.Director, m.Director != "")
+	check("cast", existing.Cast, m.Cast, len(m.Cast) > 0)
+	check("genres", existing.Genres, m.Genres, len(m.Genres) > 0)
+	check("language", existing.Language, m.Language, len(m.Language) > 0)
+	check("duration", existing.Duration, m.Duration, m.Duration > 0)
+	check("rating", existing.Rating, m.Rating, m.Rating != "")
+	check("imdbRating", existing.IMDBRating, m.IMDBRating, m.IMDBRating > 0)
+	check("tmdbRating", existing.TMDbRating, m.TMDbRating, m.TMDbRating > 0)
+	// Загруженный в GridFS постер важнее внешней ссылки
+	check("posterUrl", existing.PosterURL, m.PosterURL, m.PosterURL != "" && existing.PosterFileID.IsZero())
+	if !m.ReleaseDate.IsZero() && !m.ReleaseDate.Equal(existing.ReleaseDate) {
+		diffs = append(diffs, MovieImportDiff{Field: "releaseDate", Old: existing.ReleaseDate, New: m.ReleaseDate})
+		set["releaseDate"] = m.ReleaseDate
+	}
+
+	return diffs, set
+}
+
+// mergeExternalIDs - ID фильма в базе плюс новые ID из источника (без повторов, в исходном порядке)
+func mergeExternalIDs(existing *models.Movie, imported *ImportedMovie) []string {
+	ids := []string{}
+	seen := map[string]bool{}
+	add := func(id string) {
+		if id != "" && !seen[id] {
+			seen[id] = true
+			ids = append(ids, id)
+		}
+	}
+	add(existing.ExternalID)
+	for _, id := range existing.ExternalIDs {
+		add(id)
+	}
+	add(imported.ExternalID)
+	for _, id := range imported.ExternalIDs {
+		add(id)
+	}
+	return ids
+}
+
+// findMovieForImport - фильм с любым из внешних ID источника (фильм, импортированный
+// раньше только с tmdb id, находится и по imdb), иначе добавленный вручную фильм
+// с тем же названием и годом выхода
+func findMovieForImport(ctx context.Context, imported *ImportedMovie) (*models.Movie, error) {
+	moviesCollection := config.GetCollection("movies")
+
+	var movie models.Movie
+	err := moviesCollection.FindOne(ctx, bson.M{"$or": bson.A{
+		bson.M{"externalId": bson.M{"$in": imported.ExternalIDs}},
+		bson.M{"externalIds": bson.M{"$in": imported.ExternalIDs}},
+	}}).Decode(&movie)
+	if err == nil {
+		return &movie, nil
+	}
+	if err != mongo.ErrNoDocuments {
+		return nil, err
+	}
+	if imported.Movie.ReleaseDate.IsZero() {
+		return nil, nil
+	}
+
+	year := imported.Movie.ReleaseDate.Year()
+	err = moviesCollection.FindOne(ctx, bson.M{
+		"externalId": bson.M{"$exists": false},
+		"title":      imported.Movie.Title,
+		"releaseDate": bson.M{
+			"$gte": time.Date(year, time.January, 1, 0, 0, 0, 0, time.UTC),
+			"$lt":  time.Date(year+1, time.January, 1, 0, 0, 0, 0, time.UTC),
+		},
+	}).Decode(&movie)
+	if err == mongo.ErrNoDocuments {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &movie, nil
+}
+
+// setImportedCharacters - персонажи актеров из источника (если не заданы вручную)
+func setImportedCharacters(movie *models.Movie, characters map[string]string) {
+	for i := range movie.Credits {
+		credit := &movie.Credits[i]
+		if credit.Role == models.PersonRoleActor && credit.Character == "" {
+			credit.Character = characters[credit.Name]
+		}
+	}
+}
+
+// importMovie - создать фильм или обновить отличающиеся поля
+func importMovie(ctx context.Context, source string, imported *ImportedMovie, dryRun bool) (MovieImportItem, error) {
+	item := MovieImportItem{
+		Source:     source,
+		ExternalID: imported.ExternalID,
+		Title:      imported.Movie.Title,
+	}
+	for _, genre := range imported.UnmappedGenres {
+		item.Warnings = append(item.Warnings, fmt.Sprintf("Unknown genre %q skipped", genre))
+	}
+
+	existing, err := findMovieForImport(ctx, imported)
+	if err != nil {
+		return item, err
+	}
+	moviesCollection := config.GetCollection("movies")
+
+	// Новый фильм
+	if existing == nil {
+		item.Action = "created"
+		if dryRun {
+			return item, nil
+		}
+
+		movie := imported.Movie
+		movie.ExternalID = imported.ExternalID
+		movie.ExternalIDs = imported.ExternalIDs
+		movie.IsActive = !movie.ReleaseDate.After(time.Now()) // будущие релизы активирует UpdateMovieStatuses
+		movie.CreatedAt = time.Now()
+		if movie.Cast == nil {
+			movie.Cast = []string{}
+		}
+		if movie.Genres == nil {
+			movie.Genres = []string{}
+		}
+		if movie.Language == nil {
+			movie.Language = []string{}
+		}
+		movie.Subtitles = []string{}
+
+		if err := linkMovieCredits(ctx, &movie); err != nil {
+			return item, err
+		}
+		setImportedCharacters(&movie, imported.Characters)
+
+		result, err := moviesCollection.InsertOne(ctx, movie)
+		if err != nil {
+			return item, err
+		}
+		item.MovieID = result.InsertedID.(primitive.ObjectID)
+		return item, nil
+	}
+
+	// Существующий фильм: только отличающиеся поля
+	item.MovieID = existing.ID
+	diffs, set := movieImportDiffs(existing, imported)
+	if len(diffs) == 0 {
+		item.Action = "unchanged"
+		return item, nil
+	}
+	item.Action = "updated"
+	item.Diffs = diffs
+	if dryRun {
+		return item, nil
+	}
+
+	_, directorChanged := set["director"]
+	_, castChanged := set["cast"]
+	if directorChanged || castChanged {
+		updated := *existing
+		if directorChanged {
+			updated.Director = imported.Movie.Director
+		}
+		if castChanged {
+			updated.Cast = imported.Movie.Cast
+		}
+		if err := linkMovieCredits(ctx, &updated); err != nil {
+			return item, err
+		}
+		setImportedCharacters(&updated, imported.Characters)
+		set["credits"] = updated.Credits
+	}
+
+	if _, err := moviesCollection.UpdateOne(ctx, bson.M{"_id": existing.ID}, bson.M{"$set": set}); err != nil {
+		return item, err
+	}
+	return item, nil
+}
+
+// ImportMovies - импортировать метаданные фильмов из источника (upsert по externalId)
+func ImportMovies(ctx context.Context, fetcher MovieFetcher, dryRun bool) (*MovieImportResult, error) {
+	docs, err := fetcher.FetchMovies(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch movie metadata: %w", err)
+	}
+
+	result := &MovieImportResult{
+		Documents: len(docs),
+		DryRun:    dryRun,
+		Movies:    []MovieImportItem{},
+		Errors:    []MovieImportError{},
+	}
+
+	for _, doc := range docs {
+		movies, err := ParseMovieFeed(doc.Data)
+		if err != nil {
+			result.Failed++
+			result.Errors = append(result.Errors, MovieImportError{Source: doc.Source, Error: err.Error()})
+			continue
+		}
+
+		for i := range movies {
+			item, err := importMovie(ctx, doc.Source, &movies[i], dryRun)
+			if err != nil {
+				result.Failed++
+				result.Errors = append(result.Errors, MovieImportError{
+					Source: doc.Source,
+					Error:  fmt.Sprintf("%s: %v", movies[i].ExternalID, err),
+				})
+				continue
+			}
+
+			switch item.Action {
+			case "created":
+				result.Created++
+			case "updated":
+				result.Updated++
+			default:
+				result.Unchanged++
+			}
+			result.Movies = append(result.Movies, item)
+		}
+	}
+
+	if !dryRun && result.Created+result.Updated > 0 {
+		scheduleSearchIndexRebuild()
+	}
+
+	return result, nil
+}
+
+// ImportMovieMetadata - импорт метаданных из JSON в формате TMDb/OMDb (admin only)
+// Файл передается как multipart поле "file" или телом запроса; ?dryRun=true - только отчет.
+func ImportMovieMetadata(c *gin.Context) {
+	var data []byte
+	source := "request"
+
+	if file, header, err := c.Request.FormFile("file"); err == nil {
+		defer file.Close()
+		data, err = io.ReadAll(io.LimitReader(file, config.AppConfig.MaxUploadSize))
+		if err != nil {
+			utils.ErrorResponse(c, 400, "Failed to read file")
+			return
+		}
+		source = header.Filename
+	} else {
+		data, _ = io.ReadAll(io.LimitReader(c.Request.Body, config.AppConfig.MaxUploadSize))
+	}
+
+	if len(bytes.TrimSpace(data)) == 0 {
+		utils.ErrorResponse(c, 400, "Metadata file is required")
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
+	defer cancel()
+
+	result, err := ImportMovies(ctx, StaticMovieFetcher{{Source: source, Data: data}}, c.Query("dryRun") == "true")
+	if err != nil {
+		utils.ErrorResponse(c, 500, err.Error())
+		return
+	}
+
+	message := fmt.Sprintf("Created %d, updated %d, unchanged %d movies", result.Created, result.Updated, result.Unchanged)
+	if result.DryRun {
+		message = "Dry run: " + message
+	}
+
+	utils.SuccessWithMessage(c, 200, message, result)
+}
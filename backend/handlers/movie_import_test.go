@@ -0,0 +1,152 @@
+package handlers
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+)
+
+// Фикстуры testdata/movies: выгрузки TMDb и OMDb для импорта без доступа к API
+func TestParseMovieFeedFixtures(t *testing.T) {
+	type want struct {
+		externalID     string
+		externalIDs    []string
+		title          string
+		titleRu        string
+		titleKz        string
+		director       string
+		cast           []string
+		genres         []string
+		unmappedGenres []string
+		duration       int
+		releaseDate    time.Time
+		rating         string
+		imdbRating     float64
+		tmdbRating     float64
+		posterURL      string
+		characters     map[string]string
+	}
+
+	tests := []struct {
+		file   string
+		movies []want
+	}{
+		{
+			file: "omdb_dune.json",
+			movies: []want{{
+				externalID:  "imdb:tt15239678",
+				externalIDs: []string{"imdb:tt15239678"},
+				title:       "Dune: Part Two",
+				director:    "Denis Villeneuve",
+				cast:        []string{"Timothée Chalamet", "Zendaya", "Rebecca Ferguson"},
+				genres:      []string{"Action", "Adventure", "Drama"},
+				duration:    166,
+				releaseDate: time.Date(2024, time.March, 1, 0, 0, 0, 0, time.UTC),
+				rating:      "PG-13",
+				imdbRating:  8.5,
+				posterURL:   "https://m.media-amazon.com/images/M/MV5BNTc0YmQxMjEtODI5MC00NjFiLTlkMWUtOGQ5NjFmYWUyZGJhXkEyXkFqcGc@._V1_SX300.jpg",
+				characters:  map[string]string{},
+			}},
+		},
+		{
+			file: "tmdb_inception.json",
+			movies: []want{{
+				externalID:  "imdb:tt1375666",
+				externalIDs: []string{"imdb:tt1375666", "tmdb:27205"},
+				title:       "Inception",
+				titleRu:     "Начало",
+				titleKz:     "Бастау",
+				director:    "Christopher Nolan",
+				cast:        []string{"Leonardo DiCaprio", "Joseph Gordon-Levitt", "Ken Watanabe", "Elliot Page"},
+				genres:      []string{"Action", "Sci-Fi", "Adventure"},
+				duration:    148,
+				releaseDate: time.Date(2010, time.July, 15, 0, 0, 0, 0, time.UTC),
+				rating:      "PG-13",
+				tmdbRating:  8.4,
+				posterURL:   tmdbPosterBaseURL + "/oYuLEt3zVCKq57qu2F8dT7NIa6f.jpg",
+				characters: map[string]string{
+					"Leonardo DiCaprio":    "Dom Cobb",
+					"Joseph Gordon-Levitt": "Arthur",
+					"Ken Watanabe":         "Saito",
+					"Elliot Page":          "Ariadne",
+				},
+			}},
+		},
+		{
+			file: "tmdb_search_page.json",
+			movies: []want{
+				{
+					externalID:  "tmdb:1022789",
+					externalIDs: []string{"tmdb:1022789"},
+					title:       "Inside Out 2",
+					genres:      []string{"Animation", "Family", "Comedy"},
+					duration:    97,
+					releaseDate: time.Date(2024, time.June, 11, 0, 0, 0, 0, time.UTC),
+					tmdbRating:  7.6,
+					posterURL:   tmdbPosterBaseURL + "/vpnVM9B6NMmQpWeZvzLvDESb2QY.jpg",
+					characters:  map[string]string{},
+				},
+				{
+					externalID:     "tmdb:945961",
+					externalIDs:    []string{"tmdb:945961"},
+					title:          "Alien: Romulus",
+					genres:         []string{"Horror", "Sci-Fi"},
+					unmappedGenres: []string{"TV Movie"},
+					duration:       119,
+					releaseDate:    time.Date(2024, time.August, 13, 0, 0, 0, 0, time.UTC),
+					tmdbRating:     7.1,
+					characters:     map[string]string{},
+				},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.file, func(t *testing.T) {
+			data, err := os.ReadFile(filepath.Join("..", "testdata", "movies", tt.file))
+			if err != nil {
+				t.Fatalf("read fixture: %v", err)
+			}
+
+			movies, err := ParseMovieFeed(data)
+			if err != nil {
+				t.Fatalf("ParseMovieFeed: %v", err)
+			}
+			if len(movies) != len(tt.movies) {
+				t.Fatalf("got %d movies, want %d", len(movies), len(tt.movies))
+			}
+
+			for i, w := range tt.movies {
+				got := movies[i]
+				m := got.Movie
+				check := func(field string, got, want interface{}) {
+					t.Helper()
+					if !reflect.DeepEqual(got, want) {
+						t.Errorf("movie %d %s = %#v, want %#v", i, field, got, want)
+					}
+				}
+
+				check("ExternalID", got.ExternalID, w.externalID)
+				check("ExternalIDs", got.ExternalIDs, w.externalIDs)
+				check("Title", m.Title, w.title)
+				check("TitleRu", m.TitleRu, w.titleRu)
+				check("TitleKz", m.TitleKz, w.titleKz)
+				check("Director", m.Director, w.director)
+				check("Cast", m.Cast, w.cast)
+				check("Genres", m.Genres, w.genres)
+				check("UnmappedGenres", got.UnmappedGenres, append([]string{}, w.unmappedGenres...))
+				check("Duration", m.Duration, w.duration)
+				check("Rating", m.Rating, w.rating)
+				check("IMDBRating", m.IMDBRating, w.imdbRating)
+				check("TMDbRating", m.TMDbRating, w.tmdbRating)
+				check("PosterURL", m.PosterURL, w.posterURL)
+				check("Characters", got.Characters, w.characters)
+				if !m.ReleaseDate.Equal(w.releaseDate) {
+					t.Errorf("movie %d ReleaseDate = %v, want %v", i, m.ReleaseDate, w.releaseDate)
+				}
+			}
+		})
+	}
+}
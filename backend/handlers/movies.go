@@ -2,9 +2,11 @@ package handlers
 
 import (
 	"cinema-booking/config"
+	"cinema-booking/i18n"
 	"cinema-booking/models"
 	"cinema-booking/utils"
 	"context"
+	"fmt"
 	"strconv"
 	"time"
 
@@ -38,14 +40,44 @@ func GetMovies(c *gin.Context) {
 	search := c.Query("search") // ?search=Avatar
 
 	// Фильтры
-	genre := c.Query("genre")         // ?genre=Action
-	language := c.Query("language")   // ?language=English
-	isActive := c.Query("isActive")   // ?isActive=true
-	minRating := c.Query("minRating") // ?minRating=8.0
+	genres := queryList(c.Query("genres"))            // ?genres=Action,Drama
+	genreMatch := c.DefaultQuery("genreMatch", "any") // ?genreMatch=all - все жанры сразу
+	languages := queryList(c.Query("languages"))      // ?languages=Kazakh,Russian
+	isActive := c.Query("isActive")                   // ?isActive=true
+	minRating := c.Query("minRating")                 // ?minRating=8.0
+	personID := c.Query("personId")                   // ?personId=679ef5a2b3c4d5e6f7890abc
+	formats := queryList(c.Query("format"))           // ?format=IMAX - есть сеансы в формате
+	cinemaID := c.Query("cinemaId")                   // ?cinemaId=... - идет в кинотеатре
+	date := c.Query("date")                           // ?date=2026-02-14 - есть сеансы в этот день
+
+	// Старые параметры с одним значением
+	if genre := c.Query("genre"); genre != "" {
+		genres = append(genres, genre)
+	}
+	if language := c.Query("language"); language != "" {
+		languages = append(languages, language)
+	}
 
-	// Сортировка
-	sortBy := c.DefaultQuery("sortBy", "createdAt")  // ?sortBy=imdbRating
-	sortOrder := c.DefaultQuery("sortOrder", "desc") // ?sortOrder=asc
+	// Сортировка: только поля из movieSortFields
+	sortBy := c.DefaultQuery("sortBy", "createdAt") // ?sortBy=popularity
+	sortField, ok := movieSortFields[sortBy]
+	if !ok {
+		utils.ErrorResponse(c, 400, "Invalid sortBy. Use: createdAt, title, releaseDate, imdbRating, reviewRating, duration, popularity, or nextShowtime")
+		return
+	}
+	defaultOrder := "desc"
+	if sortBy == "title" || sortBy == "nextShowtime" {
+		defaultOrder = "asc"
+	}
+	sortDirection := -1
+	if c.DefaultQuery("sortOrder", defaultOrder) == "asc" { // ?sortOrder=asc
+		sortDirection = 1
+	}
+
+	if genreMatch != "any" && genreMatch != "all" {
+		utils.ErrorResponse(c, 400, "Invalid genreMatch. Use: any or all")
+		return
+	}
 
 	// === ПОСТРОЕНИЕ ФИЛЬТРА ===
 
@@ -64,14 +96,28 @@ func GetMovies(c *gin.Context) {
 		filter["$text"] = bson.M{"$search": search}
 	}
 
-	// Фильтр по жанру
-	if genre != "" {
-		filter["genres"] = genre // MongoDB автоматически ищет в массиве
+	// Фильтр по жанрам: любой из списка или все сразу
+	if len(genres) > 0 {
+		if genreMatch == "all" {
+			filter["genres"] = bson.M{"$all": genres}
+		} else {
+			filter["genres"] = bson.M{"$in": genres}
+		}
+	}
+
+	// Фильтр по языкам (любой из списка)
+	if len(languages) > 0 {
+		filter["language"] = bson.M{"$in": languages}
 	}
 
-	// Фильтр по языку
-	if language != "" {
-		filter["language"] = language
+	// Фильтр по режиссеру/актеру
+	if personID != "" {
+		personObjectID, err := primitive.ObjectIDFromHex(personID)
+		if err != nil {
+			utils.ErrorResponse(c, 400, "Invalid person ID")
+			return
+		}
+		filter["credits.personId"] = personObjectID
 	}
 
 	// Фильтр по рейтингу
@@ -82,49 +128,156 @@ func GetMovies(c *gin.Context) {
 		}
 	}
 
-	// === СОРТИРОВКА ===
+	// Продолжительность: ?minDuration=90&maxDuration=150 (минуты)
+	duration := bson.M{}
+	if minDuration, err := strconv.Atoi(c.Query("minDuration")); err == nil {
+		duration["$gte"] = minDuration
+	}
+	if maxDuration, err := strconv.Atoi(c.Query("maxDuration")); err == nil {
+		duration["$lte"] = maxDuration
+	}
+	if len(duration) > 0 {
+		filter["duration"] = duration
+	}
 
-	sortDirection := 1 // ascending
-	if sortOrder == "desc" {
-		sortDirection = -1
+	// Год выхода: ?yearFrom=2020&yearTo=2024 (включительно)
+	releaseDate := bson.M{}
+	if yearFrom, err := strconv.Atoi(c.Query("yearFrom")); err == nil {
+		releaseDate["$gte"] = time.Date(yearFrom, time.January, 1, 0, 0, 0, 0, time.UTC)
+	}
+	if yearTo, err := strconv.Atoi(c.Query("yearTo")); err == nil {
+		releaseDate["$lt"] = time.Date(yearTo+1, time.January, 1, 0, 0, 0, 0, time.UTC)
+	}
+	if len(releaseDate) > 0 {
+		filter["releaseDate"] = releaseDate
 	}
 
-	sortOptions := bson.D{{Key: sortBy, Value: sortDirection}}
+	// Возрастное ограничение не выше: ?maxAge=12
+	if maxAge, err := strconv.Atoi(c.Query("maxAge")); err == nil {
+		filter["$or"] = ageCeilingConditions(maxAge)
+	}
 
-	// === ОПЦИИ ЗАПРОСА ===
+	// === ФИЛЬТРЫ ПО СЕАНСАМ ===
 
-	findOptions := options.Find()
-	findOptions.SetSkip(int64(skip))
-	findOptions.SetLimit(int64(limit))
-	findOptions.SetSort(sortOptions)
+	// Только будущие сеансы; фильтр используется и для сортировки nextShowtime
+	now := time.Now()
+	showtimeFilter := bson.M{"startTime": bson.M{"$gte": now}}
+	byShowtimes := false
 
-	// === ВЫПОЛНЕНИЕ ЗАПРОСА ===
+	if len(formats) > 0 {
+		showtimeFilter["format"] = bson.M{"$in": formats}
+		byShowtimes = true
+	}
+	if cinemaID != "" {
+		cinemaObjectID, err := primitive.ObjectIDFromHex(cinemaID)
+		if err != nil {
+			utils.ErrorResponse(c, 400, "Invalid cinema ID")
+			return
+		}
+		showtimeFilter["cinemaId"] = cinemaObjectID
+		byShowtimes = true
+	}
+	if date != "" {
+		day, err := time.ParseInLocation("2006-01-02", date, time.Local)
+		if err != nil {
+			utils.ErrorResponse(c, 400, "Invalid date. Use: YYYY-MM-DD")
+			return
+		}
+		from := day
+		if from.Before(now) {
+			from = now // прошедшие сеансы сегодняшнего дня не считаем
+		}
+		showtimeFilter["startTime"] = bson.M{"$gte": from, "$lt": day.AddDate(0, 0, 1)}
+		byShowtimes = true
+	}
 
-	cursor, err := moviesCollection.Find(ctx, filter, findOptions)
-	if err != nil {
-		utils.ErrorResponse(c, 500, "Failed to fetch movies")
-		return
+	if byShowtimes {
+		movieIDs, err := movieIDsWithShowtimes(ctx, showtimeFilter)
+		if err != nil {
+			utils.ErrorResponse(c, 500, "Failed to fetch showtimes")
+			return
+		}
+		filter["_id"] = bson.M{"$in": movieIDs}
 	}
-	defer cursor.Close(ctx)
 
-	// Декодировать результаты
+	// === ВЫПОЛНЕНИЕ ЗАПРОСА ===
+
 	var movies []models.Movie
-	if err = cursor.All(ctx, &movies); err != nil {
-		utils.ErrorResponse(c, 500, "Failed to decode movies")
-		return
-	}
+	var total int
+
+	if sortField == "" {
+		// Вычисляемая сортировка (popularity, nextShowtime): ранжируем ID, загружаем страницу
+		ids, err := rankedMovieIDs(ctx, filter, sortBy, sortDirection, showtimeFilter)
+		if err != nil {
+			utils.ErrorResponse(c, 500, "Failed to fetch movies")
+			return
+		}
+		total = len(ids)
 
-	// === ПОДСЧЕТ ОБЩЕГО КОЛИЧЕСТВА ===
+		pageIDs := []primitive.ObjectID{}
+		if skip < len(ids) {
+			pageIDs = ids[skip:min(skip+limit, len(ids))]
+		}
 
-	total, err := moviesCollection.CountDocuments(ctx, filter)
-	if err != nil {
-		utils.ErrorResponse(c, 500, "Failed to count movies")
-		return
+		cursor, err := moviesCollection.Find(ctx, bson.M{"_id": bson.M{"$in": pageIDs}})
+		if err != nil {
+			utils.ErrorResponse(c, 500, "Failed to fetch movies")
+			return
+		}
+		var found []models.Movie
+		if err = cursor.All(ctx, &found); err != nil {
+			utils.ErrorResponse(c, 500, "Failed to decode movies")
+			return
+		}
+
+		// Вернуть порядок ранжирования
+		byID := map[primitive.ObjectID]models.Movie{}
+		for _, movie := range found {
+			byID[movie.ID] = movie
+		}
+		movies = []models.Movie{}
+		for _, id := range pageIDs {
+			if movie, ok := byID[id]; ok {
+				movies = append(movies, movie)
+			}
+		}
+	} else {
+		findOptions := options.Find()
+		findOptions.SetSkip(int64(skip))
+		findOptions.SetLimit(int64(limit))
+		findOptions.SetSort(bson.D{{Key: sortField, Value: sortDirection}, {Key: "_id", Value: 1}}) // _id - стабильная пагинация
+
+		cursor, err := moviesCollection.Find(ctx, filter, findOptions)
+		if err != nil {
+			utils.ErrorResponse(c, 500, "Failed to fetch movies")
+			return
+		}
+		defer cursor.Close(ctx)
+
+		// Декодировать результаты
+		if err = cursor.All(ctx, &movies); err != nil {
+			utils.ErrorResponse(c, 500, "Failed to decode movies")
+			return
+		}
+
+		// === ПОДСЧЕТ ОБЩЕГО КОЛИЧЕСТВА ===
+
+		count, err := moviesCollection.CountDocuments(ctx, filter)
+		if err != nil {
+			utils.ErrorResponse(c, 500, "Failed to count movies")
+			return
+		}
+		total = int(count)
+	}
+
+	lang := i18n.FromContext(c)
+	for i := range movies {
+		localizeMovie(lang, &movies[i])
 	}
 
 	// === ОТВЕТ С ПАГИНАЦИЕЙ ===
 
-	utils.PaginatedResponse(c, movies, page, limit, int(total))
+	utils.PaginatedResponse(c, movies, page, limit, total)
 }
 
 // GetMovieDetails - получить детальную информацию о фильме
@@ -152,17 +305,9 @@ func GetMovieDetails(c *gin.Context) {
 		return
 	}
 
-	// === ДОПОЛНИТЕЛЬНАЯ ИНФОРМАЦИЯ ===
+	localizeMovie(i18n.FromContext(c), &movie)
 
-	// Подсчитать средний рейтинг из отзывов
-	var averageReviewRating float64
-	if len(movie.Reviews) > 0 {
-		var sum float64
-		for _, review := range movie.Reviews {
-			sum += review.Rating
-		}
-		averageReviewRating = sum / float64(len(movie.Reviews))
-	}
+	// === ДОПОЛНИТЕЛЬНАЯ ИНФОРМАЦИЯ ===
 
 	// Найти ближайшие сеансы (опционально)
 	showtimesCollection := config.GetCollection("showtimes")
@@ -188,8 +333,8 @@ func GetMovieDetails(c *gin.Context) {
 		// Вернуть ответ с дополнительной информацией
 		utils.SuccessResponse(c, 200, gin.H{
 			"movie":               movie,
-			"averageReviewRating": averageReviewRating,
-			"totalReviews":        len(movie.Reviews),
+			"averageReviewRating": movie.ReviewRating,
+			"totalReviews":        movie.ReviewCount,
 			"upcomingShowtimes":   len(showtimes),
 		})
 		return
@@ -198,8 +343,8 @@ func GetMovieDetails(c *gin.Context) {
 	// Если не удалось получить сеансы, вернуть только фильм
 	utils.SuccessResponse(c, 200, gin.H{
 		"movie":               movie,
-		"averageReviewRating": averageReviewRating,
-		"totalReviews":        len(movie.Reviews),
+		"averageReviewRating": movie.ReviewRating,
+		"totalReviews":        movie.ReviewCount,
 	})
 }
 
@@ -222,13 +367,20 @@ func CreateMovie(c *gin.Context) {
 	// Установить значения по умолчанию
 	movie.IsActive = true
 	movie.CreatedAt = time.Now()
-	movie.Reviews = []models.Review{} // Пустой массив отзывов
+	movie.ReviewRating = 0
+	movie.ReviewCount = 0
 
 	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 	defer cancel()
 
 	moviesCollection := config.GetCollection("movies")
 
+	// Связать строки Director/Cast с коллекцией people
+	if err := linkMovieCredits(ctx, &movie); err != nil {
+		utils.ErrorResponse(c, 500, "Failed to link movie credits")
+		return
+	}
+
 	// Сохранить в БД
 	result, err := moviesCollection.InsertOne(ctx, movie)
 	if err != nil {
@@ -238,6 +390,8 @@ func CreateMovie(c *gin.Context) {
 
 	movie.ID = result.InsertedID.(primitive.ObjectID)
 
+	scheduleSearchIndexRebuild()
+
 	utils.SuccessWithMessage(c, 201, "Movie created successfully", movie)
 }
 
@@ -262,6 +416,11 @@ func UpdateMovie(c *gin.Context) {
 	delete(updateData, "_id")
 	delete(updateData, "createdAt")
 	delete(updateData, "reviews") // Отзывы обновляются отдельно
+	delete(updateData, "reviewRating")
+	delete(updateData, "reviewCount")
+	delete(updateData, "credits") // Состав меняется через /credits или director/cast
+	delete(updateData, "releasedAt")
+	delete(updateData, "deletedAt")
 
 	// Если нет полей для обновления
 	if len(updateData) == 0 {
@@ -277,6 +436,11 @@ func UpdateMovie(c *gin.Context) {
 	// Использовать $set оператор
 	update := bson.M{"$set": updateData}
 
+	// Повторная активация восстанавливает удаленный фильм
+	if active, ok := updateData["isActive"].(bool); ok && active {
+		update["$unset"] = bson.M{"deletedAt": ""}
+	}
+
 	result, err := moviesCollection.UpdateOne(ctx, bson.M{"_id": movieID}, update)
 	if err != nil {
 		utils.ErrorResponse(c, 500, "Failed to update movie")
@@ -292,6 +456,19 @@ func UpdateMovie(c *gin.Context) {
 	var updatedMovie models.Movie
 	moviesCollection.FindOne(ctx, bson.M{"_id": movieID}).Decode(&updatedMovie)
 
+	// Изменились строки Director/Cast - пересобрать ссылки на people
+	_, directorChanged := updateData["director"]
+	_, castChanged := updateData["cast"]
+	if directorChanged || castChanged {
+		if err := linkMovieCredits(ctx, &updatedMovie); err != nil {
+			fmt.Printf("Warning: failed to link movie credits: %v\n", err)
+		} else if _, err := moviesCollection.UpdateOne(ctx, bson.M{"_id": movieID}, bson.M{"$set": bson.M{"credits": updatedMovie.Credits}}); err != nil {
+			fmt.Printf("Warning: failed to save movie credits: %v\n", err)
+		}
+	}
+
+	scheduleSearchIndexRebuild()
+
 	utils.SuccessWithMessage(c, 200, "Movie updated successfully", updatedMovie)
 }
 
@@ -310,8 +487,8 @@ func DeleteMovie(c *gin.Context) {
 
 	moviesCollection := config.GetCollection("movies")
 
-	// Soft delete - просто деактивировать фильм
-	update := bson.M{"$set": bson.M{"isActive": false}}
+	// Soft delete - деактивировать фильм (deletedAt отличает удаление от окончания проката)
+	update := bson.M{"$set": bson.M{"isActive": false, "deletedAt": time.Now()}}
 
 	result, err := moviesCollection.UpdateOne(ctx, bson.M{"_id": movieID}, update)
 	if err != nil {
@@ -324,6 +501,8 @@ func DeleteMovie(c *gin.Context) {
 		return
 	}
 
+	scheduleSearchIndexRebuild()
+
 	utils.SuccessWithMessage(c, 200, "Movie deleted successfully", gin.H{
 		"movieId": movieIDStr,
 		"deleted": true,
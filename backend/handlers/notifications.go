@@ -0,0 +1,266 @@
+package handlers
+
+import (
+	"cinema-booking/config"
+	"cinema-booking/i18n"
+	"cinema-booking/mailer"
+	"cinema-booking/models"
+	"cinema-booking/utils"
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// enqueueNotifications - поставить уведомления в очередь (status "pending")
+func enqueueNotifications(ctx context.Context, notifications []models.Notification) error {
+	if len(notifications) == 0 {
+		return nil
+	}
+
+	docs := make([]interface{}, len(notifications))
+	now := time.Now()
+	for i := range notifications {
+		notifications[i].Status = "pending"
+		notifications[i].CreatedAt = now
+		docs[i] = notifications[i]
+	}
+
+	_, err := config.GetCollection("notifications").InsertMany(ctx, docs)
+	return err
+}
+
+// notificationText - текст уведомления на языке запроса
+func notificationText(lang string, notification models.Notification, movies map[primitive.ObjectID]*models.Movie) string {
+	switch notification.Type {
+	case models.NotificationTicketsOnSale:
+		title := ""
+		if movie, ok := movies[notification.MovieID]; ok {
+			title = i18n.Pick(lang, map[string]string{
+				i18n.English: movie.Title,
+				i18n.Russian: movie.TitleRu,
+				i18n.Kazakh:  movie.TitleKz,
+			})
+		}
+		return i18n.T(lang, "Tickets are on sale: "+title)
+	default:
+		return notification.Type
+	}
+}
+
+// notificationEmail - письмо по уведомлению (язык - первый из LANG_FALLBACK: язык пользователя не хранится)
+func notificationEmail(lang string, user models.User, notification models.Notification, movies map[primitive.ObjectID]*models.Movie) mailer.Message {
+	body := i18n.T(lang, "Hello") + ", " + user.FullName + "!\n\n" +
+		notificationText(lang, notification, movies) + "\n"
+	if notification.Type == models.NotificationTicketsOnSale {
+		body += i18n.T(lang, "Showtimes for a movie from your watchlist are now available.") + "\n" +
+			i18n.T(lang, "Open the movie page to choose a showtime:") + "\n" +
+			strings.TrimRight(config.AppConfig.AppURL, "/") + "/movie/" + notification.MovieID.Hex()
+	}
+	return mailer.Message{
+		To:      user.Email,
+		Subject: notificationText(lang, notification, movies),
+		Body:    body,
+	}
+}
+
+// SendPendingNotifications - отправить письма по уведомлениям из очереди (status "pending").
+// Уведомление помечается "sent" до отправки, чтобы параллельные запуски не отправили письмо
+// дважды; при ошибке отправки оно возвращается в очередь. Возвращает число отправленных писем.
+func SendPendingNotifications(ctx context.Context) (int, error) {
+	notificationsCollection := config.GetCollection("notifications")
+	cursor, err := notificationsCollection.Find(ctx, bson.M{"status": "pending"},
+		options.Find().SetSort(bson.D{{Key: "createdAt", Value: 1}}).SetLimit(1000))
+	if err != nil {
+		return 0, err
+	}
+	var notifications []models.Notification
+	if err := cursor.All(ctx, &notifications); err != nil {
+		return 0, err
+	}
+	if len(notifications) == 0 {
+		return 0, nil
+	}
+
+	userIDs := []primitive.ObjectID{}
+	movieIDs := []primitive.ObjectID{}
+	for _, n := range notifications {
+		userIDs = append(userIDs, n.UserID)
+		if !n.MovieID.IsZero() {
+			movieIDs = append(movieIDs, n.MovieID)
+		}
+	}
+
+	users := map[primitive.ObjectID]models.User{}
+	cursor, err = config.GetCollection("users").Find(ctx,
+		bson.M{"_id": bson.M{"$in": userIDs}},
+		options.Find().SetProjection(bson.M{"email": 1, "fullName": 1}),
+	)
+	if err != nil {
+		return 0, err
+	}
+	var userList []models.User
+	if err := cursor.All(ctx, &userList); err != nil {
+		return 0, err
+	}
+	for _, user := range userList {
+		users[user.ID] = user
+	}
+
+	movies := map[primitive.ObjectID]*models.Movie{}
+	cursor, err = config.GetCollection("movies").Find(ctx, bson.M{"_id": bson.M{"$in": movieIDs}})
+	if err != nil {
+		return 0, err
+	}
+	var movieList []models.Movie
+	if err := cursor.All(ctx, &movieList); err != nil {
+		return 0, err
+	}
+	for i := range movieList {
+		movies[movieList[i].ID] = &movieList[i]
+	}
+
+	lang := i18n.FallbackOrder()[0]
+	sent := 0
+	for _, n := range notifications {
+		result, err := notificationsCollection.UpdateOne(ctx,
+			bson.M{"_id": n.ID, "status": "pending"},
+			bson.M{"$set": bson.M{"status": "sent", "sentAt": time.Now()}},
+		)
+		if err != nil {
+			return sent, err
+		}
+		if result.ModifiedCount == 0 {
+			continue // уже отправляет другой запуск
+		}
+
+		// Пользователь удален - письмо некому отправлять, уведомление остается "sent"
+		user, ok := users[n.UserID]
+		if !ok || user.Email == "" {
+			continue
+		}
+
+		if err := getMailer().Send(ctx, notificationEmail(lang, user, n, movies)); err != nil {
+			fmt.Printf("Warning: failed to send notification %s: %v\n", n.ID.Hex(), err)
+			if _, err := notificationsCollection.UpdateOne(ctx,
+				bson.M{"_id": n.ID},
+				bson.M{"$set": bson.M{"status": "pending"}, "$unset": bson.M{"sentAt": ""}},
+			); err != nil {
+				fmt.Printf("Warning: failed to requeue notification %s: %v\n", n.ID.Hex(), err)
+			}
+			continue
+		}
+		sent++
+	}
+
+	return sent, nil
+}
+
+// sendPendingNotificationsAsync - отправить письма в фоне, не задерживая ответ
+// (со своим контекстом: контекст запроса к этому моменту может истечь)
+func sendPendingNotificationsAsync() {
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Minute)
+		defer cancel()
+		if _, err := SendPendingNotifications(ctx); err != nil {
+			fmt.Printf("Warning: failed to send notifications: %v\n", err)
+		}
+	}()
+}
+
+// GetMyNotifications - уведомления пользователя (новые первыми)
+// GET /api/notifications?page=1&limit=20
+func GetMyNotifications(c *gin.Context) {
+	userID, _ := c.Get("userId")
+	userObjectID, _ := primitive.ObjectIDFromHex(userID.(string))
+
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "20"))
+	if page < 1 {
+		page = 1
+	}
+	if limit < 1 || limit > 100 {
+		limit = 20
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	notificationsCollection := config.GetCollection("notifications")
+	filter := bson.M{"userId": userObjectID}
+
+	total, err := notificationsCollection.CountDocuments(ctx, filter)
+	if err != nil {
+		utils.ErrorResponse(c, 500, "Failed to fetch notifications")
+		return
+	}
+
+	cursor, err := notificationsCollection.Find(ctx, filter, options.Find().
+		SetSort(bson.D{{Key: "createdAt", Value: -1}}).
+		SetSkip(int64((page-1)*limit)).
+		SetLimit(int64(limit)))
+	if err != nil {
+		utils.ErrorResponse(c, 500, "Failed to fetch notifications")
+		return
+	}
+	var notifications []models.Notification
+	if err := cursor.All(ctx, &notifications); err != nil {
+		utils.ErrorResponse(c, 500, "Failed to decode notifications")
+		return
+	}
+
+	movieIDs := []primitive.ObjectID{}
+	for _, n := range notifications {
+		if !n.MovieID.IsZero() {
+			movieIDs = append(movieIDs, n.MovieID)
+		}
+	}
+	movies := map[primitive.ObjectID]*models.Movie{}
+	if len(movieIDs) > 0 {
+		cursor, err := config.GetCollection("movies").Find(ctx, bson.M{"_id": bson.M{"$in": movieIDs}})
+		if err == nil {
+			var movieList []models.Movie
+			if err := cursor.All(ctx, &movieList); err == nil {
+				for i := range movieList {
+					movies[movieList[i].ID] = &movieList[i]
+				}
+			}
+		}
+	}
+
+	lang := i18n.FromContext(c)
+	results := []gin.H{}
+	for _, n := range notifications {
+		results = append(results, gin.H{
+			"id":        n.ID,
+			"type":      n.Type,
+			"movieId":   n.MovieID,
+			"city":      n.City,
+			"text":      notificationText(lang, n, movies),
+			"readAt":    n.ReadAt,
+			"createdAt": n.CreatedAt,
+		})
+	}
+
+	// Выданные уведомления считаются прочитанными
+	if len(notifications) > 0 {
+		ids := make([]primitive.ObjectID, len(notifications))
+		for i, n := range notifications {
+			ids[i] = n.ID
+		}
+		if _, err := notificationsCollection.UpdateMany(ctx,
+			bson.M{"_id": bson.M{"$in": ids}, "readAt": bson.M{"$exists": false}},
+			bson.M{"$set": bson.M{"readAt": time.Now()}},
+		); err != nil {
+			fmt.Printf("Warning: failed to mark notifications as read: %v\n", err)
+		}
+	}
+
+	utils.PaginatedResponse(c, results, page, limit, int(total))
+}
@@ -0,0 +1,590 @@
+package handlers
+
+import (
+	"cinema-booking/config"
+	"cinema-booking/i18n"
+	"cinema-booking/models"
+	"cinema-booking/search"
+	"cinema-booking/utils"
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// findOrCreatePersonByName - найти персону по имени (с нормализацией) или создать новую.
+// Однофамильцев может быть несколько: берется самая ранняя запись, нужного человека
+// админ указывает через PUT /admin/movies/:id/credits.
+func findOrCreatePersonByName(ctx context.Context, name, role string) (primitive.ObjectID, error) {
+	peopleCollection := config.GetCollection("people")
+	now := time.Now()
+
+	var person models.Person
+	err := peopleCollection.FindOneAndUpdate(ctx,
+		bson.M{"nameKey": search.Normalize(name)},
+		bson.M{
+			"$addToSet": bson.M{"roles": role},
+			"$set":      bson.M{"updatedAt": now},
+		},
+		options.FindOneAndUpdate().SetSort(bson.D{{Key: "createdAt", Value: 1}}).SetReturnDocument(options.After),
+	).Decode(&person)
+	if err == nil {
+		return person.ID, nil
+	}
+	if err != mongo.ErrNoDocuments {
+		return primitive.NilObjectID, err
+	}
+
+	result, err := peopleCollection.InsertOne(ctx, models.Person{
+		Name:      name,
+		NameKey:   search.Normalize(name),
+		Roles:     []string{role},
+		CreatedAt: now,
+		UpdatedAt: now,
+	})
+	if err != nil {
+		return primitive.NilObjectID, err
+	}
+	return result.InsertedID.(primitive.ObjectID), nil
+}
+
+// linkMovieCredits - построить Credits из строк Director/Cast.
+// Ссылки, уже стоящие в Credits (в том числе выбранные через SetMovieCredits), сохраняются
+// вместе с персонажами; по имени ищутся только новые строки без ссылки.
+func linkMovieCredits(ctx context.Context, movie *models.Movie) error {
+	// Существующие ссылки по роли и нормализованному имени (в порядке следования)
+	linked := map[string][]models.MovieCredit{}
+	for _, credit := range movie.Credits {
+		key := credit.Role + "|" + search.Normalize(credit.Name)
+		linked[key] = append(linked[key], credit)
+	}
+
+	credits := []models.MovieCredit{}
+	add := func(name, role string) error {
+		if name == "" {
+			return nil
+		}
+		key := role + "|" + search.Normalize(name)
+		if existing := linked[key]; len(existing) > 0 {
+			credit := existing[0]
+			linked[key] = existing[1:]
+			credit.Name = name
+			credits = append(credits, credit)
+			return nil
+		}
+
+		personID, err := findOrCreatePersonByName(ctx, name, role)
+		if err != nil {
+			return err
+		}
+		credits = append(credits, models.MovieCredit{
+			PersonID: personID,
+			Name:     name,
+			Role:     role,
+		})
+		return nil
+	}
+
+	if err := add(movie.Director, models.PersonRoleDirector); err != nil {
+		return err
+	}
+	for _, name := range movie.Cast {
+		if err := add(name, models.PersonRoleActor); err != nil {
+			return err
+		}
+	}
+
+	movie.Credits = credits
+	return nil
+}
+
+// MigratePeople - перенести строки Director/Cast фильмов в коллекцию people и ссылки Credits.
+// Повторный запуск безопасен: фильмы с Credits пропускаются. Возвращает число фильмов.
+func MigratePeople(ctx context.Context) (int, error) {
+	moviesCollection := config.GetCollection("movies")
+
+	cursor, err := moviesCollection.Find(ctx, bson.M{
+		"credits.0": bson.M{"$exists": false},
+		"$or": bson.A{
+			bson.M{"director": bson.M{"$nin": bson.A{"", nil}}},
+			bson.M{"cast.0": bson.M{"$exists": true}},
+		},
+	})
+	if err != nil {
+		return 0, err
+	}
+	var movies []models.Movie
+	if err := cursor.All(ctx, &movies); err != nil {
+		return 0, err
+	}
+
+	for i := range movies {
+		if err := linkMovieCredits(ctx, &movies[i]); err != nil {
+			return i, err
+		}
+		_, err := moviesCollection.UpdateOne(ctx,
+			bson.M{"_id": movies[i].ID},
+			bson.M{"$set": bson.M{"credits": movies[i].Credits}},
+		)
+		if err != nil {
+			return i, err
+		}
+	}
+
+	return len(movies), nil
+}
+
+// localizePerson - имя на языке запроса
+func localizePerson(lang string, person *models.Person) {
+	person.Name = i18n.Pick(lang, map[string]string{
+		i18n.English: person.Name,
+		i18n.Russian: person.NameRu,
+		i18n.Kazakh:  person.NameKz,
+	})
+}
+
+// GetPerson - персона с фильмографией и ближайшими сеансами
+func GetPerson(c *gin.Context) {
+	personID, err := primitive.ObjectIDFromHex(c.Param("id"))
+	if err != nil {
+		utils.ErrorResponse(c, 400, "Invalid person ID")
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	var person models.Person
+	if err := config.GetCollection("people").FindOne(ctx, bson.M{"_id": personID}).Decode(&person); err != nil {
+		utils.ErrorResponse(c, 404, "Person not found")
+		return
+	}
+
+	// === ФИЛЬМОГРАФИЯ ===
+
+	cursor, err := config.GetCollection("movies").Find(ctx,
+		bson.M{"credits.personId": personID, "isActive": true},
+		options.Find().SetSort(bson.D{{Key: "releaseDate", Value: -1}}),
+	)
+	if err != nil {
+		utils.ErrorResponse(c, 500, "Failed to fetch movies")
+		return
+	}
+	var movies []models.Movie
+	if err := cursor.All(ctx, &movies); err != nil {
+		utils.ErrorResponse(c, 500, "Failed to decode movies")
+		return
+	}
+
+	lang := i18n.FromContext(c)
+	localizePerson(lang, &person)
+
+	filmography := []gin.H{}
+	movieIDs := []primitive.ObjectID{}
+	for i := range movies {
+		movie := &movies[i]
+		movieIDs = append(movieIDs, movie.ID)
+
+		roles := []string{}
+		characters := []string{}
+		for _, credit := range movie.Credits {
+			if credit.PersonID == personID {
+				roles = append(roles, credit.Role)
+				if credit.Character != "" {
+					characters = append(characters, credit.Character)
+				}
+			}
+		}
+
+		localizeMovie(lang, movie)
+		filmography = append(filmography, gin.H{
+			"movieId":     movie.ID,
+			"title":       movie.Title,
+			"releaseDate": movie.ReleaseDate,
+			"posterUrl":   movie.PosterURL,
+			"genres":      movie.Genres,
+			"localized":   movie.Localized,
+			"roles":       roles,
+			"characters":  characters,
+		})
+	}
+
+	// === БЛИЖАЙШИЕ СЕАНСЫ ===
+
+	showtimes := []models.Showtime{}
+	if len(movieIDs) > 0 {
+		cursor, err := config.GetCollection("showtimes").Find(ctx,
+			bson.M{
+				"movieId":   bson.M{"$in": movieIDs},
+				"startTime": bson.M{"$gte": time.Now()},
+			},
+			options.Find().
+				SetSort(bson.D{{Key: "startTime", Value: 1}}).
+				SetLimit(20).
+				SetProjection(bson.M{"bookedSeats": 0}),
+		)
+		if err != nil {
+			utils.ErrorResponse(c, 500, "Failed to fetch showtimes")
+			return
+		}
+		if err := cursor.All(ctx, &showtimes); err != nil {
+			utils.ErrorResponse(c, 500, "Failed to decode showtimes")
+			return
+		}
+		for i := range showtimes {
+			localizeShowtime(lang, &showtimes[i])
+		}
+	}
+
+	utils.SuccessResponse(c, 200, gin.H{
+		"person":            person,
+		"filmography":       filmography,
+		"upcomingShowtimes": showtimes,
+	})
+}
+
+// PersonRequest - создание/изменение персоны
+type PersonRequest struct {
+	Name   string   `json:"name" binding:"required"`
+	NameRu string   `json:"nameRu"`
+	NameKz string   `json:"nameKz"`
+	Roles  []string `json:"roles"`
+	Bio    string   `json:"bio"`
+}
+
+// validatePersonRequest - проверить имя и роли
+func validatePersonRequest(req *PersonRequest) string {
+	req.Name = utils.SanitizeString(req.Name)
+	req.NameRu = utils.SanitizeString(req.NameRu)
+	req.NameKz = utils.SanitizeString(req.NameKz)
+	if len([]rune(req.Name)) < 2 {
+		return "Name must be at least 2 characters"
+	}
+	if req.Roles == nil {
+		req.Roles = []string{}
+	}
+	for _, role := range req.Roles {
+		if role != models.PersonRoleDirector && role != models.PersonRoleActor {
+			return "Invalid role. Use: director or actor"
+		}
+	}
+	return ""
+}
+
+// CreatePerson - добавить персону (admin only)
+func CreatePerson(c *gin.Context) {
+	var req PersonRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ErrorResponse(c, 400, "Invalid request data: "+err.Error())
+		return
+	}
+	if msg := validatePersonRequest(&req); msg != "" {
+		utils.ErrorResponse(c, 400, msg)
+		return
+	}
+
+	person := models.Person{
+		Name:      req.Name,
+		NameRu:    req.NameRu,
+		NameKz:    req.NameKz,
+		NameKey:   search.Normalize(req.Name),
+		Roles:     req.Roles,
+		Bio:       req.Bio,
+		CreatedAt: time.Now(),
+		UpdatedAt: time.Now(),
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	result, err := config.GetCollection("people").InsertOne(ctx, person)
+	if err != nil {
+		utils.ErrorResponse(c, 500, "Failed to create person")
+		return
+	}
+
+	person.ID = result.InsertedID.(primitive.ObjectID)
+	scheduleSearchIndexRebuild()
+
+	utils.SuccessWithMessage(c, 201, "Person created successfully", person)
+}
+
+// UpdatePerson - изменить персону (admin only); новое имя переносится в Credits и Director/Cast фильмов
+func UpdatePerson(c *gin.Context) {
+	personID, err := primitive.ObjectIDFromHex(c.Param("id"))
+	if err != nil {
+		utils.ErrorResponse(c, 400, "Invalid person ID")
+		return
+	}
+
+	var req PersonRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ErrorResponse(c, 400, "Invalid request data: "+err.Error())
+		return
+	}
+	if msg := validatePersonRequest(&req); msg != "" {
+		utils.ErrorResponse(c, 400, msg)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	var oldPerson models.Person
+	err = config.GetCollection("people").FindOneAndUpdate(ctx,
+		bson.M{"_id": personID},
+		bson.M{"$set": bson.M{
+			"name":      req.Name,
+			"nameRu":    req.NameRu,
+			"nameKz":    req.NameKz,
+			"nameKey":   search.Normalize(req.Name),
+			"roles":     req.Roles,
+			"bio":       req.Bio,
+			"updatedAt": time.Now(),
+		}},
+		options.FindOneAndUpdate().SetReturnDocument(options.Before),
+	).Decode(&oldPerson)
+	if err != nil {
+		utils.ErrorResponse(c, 404, "Person not found")
+		return
+	}
+
+	if oldPerson.Name != req.Name {
+		renamePersonInMovies(ctx, personID, oldPerson.Name, req.Name)
+	}
+	scheduleSearchIndexRebuild()
+
+	person := oldPerson
+	person.Name = req.Name
+	person.NameRu = req.NameRu
+	person.NameKz = req.NameKz
+	person.Roles = req.Roles
+	person.Bio = req.Bio
+	person.UpdatedAt = time.Now()
+
+	utils.SuccessWithMessage(c, 200, "Person updated successfully", person)
+}
+
+// renamePersonInMovies - обновить денормализованное имя в фильмах
+func renamePersonInMovies(ctx context.Context, personID primitive.ObjectID, oldName, newName string) {
+	moviesCollection := config.GetCollection("movies")
+
+	_, err := moviesCollection.UpdateMany(ctx,
+		bson.M{"credits.personId": personID},
+		bson.M{"$set": bson.M{"credits.$[credit].name": newName}},
+		options.Update().SetArrayFilters(options.ArrayFilters{
+			Filters: bson.A{bson.M{"credit.personId": personID}},
+		}),
+	)
+	if err != nil {
+		fmt.Printf("Warning: failed to rename person in credits: %v\n", err)
+	}
+
+	_, err = moviesCollection.UpdateMany(ctx,
+		bson.M{"credits.personId": personID, "director": oldName},
+		bson.M{"$set": bson.M{"director": newName}},
+	)
+	if err != nil {
+		fmt.Printf("Warning: failed to rename director: %v\n", err)
+	}
+
+	_, err = moviesCollection.UpdateMany(ctx,
+		bson.M{"credits.personId": personID, "cast": oldName},
+		bson.M{"$set": bson.M{"cast.$": newName}},
+	)
+	if err != nil {
+		fmt.Printf("Warning: failed to rename cast member: %v\n", err)
+	}
+}
+
+// UploadPersonPhoto - загрузить фото персоны (admin only)
+func UploadPersonPhoto(c *gin.Context) {
+	personID, err := primitive.ObjectIDFromHex(c.Param("id"))
+	if err != nil {
+		utils.ErrorResponse(c, 400, "Invalid person ID")
+		return
+	}
+
+	file, header, contentType, reader, ok := receiveUpload(c, config.AppConfig.MaxUploadSize, imageContentTypes)
+	if !ok {
+		return
+	}
+	defer file.Close()
+
+	data, img, ok := readUploadedImage(c, reader, contentType)
+	if !ok {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	peopleCollection := config.GetCollection("people")
+
+	count, err := peopleCollection.CountDocuments(ctx, bson.M{"_id": personID})
+	if err != nil || count == 0 {
+		utils.ErrorResponse(c, 404, "Person not found")
+		return
+	}
+
+	bucket, err := config.GetGridFSBucket()
+	if err != nil {
+		utils.ErrorResponse(c, 500, "Failed to open file storage")
+		return
+	}
+
+	fileID, err := storeImage(bucket, header.Filename, data, img, bson.M{
+		"contentType": contentType,
+		"kind":        "person_photo",
+		"personId":    personID,
+	})
+	if err != nil {
+		utils.ErrorResponse(c, 500, "Failed to store file")
+		return
+	}
+
+	var oldPerson models.Person
+	err = peopleCollection.FindOneAndUpdate(ctx,
+		bson.M{"_id": personID},
+		bson.M{"$set": bson.M{
+			"photoFileId": fileID,
+			"photoUrl":    fileURL(fileID),
+			"updatedAt":   time.Now(),
+		}},
+		options.FindOneAndUpdate().SetReturnDocument(options.Before),
+	).Decode(&oldPerson)
+	if err != nil {
+		if delErr := deleteStoredFile(ctx, bucket, fileID); delErr != nil {
+			fmt.Printf("Warning: failed to delete orphaned file %s: %v\n", fileID.Hex(), delErr)
+		}
+		utils.ErrorResponse(c, 500, "Failed to update person")
+		return
+	}
+
+	if !oldPerson.PhotoFileID.IsZero() {
+		if err := deleteStoredFile(ctx, bucket, oldPerson.PhotoFileID); err != nil {
+			fmt.Printf("Warning: failed to delete replaced file %s: %v\n", oldPerson.PhotoFileID.Hex(), err)
+		}
+	}
+
+	utils.SuccessWithMessage(c, 200, "File uploaded successfully", gin.H{
+		"personId":    personID.Hex(),
+		"fileId":      fileID.Hex(),
+		"url":         fileURL(fileID),
+		"contentType": contentType,
+		"size":        header.Size,
+	})
+}
+
+// MovieCreditsRequest - состав фильма по ID персон
+type MovieCreditsRequest struct {
+	Credits []struct {
+		PersonID  string `json:"personId" binding:"required"`
+		Role      string `json:"role" binding:"required"`
+		Character string `json:"character"`
+	} `json:"credits" binding:"required"`
+}
+
+// SetMovieCredits - задать режиссеров и актеров фильма (admin only).
+// Строки Director/Cast пересобираются из Credits для старых клиентов.
+func SetMovieCredits(c *gin.Context) {
+	movieID, err := primitive.ObjectIDFromHex(c.Param("id"))
+	if err != nil {
+		utils.ErrorResponse(c, 400, "Invalid movie ID")
+		return
+	}
+
+	var req MovieCreditsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ErrorResponse(c, 400, "Invalid request data: "+err.Error())
+		return
+	}
+
+	personIDs := []primitive.ObjectID{}
+	for _, credit := range req.Credits {
+		personID, err := primitive.ObjectIDFromHex(credit.PersonID)
+		if err != nil {
+			utils.ErrorResponse(c, 400, "Invalid person ID")
+			return
+		}
+		if credit.Role != models.PersonRoleDirector && credit.Role != models.PersonRoleActor {
+			utils.ErrorResponse(c, 400, "Invalid role. Use: director or actor")
+			return
+		}
+		personIDs = append(personIDs, personID)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	peopleCollection := config.GetCollection("people")
+
+	cursor, err := peopleCollection.Find(ctx, bson.M{"_id": bson.M{"$in": personIDs}})
+	if err != nil {
+		utils.ErrorResponse(c, 500, "Failed to fetch people")
+		return
+	}
+	var people []models.Person
+	if err := cursor.All(ctx, &people); err != nil {
+		utils.ErrorResponse(c, 500, "Failed to decode people")
+		return
+	}
+	names := map[primitive.ObjectID]string{}
+	for _, p := range people {
+		names[p.ID] = p.Name
+	}
+
+	credits := []models.MovieCredit{}
+	director := ""
+	cast := []string{}
+	for i, credit := range req.Credits {
+		name, found := names[personIDs[i]]
+		if !found {
+			utils.ErrorResponse(c, 404, "Person not found: "+credit.PersonID)
+			return
+		}
+		credits = append(credits, models.MovieCredit{
+			PersonID:  personIDs[i],
+			Name:      name,
+			Role:      credit.Role,
+			Character: utils.SanitizeString(credit.Character),
+		})
+		if credit.Role == models.PersonRoleDirector && director == "" {
+			director = name
+		}
+		if credit.Role == models.PersonRoleActor {
+			cast = append(cast, name)
+		}
+	}
+
+	var movie models.Movie
+	err = config.GetCollection("movies").FindOneAndUpdate(ctx,
+		bson.M{"_id": movieID},
+		bson.M{"$set": bson.M{"credits": credits, "director": director, "cast": cast}},
+		options.FindOneAndUpdate().SetReturnDocument(options.After),
+	).Decode(&movie)
+	if err != nil {
+		utils.ErrorResponse(c, 404, "Movie not found")
+		return
+	}
+
+	// Роль персоны добавляется в ее профиль
+	for _, credit := range credits {
+		_, err := peopleCollection.UpdateOne(ctx,
+			bson.M{"_id": credit.PersonID},
+			bson.M{"$addToSet": bson.M{"roles": credit.Role}},
+		)
+		if err != nil {
+			fmt.Printf("Warning: failed to update person roles: %v\n", err)
+		}
+	}
+
+	scheduleSearchIndexRebuild()
+
+	utils.SuccessWithMessage(c, 200, "Movie credits updated successfully", movie)
+}
@@ -0,0 +1,498 @@
+package handlers
+
+import (
+	"cinema-booking/config"
+	"cinema-booking/models"
+	"cinema-booking/utils"
+	"context"
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// PriceAdjustment - примененное правило ценообразования (для объяснения цены)
+type PriceAdjustment struct {
+	RuleID     primitive.ObjectID `json:"ruleId"`
+	RuleName   string             `json:"ruleName"`
+	Type       string             `json:"type"`
+	Multiplier float64            `json:"multiplier"`
+	Surcharge  float64            `json:"surcharge"`
+	Reason     string             `json:"reason"`
+}
+
+// SeatQuote - цена конкретного места
+type SeatQuote struct {
+	Row       string  `json:"row"`
+	Number    int     `json:"number"`
+	SeatType  string  `json:"seatType"`
+	BasePrice float64 `json:"basePrice"` // цена места в зале + базовая цена сеанса
+	Price     float64 `json:"price"`     // итоговая цена после правил
+}
+
+// pricingContext - все данные, необходимые для расчета цены сеанса
+type pricingContext struct {
+	Showtime models.Showtime
+	Cinema   *models.Cinema // nil, если кинотеатр не найден
+	Hall     *models.Hall   // nil, если зал не найден
+	Movie    *models.Movie  // nil, если фильм не найден
+	Rules    []models.PricingRule
+}
+
+// loadPricingContext - загрузить зал, фильм и активные правила для сеанса
+func loadPricingContext(ctx context.Context, showtime models.Showtime) (*pricingContext, error) {
+	pc := &pricingContext{Showtime: showtime}
+
+	var cinema models.Cinema
+	if err := config.GetCollection("cinemas").FindOne(ctx, bson.M{"_id": showtime.CinemaID}).Decode(&cinema); err == nil {
+		pc.Cinema = &cinema
+	}
+
+	var hall models.Hall
+	if err := config.GetCollection("halls").FindOne(ctx, bson.M{"_id": showtime.HallID}).Decode(&hall); err == nil {
+		pc.Hall = &hall
+	}
+
+	var movie models.Movie
+	if err := config.GetCollection("movies").FindOne(ctx, bson.M{"_id": showtime.MovieID}).Decode(&movie); err == nil {
+		pc.Movie = &movie
+	}
+
+	// Правила для всех кинотеатров или для кинотеатра сеанса
+	filter := bson.M{
+		"isActive": true,
+		"$or": bson.A{
+			bson.M{"cinemaIds": bson.M{"$exists": false}},
+			bson.M{"cinemaIds": bson.M{"$size": 0}},
+			bson.M{"cinemaIds": showtime.CinemaID},
+		},
+	}
+	findOptions := options.Find().SetSort(bson.D{{Key: "priority", Value: 1}, {Key: "createdAt", Value: 1}})
+
+	cursor, err := config.GetCollection("pricing_rules").Find(ctx, filter, findOptions)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	if err := cursor.All(ctx, &pc.Rules); err != nil {
+		return nil, err
+	}
+
+	return pc, nil
+}
+
+// seatBasePrice - базовая цена места (цена места в зале + базовая цена сеанса)
+func (pc *pricingContext) seatBasePrice(row string, number int) (float64, string) {
+	if pc.Hall != nil {
+		for _, hallSeat := range pc.Hall.Seats {
+			if hallSeat.Row == row && hallSeat.Number == number {
+				return hallSeat.Price + pc.Showtime.BasePrice, hallSeat.Type
+			}
+		}
+	}
+	// Зал или место не найдены - используем базовую цену сеанса
+	return pc.Showtime.BasePrice, "regular"
+}
+
+// occupancy - доля занятых мест (0..1)
+func (pc *pricingContext) occupancy() float64 {
+	capacity := pc.Showtime.AvailableSeats + len(pc.Showtime.BookedSeats)
+	if pc.Hall != nil && pc.Hall.Capacity > 0 {
+		capacity = pc.Hall.Capacity
+	}
+	if capacity == 0 {
+		return 0
+	}
+	return float64(capacity-pc.Showtime.AvailableSeats) / float64(capacity)
+}
+
+// adjustments - применимые правила: общий множитель, надбавка за место и объяснение
+func (pc *pricingContext) adjustments() (float64, float64, []PriceAdjustment) {
+	multiplier, surcharge := 1.0, 0.0
+	applied := []PriceAdjustment{}
+
+	start := pc.Showtime.StartTime.In(time.Local)
+
+	for _, rule := range pc.Rules {
+		var reason string
+
+		switch rule.Type {
+		case models.PricingRuleTimeOfDay:
+			hour := start.Hour()
+			inRange := hour >= rule.StartHour && hour < rule.EndHour
+			if rule.EndHour < rule.StartHour {
+				inRange = hour >= rule.StartHour || hour < rule.EndHour
+			}
+			if inRange {
+				reason = fmt.Sprintf("Showtime starts at %s (%02d:00-%02d:00)", start.Format("15:04"), rule.StartHour, rule.EndHour)
+			}
+
+		case models.PricingRuleWeekday:
+			for _, day := range rule.Weekdays {
+				if time.Weekday(day) == start.Weekday() {
+					reason = fmt.Sprintf("Showtime is on %s", start.Weekday())
+					break
+				}
+			}
+
+		case models.PricingRuleFormat:
+			for _, format := range rule.Formats {
+				if strings.EqualFold(format, pc.Showtime.Format) {
+					reason = fmt.Sprintf("%s format surcharge", pc.Showtime.Format)
+					break
+				}
+				if pc.Hall != nil && strings.EqualFold(format, pc.Hall.Type) {
+					reason = fmt.Sprintf("%s hall surcharge", pc.Hall.Type)
+					break
+				}
+			}
+
+		case models.PricingRulePremiereWeek:
+			if pc.Movie == nil || pc.Movie.ReleaseDate.IsZero() {
+				continue
+			}
+			days := rule.PremiereDays
+			if days <= 0 {
+				days = 7
+			}
+			sinceRelease := start.Sub(pc.Movie.ReleaseDate)
+			if sinceRelease >= 0 && sinceRelease < time.Duration(days)*24*time.Hour {
+				reason = fmt.Sprintf("Premiere period: within %d days of release on %s", days, pc.Movie.ReleaseDate.Format("2006-01-02"))
+			}
+
+		case models.PricingRuleOccupancy:
+			occupancy := pc.occupancy()
+			if occupancy >= rule.MinOccupancy {
+				reason = fmt.Sprintf("High demand: %.0f%% of seats taken", occupancy*100)
+			}
+		}
+
+		if reason == "" {
+			continue
+		}
+
+		ruleMultiplier := rule.Multiplier
+		if ruleMultiplier <= 0 {
+			ruleMultiplier = 1
+		}
+		multiplier *= ruleMultiplier
+		surcharge += rule.Surcharge
+
+		applied = append(applied, PriceAdjustment{
+			RuleID:     rule.ID,
+			RuleName:   rule.Name,
+			Type:       rule.Type,
+			Multiplier: ruleMultiplier,
+			Surcharge:  rule.Surcharge,
+			Reason:     reason,
+		})
+	}
+
+	return multiplier, surcharge, applied
+}
+
+// seatPrice - итоговая цена места с учетом правил
+func (pc *pricingContext) seatPrice(row string, number int, multiplier, surcharge float64) SeatQuote {
+	base, seatType := pc.seatBasePrice(row, number)
+	// Цена не бывает отрицательной (правила, сохраненные до проверки надбавки)
+	price := math.Max(0, roundMoney(base*multiplier+surcharge))
+	return SeatQuote{
+		Row:       row,
+		Number:    number,
+		SeatType:  seatType,
+		BasePrice: base,
+		Price:     price,
+	}
+}
+
+// roundMoney - округлить сумму до 2 знаков
+func roundMoney(amount float64) float64 {
+	return math.Round(amount*100) / 100
+}
+
+// GetPriceQuote - расчет цены сеанса с объяснением корректировок
+// ?seats=A-1,A-2 - цены конкретных мест, иначе - цены по типам мест
+func GetPriceQuote(c *gin.Context) {
+	showtimeID, err := primitive.ObjectIDFromHex(c.Param("id"))
+	if err != nil {
+		utils.ErrorResponse(c, 400, "Invalid showtime ID")
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	var showtime models.Showtime
+	err = config.GetCollection("showtimes").FindOne(ctx, bson.M{"_id": showtimeID}).Decode(&showtime)
+	if err != nil {
+		utils.ErrorResponse(c, 404, "Showtime not found")
+		return
+	}
+
+	pc, err := loadPricingContext(ctx, showtime)
+	if err != nil {
+		utils.ErrorResponse(c, 500, "Failed to load pricing rules")
+		return
+	}
+
+	multiplier, surcharge, adjustments := pc.adjustments()
+
+	seats := []SeatQuote{}
+	var total float64
+
+	if seatsParam := c.Query("seats"); seatsParam != "" {
+		// Цены запрошенных мест
+		for _, key := range strings.Split(seatsParam, ",") {
+			parts := strings.SplitN(strings.TrimSpace(key), "-", 2)
+			if len(parts) != 2 {
+				utils.ErrorResponse(c, 400, fmt.Sprintf("Invalid seat %q. Use: ROW-NUMBER, e.g. A-5", key))
+				return
+			}
+			number, err := strconv.Atoi(parts[1])
+			if err != nil {
+				utils.ErrorResponse(c, 400, fmt.Sprintf("Invalid seat %q. Use: ROW-NUMBER, e.g. A-5", key))
+				return
+			}
+
+			quote := pc.seatPrice(parts[0], number, multiplier, surcharge)
+			seats = append(seats, quote)
+			total += quote.Price
+		}
+	} else if pc.Hall != nil {
+		// По одному месту каждого типа
+		seen := make(map[string]bool)
+		for _, hallSeat := range pc.Hall.Seats {
+			if seen[hallSeat.Type] {
+				continue
+			}
+			seen[hallSeat.Type] = true
+			seats = append(seats, pc.seatPrice(hallSeat.Row, hallSeat.Number, multiplier, surcharge))
+		}
+		sort.Slice(seats, func(i, j int) bool { return seats[i].BasePrice < seats[j].BasePrice })
+	}
+
+	// Категории билетов кинотеатра и их доступность для фильма
+	categories := []gin.H{}
+	for _, category := range ticketCategoriesFor(pc.Cinema) {
+		if !category.IsActive {
+			continue
+		}
+		entry := gin.H{
+			"code":                 category.Code,
+			"name":                 category.Name,
+			"discountPercent":      category.DiscountPercent,
+			"requiresVerification": category.RequiresVerification,
+			"allowed":              true,
+		}
+		if _, err := pc.ticketCategory(category.Code); err != nil {
+			entry["allowed"] = false
+			entry["reason"] = err.Error()
+		}
+		categories = append(categories, entry)
+	}
+
+	utils.SuccessResponse(c, 200, gin.H{
+		"showtimeId":  showtimeID,
+		"basePrice":   showtime.BasePrice,
+		"multiplier":  math.Round(multiplier*10000) / 10000,
+		"surcharge":   surcharge,
+		"adjustments": adjustments,
+		"seats":       seats,
+		"categories":  categories,
+		"ageRule":     pc.ageRule(),
+		"total":       roundMoney(total),
+		"currency":    "KZT",
+		"quotedAt":    time.Now(),
+	})
+}
+
+// validatePricingRule - проверить правило перед сохранением
+func validatePricingRule(rule *models.PricingRule) string {
+	rule.Name = utils.SanitizeString(rule.Name)
+	if rule.Name == "" {
+		return "Name is required"
+	}
+
+	if rule.Multiplier == 0 {
+		rule.Multiplier = 1
+	}
+	if rule.Multiplier < 0 || rule.Multiplier > 10 {
+		return "Multiplier must be between 0 and 10"
+	}
+	if rule.Surcharge < 0 {
+		return "Surcharge must not be negative"
+	}
+
+	switch rule.Type {
+	case models.PricingRuleTimeOfDay:
+		if rule.StartHour < 0 || rule.StartHour > 23 || rule.EndHour < 0 || rule.EndHour > 24 || rule.StartHour == rule.EndHour {
+			return "startHour must be 0-23 and endHour 0-24, and they must differ"
+		}
+	case models.PricingRuleWeekday:
+		if len(rule.Weekdays) == 0 {
+			return "weekdays are required (0 = Sunday ... 6 = Saturday)"
+		}
+		for _, day := range rule.Weekdays {
+			if day < 0 || day > 6 {
+				return "weekdays must be between 0 (Sunday) and 6 (Saturday)"
+			}
+		}
+	case models.PricingRuleFormat:
+		if len(rule.Formats) == 0 {
+			return "formats are required, e.g. [\"3D\", \"IMAX\"]"
+		}
+	case models.PricingRulePremiereWeek:
+		if rule.PremiereDays < 0 {
+			return "premiereDays must not be negative"
+		}
+	case models.PricingRuleOccupancy:
+		if rule.MinOccupancy <= 0 || rule.MinOccupancy > 1 {
+			return "minOccupancy must be between 0 and 1"
+		}
+	default:
+		return "Invalid rule type. Use: time_of_day, weekday, format, premiere_week, occupancy"
+	}
+
+	return ""
+}
+
+// GetPricingRules - список правил ценообразования (admin only)
+func GetPricingRules(c *gin.Context) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	filter := bson.M{}
+	if ruleType := c.Query("type"); ruleType != "" {
+		filter["type"] = ruleType
+	}
+	if isActive := c.Query("isActive"); isActive != "" {
+		filter["isActive"] = isActive == "true"
+	}
+
+	findOptions := options.Find().SetSort(bson.D{{Key: "priority", Value: 1}, {Key: "createdAt", Value: 1}})
+
+	cursor, err := config.GetCollection("pricing_rules").Find(ctx, filter, findOptions)
+	if err != nil {
+		utils.ErrorResponse(c, 500, "Failed to fetch pricing rules")
+		return
+	}
+	defer cursor.Close(ctx)
+
+	rules := []models.PricingRule{}
+	if err = cursor.All(ctx, &rules); err != nil {
+		utils.ErrorResponse(c, 500, "Failed to decode pricing rules")
+		return
+	}
+
+	utils.SuccessResponse(c, 200, rules)
+}
+
+// CreatePricingRule - создать правило ценообразования (admin only)
+func CreatePricingRule(c *gin.Context) {
+	var rule models.PricingRule
+	if err := c.ShouldBindJSON(&rule); err != nil {
+		utils.ErrorResponse(c, 400, "Invalid request data: "+err.Error())
+		return
+	}
+
+	if msg := validatePricingRule(&rule); msg != "" {
+		utils.ErrorResponse(c, 400, msg)
+		return
+	}
+
+	rule.ID = primitive.NilObjectID
+	rule.CreatedAt = time.Now()
+	rule.UpdatedAt = time.Now()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	result, err := config.GetCollection("pricing_rules").InsertOne(ctx, rule)
+	if err != nil {
+		utils.ErrorResponse(c, 500, "Failed to create pricing rule")
+		return
+	}
+
+	rule.ID = result.InsertedID.(primitive.ObjectID)
+
+	utils.SuccessWithMessage(c, 201, "Pricing rule created successfully", rule)
+}
+
+// UpdatePricingRule - заменить правило ценообразования (admin only)
+func UpdatePricingRule(c *gin.Context) {
+	ruleID, err := primitive.ObjectIDFromHex(c.Param("id"))
+	if err != nil {
+		utils.ErrorResponse(c, 400, "Invalid pricing rule ID")
+		return
+	}
+
+	var rule models.PricingRule
+	if err := c.ShouldBindJSON(&rule); err != nil {
+		utils.ErrorResponse(c, 400, "Invalid request data: "+err.Error())
+		return
+	}
+
+	if msg := validatePricingRule(&rule); msg != "" {
+		utils.ErrorResponse(c, 400, msg)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	rulesCollection := config.GetCollection("pricing_rules")
+
+	var existing models.PricingRule
+	if err := rulesCollection.FindOne(ctx, bson.M{"_id": ruleID}).Decode(&existing); err != nil {
+		utils.ErrorResponse(c, 404, "Pricing rule not found")
+		return
+	}
+
+	rule.ID = ruleID
+	rule.CreatedAt = existing.CreatedAt
+	rule.UpdatedAt = time.Now()
+
+	if _, err := rulesCollection.ReplaceOne(ctx, bson.M{"_id": ruleID}, rule); err != nil {
+		utils.ErrorResponse(c, 500, "Failed to update pricing rule")
+		return
+	}
+
+	utils.SuccessWithMessage(c, 200, "Pricing rule updated successfully", rule)
+}
+
+// DeletePricingRule - удалить правило ценообразования (admin only)
+func DeletePricingRule(c *gin.Context) {
+	ruleIDStr := c.Param("id")
+	ruleID, err := primitive.ObjectIDFromHex(ruleIDStr)
+	if err != nil {
+		utils.ErrorResponse(c, 400, "Invalid pricing rule ID")
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	result, err := config.GetCollection("pricing_rules").DeleteOne(ctx, bson.M{"_id": ruleID})
+	if err != nil {
+		utils.ErrorResponse(c, 500, "Failed to delete pricing rule")
+		return
+	}
+
+	if result.DeletedCount == 0 {
+		utils.ErrorResponse(c, 404, "Pricing rule not found")
+		return
+	}
+
+	utils.SuccessWithMessage(c, 200, "Pricing rule deleted successfully", gin.H{
+		"ruleId":  ruleIDStr,
+		"deleted": true,
+	})
+}
@@ -0,0 +1,618 @@
+package handlers
+
+import (
+	"cinema-booking/config"
+	"cinema-booking/models"
+	"cinema-booking/utils"
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// normalizePromoCode - коды храним и сравниваем в верхнем регистре
+func normalizePromoCode(code string) string {
+	return strings.ToUpper(utils.SanitizeString(code))
+}
+
+// promoDiscount - посчитать скидку промокода для суммы брони
+func promoDiscount(promo *models.PromoCode, total float64) float64 {
+	var discount float64
+	switch promo.DiscountType {
+	case "percentage":
+		discount = total * promo.DiscountValue / 100
+		if promo.MaxDiscount > 0 && discount > promo.MaxDiscount {
+			discount = promo.MaxDiscount
+		}
+	case "fixed":
+		discount = promo.DiscountValue
+	}
+	if discount > total {
+		discount = total
+	}
+	return roundMoney(discount)
+}
+
+// validatePromoForBooking - найти промокод и проверить все ограничения для брони
+func validatePromoForBooking(ctx context.Context, code string, userID primitive.ObjectID, pc *pricingContext, seatsCount int) (*models.PromoCode, error) {
+	var promo models.PromoCode
+	err := config.GetCollection("promo_codes").FindOne(ctx, bson.M{"code": normalizePromoCode(code)}).Decode(&promo)
+	if err != nil {
+		return nil, errors.New("Promo code not found")
+	}
+
+	now := time.Now()
+	if !promo.IsActive {
+		return nil, errors.New("Promo code is not active")
+	}
+	if now.Before(promo.ValidFrom) {
+		return nil, errors.New("Promo code is not valid yet")
+	}
+	if !promo.ValidUntil.IsZero() && now.After(promo.ValidUntil) {
+		return nil, errors.New("Promo code has expired")
+	}
+	if promo.UsageLimit > 0 && promo.UsedCount >= promo.UsageLimit {
+		return nil, errors.New("Promo code usage limit reached")
+	}
+	if promo.MinSeats > 0 && seatsCount < promo.MinSeats {
+		return nil, fmt.Errorf("Promo code requires at least %d seats", promo.MinSeats)
+	}
+
+	// Ограничения по фильму, кинотеатру, формату и дню недели
+	if len(promo.MovieIDs) > 0 && !containsObjectID(promo.MovieIDs, pc.Showtime.MovieID) {
+		return nil, errors.New("Promo code is not valid for this movie")
+	}
+	if len(promo.CinemaIDs) > 0 && !containsObjectID(promo.CinemaIDs, pc.Showtime.CinemaID) {
+		return nil, errors.New("Promo code is not valid at this cinema")
+	}
+	if len(promo.Formats) > 0 {
+		formatOK := false
+		for _, format := range promo.Formats {
+			if strings.EqualFold(format, pc.Showtime.Format) {
+				formatOK = true
+				break
+			}
+		}
+		if !formatOK {
+			return nil, fmt.Errorf("Promo code is not valid for %s showtimes", pc.Showtime.Format)
+		}
+	}
+	if len(promo.Weekdays) > 0 {
+		weekday := int(pc.Showtime.StartTime.In(time.Local).Weekday())
+		dayOK := false
+		for _, day := range promo.Weekdays {
+			if day == weekday {
+				dayOK = true
+				break
+			}
+		}
+		if !dayOK {
+			return nil, fmt.Errorf("Promo code is not valid on %s", pc.Showtime.StartTime.In(time.Local).Weekday())
+		}
+	}
+
+	// Лимит на пользователя (предварительно; атомарно - в reservePromoCode)
+	if promo.PerUserLimit > 0 {
+		used, err := config.GetCollection("promo_redemptions").CountDocuments(ctx, bson.M{
+			"promoCodeId": promo.ID,
+			"userId":      userID,
+			"status":      "active",
+		})
+		if err != nil {
+			return nil, errors.New("Failed to check promo code usage")
+		}
+		if int(used) >= promo.PerUserLimit {
+			return nil, errors.New("You have already used this promo code the maximum number of times")
+		}
+	}
+
+	return &promo, nil
+}
+
+// reservePromoCode - атомарно занять одно использование промокода (общий лимит и лимит пользователя)
+func reservePromoCode(ctx context.Context, promo *models.PromoCode, userID primitive.ObjectID) error {
+	filter := bson.M{"_id": promo.ID, "isActive": true}
+	if promo.UsageLimit > 0 {
+		filter["usedCount"] = bson.M{"$lt": promo.UsageLimit}
+	}
+
+	result, err := config.GetCollection("promo_codes").UpdateOne(ctx, filter, bson.M{
+		"$inc": bson.M{"usedCount": 1},
+		"$set": bson.M{"updatedAt": time.Now()},
+	})
+	if err != nil {
+		return errors.New("Failed to apply promo code")
+	}
+	if result.ModifiedCount == 0 {
+		return errors.New("Promo code usage limit reached")
+	}
+
+	if err := reservePromoUserUse(ctx, promo, userID); err != nil {
+		releasePromoCode(ctx, promo.ID, primitive.NilObjectID)
+		return err
+	}
+	return nil
+}
+
+// promoUsageKey - ID счетчика использований промокода пользователем
+func promoUsageKey(promoID, userID primitive.ObjectID) string {
+	return promoID.Hex() + ":" + userID.Hex()
+}
+
+// reservePromoUserUse - атомарно занять использование из лимита пользователя.
+// Параллельные брони одного пользователя не превысят PerUserLimit: счетчик
+// увеличивается только при used < limit, а создается с уникальным _id.
+func reservePromoUserUse(ctx context.Context, promo *models.PromoCode, userID primitive.ObjectID) error {
+	if promo.PerUserLimit <= 0 {
+		return nil
+	}
+
+	usageCollection := config.GetCollection("promo_user_usage")
+	key := promoUsageKey(promo.ID, userID)
+
+	for attempt := 0; attempt < 2; attempt++ {
+		result, err := usageCollection.UpdateOne(ctx,
+			bson.M{"_id": key, "used": bson.M{"$lt": promo.PerUserLimit}},
+			bson.M{"$inc": bson.M{"used": 1}, "$set": bson.M{"updatedAt": time.Now()}},
+		)
+		if err != nil {
+			return errors.New("Failed to apply promo code")
+		}
+		if result.MatchedCount > 0 {
+			return nil
+		}
+
+		// Счетчика еще нет: начать с использований, сделанных до его появления
+		used, err := config.GetCollection("promo_redemptions").CountDocuments(ctx, bson.M{
+			"promoCodeId": promo.ID,
+			"userId":      userID,
+			"status":      "active",
+		})
+		if err != nil {
+			return errors.New("Failed to check promo code usage")
+		}
+		if int(used) >= promo.PerUserLimit {
+			break
+		}
+
+		_, err = usageCollection.InsertOne(ctx, models.PromoUserUsage{
+			ID:          key,
+			PromoCodeID: promo.ID,
+			UserID:      userID,
+			Used:        int(used) + 1,
+			UpdatedAt:   time.Now(),
+		})
+		if err == nil {
+			return nil
+		}
+		if !mongo.IsDuplicateKeyError(err) {
+			return errors.New("Failed to apply promo code")
+		}
+		// Счетчик уже есть (создан параллельно или исчерпан) - проверить еще раз
+	}
+
+	return errors.New("You have already used this promo code the maximum number of times")
+}
+
+// releasePromoCode - вернуть одно использование промокода (и использование пользователя, если userID задан)
+func releasePromoCode(ctx context.Context, promoID, userID primitive.ObjectID) {
+	if !userID.IsZero() {
+		_, err := config.GetCollection("promo_user_usage").UpdateOne(ctx,
+			bson.M{"_id": promoUsageKey(promoID, userID), "used": bson.M{"$gt": 0}},
+			bson.M{"$inc": bson.M{"used": -1}, "$set": bson.M{"updatedAt": time.Now()}},
+		)
+		if err != nil {
+			fmt.Printf("Warning: failed to release promo code user usage: %v\n", err)
+		}
+	}
+
+	_, err := config.GetCollection("promo_codes").UpdateOne(ctx,
+		bson.M{"_id": promoID, "usedCount": bson.M{"$gt": 0}},
+		bson.M{
+			"$inc": bson.M{"usedCount": -1},
+			"$set": bson.M{"updatedAt": time.Now()},
+		},
+	)
+	if err != nil {
+		fmt.Printf("Warning: failed to release promo code usage: %v\n", err)
+	}
+}
+
+// recordPromoRedemption - сохранить использование промокода для брони
+func recordPromoRedemption(ctx context.Context, promo *models.PromoCode, userID, bookingID primitive.ObjectID, discount float64) {
+	redemption := models.PromoRedemption{
+		PromoCodeID: promo.ID,
+		Code:        promo.Code,
+		UserID:      userID,
+		BookingID:   bookingID,
+		Discount:    discount,
+		Status:      "active",
+		CreatedAt:   time.Now(),
+		UpdatedAt:   time.Now(),
+	}
+	if _, err := config.GetCollection("promo_redemptions").InsertOne(ctx, redemption); err != nil {
+		fmt.Printf("Warning: failed to record promo redemption: %v\n", err)
+	}
+}
+
+// returnPromoRedemption - вернуть использование промокода при отмене брони
+func returnPromoRedemption(ctx context.Context, booking models.Booking) {
+	for _, discount := range booking.Discounts {
+		if discount.Type != "promo" || discount.PromoCodeID.IsZero() {
+			continue
+		}
+
+		result, err := config.GetCollection("promo_redemptions").UpdateOne(ctx,
+			bson.M{"bookingId": booking.ID, "promoCodeId": discount.PromoCodeID, "status": "active"},
+			bson.M{"$set": bson.M{"status": "returned", "updatedAt": time.Now()}},
+		)
+		if err != nil {
+			fmt.Printf("Warning: failed to return promo redemption: %v\n", err)
+			continue
+		}
+		if result.ModifiedCount > 0 {
+			releasePromoCode(ctx, discount.PromoCodeID, booking.UserID)
+		}
+	}
+}
+
+// containsObjectID - есть ли ID в списке
+func containsObjectID(ids []primitive.ObjectID, id primitive.ObjectID) bool {
+	for _, item := range ids {
+		if item == id {
+			return true
+		}
+	}
+	return false
+}
+
+// validatePromoCode - проверить промокод перед сохранением
+func validatePromoCode(promo *models.PromoCode) string {
+	promo.Code = normalizePromoCode(promo.Code)
+	if len(promo.Code) < 3 || len(promo.Code) > 32 {
+		return "Code must be 3-32 characters long"
+	}
+	for _, r := range promo.Code {
+		if !(r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || r == '-' || r == '_') {
+			return "Code may contain only letters, digits, '-' and '_'"
+		}
+	}
+
+	switch promo.DiscountType {
+	case "percentage":
+		if promo.DiscountValue <= 0 || promo.DiscountValue > 100 {
+			return "Percentage discount must be between 0 and 100"
+		}
+	case "fixed":
+		if promo.DiscountValue <= 0 {
+			return "Fixed discount must be greater than 0"
+		}
+	default:
+		return "Invalid discount type. Use: percentage or fixed"
+	}
+
+	if promo.MaxDiscount < 0 || promo.UsageLimit < 0 || promo.PerUserLimit < 0 || promo.MinSeats < 0 {
+		return "Limits must not be negative"
+	}
+
+	if !promo.ValidUntil.IsZero() && !promo.ValidUntil.After(promo.ValidFrom) {
+		return "validUntil must be after validFrom"
+	}
+
+	for _, day := range promo.Weekdays {
+		if day < 0 || day > 6 {
+			return "weekdays must be between 0 (Sunday) and 6 (Saturday)"
+		}
+	}
+
+	return ""
+}
+
+// GetPromoCodes - список промокодов (admin only)
+func GetPromoCodes(c *gin.Context) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	// Пагинация
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "20"))
+	if page < 1 {
+		page = 1
+	}
+	if limit < 1 || limit > 100 {
+		limit = 20
+	}
+	skip := (page - 1) * limit
+
+	filter := bson.M{}
+	if campaign := c.Query("campaign"); campaign != "" {
+		filter["campaign"] = campaign
+	}
+	if isActive := c.Query("isActive"); isActive != "" {
+		filter["isActive"] = isActive == "true"
+	}
+
+	promoCollection := config.GetCollection("promo_codes")
+
+	findOptions := options.Find()
+	findOptions.SetSkip(int64(skip))
+	findOptions.SetLimit(int64(limit))
+	findOptions.SetSort(bson.D{{Key: "createdAt", Value: -1}})
+
+	cursor, err := promoCollection.Find(ctx, filter, findOptions)
+	if err != nil {
+		utils.ErrorResponse(c, 500, "Failed to fetch promo codes")
+		return
+	}
+	defer cursor.Close(ctx)
+
+	promos := []models.PromoCode{}
+	if err = cursor.All(ctx, &promos); err != nil {
+		utils.ErrorResponse(c, 500, "Failed to decode promo codes")
+		return
+	}
+
+	total, _ := promoCollection.CountDocuments(ctx, filter)
+
+	utils.PaginatedResponse(c, promos, page, limit, int(total))
+}
+
+// CreatePromoCode - создать промокод (admin only)
+func CreatePromoCode(c *gin.Context) {
+	var promo models.PromoCode
+	if err := c.ShouldBindJSON(&promo); err != nil {
+		utils.ErrorResponse(c, 400, "Invalid request data: "+err.Error())
+		return
+	}
+
+	if msg := validatePromoCode(&promo); msg != "" {
+		utils.ErrorResponse(c, 400, msg)
+		return
+	}
+
+	promo.ID = primitive.NilObjectID
+	promo.UsedCount = 0
+	promo.IsActive = true
+	promo.CreatedAt = time.Now()
+	promo.UpdatedAt = time.Now()
+	if promo.ValidFrom.IsZero() {
+		promo.ValidFrom = time.Now()
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	result, err := config.GetCollection("promo_codes").InsertOne(ctx, promo)
+	if mongo.IsDuplicateKeyError(err) {
+		utils.ErrorResponse(c, 409, "Promo code already exists")
+		return
+	}
+	if err != nil {
+		utils.ErrorResponse(c, 500, "Failed to create promo code")
+		return
+	}
+
+	promo.ID = result.InsertedID.(primitive.ObjectID)
+
+	utils.SuccessWithMessage(c, 201, "Promo code created successfully", promo)
+}
+
+// UpdatePromoCodeRequest - изменение промокода; isActive не передан - активность не меняется
+type UpdatePromoCodeRequest struct {
+	models.PromoCode
+	IsActive *bool `json:"isActive"`
+}
+
+// UpdatePromoCode - обновить условия промокода (admin only)
+func UpdatePromoCode(c *gin.Context) {
+	promoID, err := primitive.ObjectIDFromHex(c.Param("id"))
+	if err != nil {
+		utils.ErrorResponse(c, 400, "Invalid promo code ID")
+		return
+	}
+
+	var req UpdatePromoCodeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ErrorResponse(c, 400, "Invalid request data: "+err.Error())
+		return
+	}
+	promo := req.PromoCode
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	promoCollection := config.GetCollection("promo_codes")
+
+	var existing models.PromoCode
+	if err := promoCollection.FindOne(ctx, bson.M{"_id": promoID}).Decode(&existing); err != nil {
+		utils.ErrorResponse(c, 404, "Promo code not found")
+		return
+	}
+
+	// Код и счетчик использований не меняются; активность - только если передана явно
+	promo.Code = existing.Code
+	promo.IsActive = existing.IsActive
+	if req.IsActive != nil {
+		promo.IsActive = *req.IsActive
+	}
+	if msg := validatePromoCode(&promo); msg != "" {
+		utils.ErrorResponse(c, 400, msg)
+		return
+	}
+
+	promo.ID = promoID
+	promo.UsedCount = existing.UsedCount
+	promo.CreatedAt = existing.CreatedAt
+	promo.UpdatedAt = time.Now()
+
+	if _, err := promoCollection.ReplaceOne(ctx, bson.M{"_id": promoID}, promo); err != nil {
+		utils.ErrorResponse(c, 500, "Failed to update promo code")
+		return
+	}
+
+	utils.SuccessWithMessage(c, 200, "Promo code updated successfully", promo)
+}
+
+// DeletePromoCode - деактивировать промокод (admin only)
+func DeletePromoCode(c *gin.Context) {
+	promoIDStr := c.Param("id")
+	promoID, err := primitive.ObjectIDFromHex(promoIDStr)
+	if err != nil {
+		utils.ErrorResponse(c, 400, "Invalid promo code ID")
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	// Soft delete - история использований сохраняется
+	result, err := config.GetCollection("promo_codes").UpdateOne(ctx,
+		bson.M{"_id": promoID},
+		bson.M{"$set": bson.M{"isActive": false, "updatedAt": time.Now()}},
+	)
+	if err != nil {
+		utils.ErrorResponse(c, 500, "Failed to delete promo code")
+		return
+	}
+
+	if result.MatchedCount == 0 {
+		utils.ErrorResponse(c, 404, "Promo code not found")
+		return
+	}
+
+	utils.SuccessWithMessage(c, 200, "Promo code deactivated successfully", gin.H{
+		"promoCodeId": promoIDStr,
+		"deleted":     true,
+	})
+}
+
+// GeneratePromoBatchRequest - пакетная генерация кодов для партнерской кампании
+type GeneratePromoBatchRequest struct {
+	Campaign string           `json:"campaign" binding:"required"`
+	Prefix   string           `json:"prefix"` // "PARTNER" -> "PARTNER-7KQ2M9XA"
+	Count    int              `json:"count" binding:"required"`
+	Length   int              `json:"length"`   // длина случайной части (по умолчанию 8)
+	Template models.PromoCode `json:"template"` // условия, общие для всех кодов
+}
+
+// generatePromoCode - случайный код с необязательным префиксом
+func generatePromoCode(prefix string, length int) (string, error) {
+	code, err := utils.RandomCode(length)
+	if err != nil {
+		return "", err
+	}
+	if prefix != "" {
+		return prefix + "-" + code, nil
+	}
+	return code, nil
+}
+
+// GeneratePromoBatch - сгенерировать пакет уникальных кодов (admin only)
+func GeneratePromoBatch(c *gin.Context) {
+	var req GeneratePromoBatchRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ErrorResponse(c, 400, "Invalid request data: "+err.Error())
+		return
+	}
+
+	if req.Count < 1 || req.Count > 10000 {
+		utils.ErrorResponse(c, 400, "Count must be between 1 and 10000")
+		return
+	}
+	if req.Length == 0 {
+		req.Length = 8
+	}
+	if req.Length < 6 || req.Length > 16 {
+		utils.ErrorResponse(c, 400, "Length must be between 6 and 16")
+		return
+	}
+
+	req.Prefix = normalizePromoCode(req.Prefix)
+	req.Campaign = utils.SanitizeString(req.Campaign)
+
+	// Проверить общие условия на примере одного кода
+	template := req.Template
+	template.Code = strings.Repeat("X", req.Length)
+	if req.Prefix != "" {
+		template.Code = req.Prefix + "-" + template.Code
+	}
+	if msg := validatePromoCode(&template); msg != "" {
+		utils.ErrorResponse(c, 400, msg)
+		return
+	}
+
+	now := time.Now()
+	if template.ValidFrom.IsZero() {
+		template.ValidFrom = now
+	}
+
+	// Партнерские коды по умолчанию одноразовые
+	if template.UsageLimit == 0 {
+		template.UsageLimit = 1
+	}
+
+	seen := make(map[string]bool)
+	docs := make([]interface{}, 0, req.Count)
+	codes := make([]string, 0, req.Count)
+	for len(docs) < req.Count {
+		code, err := generatePromoCode(req.Prefix, req.Length)
+		if err != nil {
+			utils.ErrorResponse(c, 500, "Failed to generate promo codes")
+			return
+		}
+		if seen[code] {
+			continue
+		}
+		seen[code] = true
+
+		promo := template
+		promo.ID = primitive.NilObjectID
+		promo.Code = code
+		promo.Campaign = req.Campaign
+		promo.UsedCount = 0
+		promo.IsActive = true
+		promo.CreatedAt = now
+		promo.UpdatedAt = now
+
+		docs = append(docs, promo)
+		codes = append(codes, code)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
+	defer cancel()
+
+	// Неупорядоченная вставка: коллизии с существующими кодами пропускаются
+	_, err := config.GetCollection("promo_codes").InsertMany(ctx, docs, options.InsertMany().SetOrdered(false))
+	created := codes
+	if err != nil {
+		var bulkErr mongo.BulkWriteException
+		if !errors.As(err, &bulkErr) {
+			utils.ErrorResponse(c, 500, "Failed to save promo codes")
+			return
+		}
+		failed := make(map[int]bool)
+		for _, writeErr := range bulkErr.WriteErrors {
+			failed[writeErr.Index] = true
+		}
+		created = make([]string, 0, len(codes))
+		for i, code := range codes {
+			if !failed[i] {
+				created = append(created, code)
+			}
+		}
+	}
+
+	utils.SuccessWithMessage(c, 201, fmt.Sprintf("Generated %d promo codes", len(created)), gin.H{
+		"campaign": req.Campaign,
+		"count":    len(created),
+		"codes":    created,
+	})
+}
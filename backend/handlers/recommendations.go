@@ -0,0 +1,571 @@
+package handlers
+
+import (
+	"bytes"
+	"cinema-booking/config"
+	"cinema-booking/i18n"
+	"cinema-booking/models"
+	"cinema-booking/utils"
+	"context"
+	"math"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// Веса составляющих оценки (каждая составляющая 0..1)
+const (
+	recWeightSimilar  = 0.45 // совместные просмотры (item-item)
+	recWeightGenre    = 0.25
+	recWeightPerson   = 0.15
+	recWeightFormat   = 0.08
+	recWeightLanguage = 0.07
+)
+
+const (
+	maxRecommendations = 20 // хранится на пользователя
+	recHistoryLimit    = 50 // последних фильмов пользователя учитываются в совместных просмотрах
+	minCoViewers       = 2  // меньше общих зрителей - случайное совпадение
+	maxRecReasons      = 3
+	minReasonScore     = 0.02 // слабые причины не показываются
+)
+
+// watchedMovie - фильм из подтвержденных броней пользователя
+type watchedMovie struct {
+	Key struct {
+		UserID  primitive.ObjectID `bson:"userId"`
+		MovieID primitive.ObjectID `bson:"movieId"`
+	} `bson:"_id"`
+	Formats   []string  `bson:"formats"`
+	Languages []string  `bson:"languages"`
+	LastAt    time.Time `bson:"lastAt"`
+}
+
+// upcomingMovie - фильм с будущими сеансами (кандидат в рекомендации)
+type upcomingMovie struct {
+	MovieID   primitive.ObjectID `bson:"_id"`
+	Formats   []string           `bson:"formats"`
+	Languages []string           `bson:"languages"`
+}
+
+// moviePair - пара фильмов (упорядочена, чтобы (a,b) и (b,a) совпадали)
+type moviePair struct {
+	a, b primitive.ObjectID
+}
+
+func newMoviePair(x, y primitive.ObjectID) moviePair {
+	if bytes.Compare(x[:], y[:]) > 0 {
+		x, y = y, x
+	}
+	return moviePair{a: x, b: y}
+}
+
+// userTaste - предпочтения пользователя: доля просмотренных фильмов с жанром, персоной, форматом, языком
+type userTaste struct {
+	genres     map[string]float64
+	people     map[string]float64
+	peopleName map[string]string
+	formats    map[string]float64
+	languages  map[string]float64
+}
+
+// moviePeople - режиссеры и актеры фильма: ключ (personId или имя) -> имя
+func moviePeople(movie *models.Movie) map[string]string {
+	people := map[string]string{}
+	if len(movie.Credits) > 0 {
+		for _, credit := range movie.Credits {
+			people[credit.PersonID.Hex()] = credit.Name
+		}
+		return people
+	}
+	if movie.Director != "" {
+		people[movie.Director] = movie.Director
+	}
+	for _, name := range movie.Cast {
+		people[name] = name
+	}
+	return people
+}
+
+// buildTaste - профиль по истории пользователя
+func buildTaste(history []watchedMovie, movies map[primitive.ObjectID]*models.Movie) userTaste {
+	taste := userTaste{
+		genres:     map[string]float64{},
+		people:     map[string]float64{},
+		peopleName: map[string]string{},
+		formats:    map[string]float64{},
+		languages:  map[string]float64{},
+	}
+	if len(history) == 0 {
+		return taste
+	}
+
+	share := 1 / float64(len(history))
+	for _, watched := range history {
+		for _, format := range watched.Formats {
+			taste.formats[format] += share
+		}
+		for _, language := range watched.Languages {
+			taste.languages[language] += share
+		}
+
+		movie, ok := movies[watched.Key.MovieID]
+		if !ok {
+			continue
+		}
+		for _, genre := range movie.Genres {
+			taste.genres[genre] += share
+		}
+		for key, name := range moviePeople(movie) {
+			taste.people[key] += share
+			taste.peopleName[key] = name
+		}
+	}
+	return taste
+}
+
+// bestPreference - самое предпочитаемое значение из values и его вес
+func bestPreference(prefs map[string]float64, values []string) (string, float64) {
+	best, bestScore := "", 0.0
+	for _, v := range values {
+		if prefs[v] > bestScore {
+			best, bestScore = v, prefs[v]
+		}
+	}
+	return best, bestScore
+}
+
+// scoredReason - причина с ее вкладом в оценку
+type scoredReason struct {
+	reason models.RecommendationReason
+	score  float64
+}
+
+// ComputeRecommendations - пересчитать рекомендации всех пользователей с подтвержденными бронями
+// (go run ./cmd compute-recommendations, по cron раз в сутки). Возвращает число пользователей.
+func ComputeRecommendations(ctx context.Context) (int, error) {
+	runStartedAt := time.Now()
+
+	// === ИСТОРИЯ: фильмы из подтвержденных броней ===
+
+	cursor, err := config.GetCollection("bookings").Aggregate(ctx, bson.A{
+		bson.M{"$match": bson.M{"status": "confirmed"}},
+		bson.M{"$lookup": bson.M{
+			"from":         "showtimes",
+			"localField":   "showtimeId",
+			"foreignField": "_id",
+			"as":           "showtime",
+		}},
+		bson.M{"$unwind": "$showtime"},
+		bson.M{"$group": bson.M{
+			"_id":       bson.M{"userId": "$userId", "movieId": "$showtime.movieId"},
+			"formats":   bson.M{"$addToSet": "$showtime.format"},
+			"languages": bson.M{"$addToSet": "$showtime.language"},
+			"lastAt":    bson.M{"$max": "$createdAt"},
+		}},
+	})
+	if err != nil {
+		return 0, err
+	}
+	var watched []watchedMovie
+	if err := cursor.All(ctx, &watched); err != nil {
+		return 0, err
+	}
+
+	histories := map[primitive.ObjectID][]watchedMovie{}
+	seen := map[primitive.ObjectID]map[primitive.ObjectID]bool{} // все просмотренные фильмы пользователя
+	movieViewers := map[primitive.ObjectID]int{}
+	for _, w := range watched {
+		histories[w.Key.UserID] = append(histories[w.Key.UserID], w)
+		if seen[w.Key.UserID] == nil {
+			seen[w.Key.UserID] = map[primitive.ObjectID]bool{}
+		}
+		seen[w.Key.UserID][w.Key.MovieID] = true
+		movieViewers[w.Key.MovieID]++
+	}
+
+	// Последние фильмы первыми; для матрицы и профиля берется recHistoryLimit
+	coViewers := map[moviePair]int{}
+	for userID, history := range histories {
+		sort.Slice(history, func(i, j int) bool { return history[i].LastAt.After(history[j].LastAt) })
+		if len(history) > recHistoryLimit {
+			history = history[:recHistoryLimit]
+		}
+		histories[userID] = history
+
+		for i := range history {
+			for j := i + 1; j < len(history); j++ {
+				coViewers[newMoviePair(history[i].Key.MovieID, history[j].Key.MovieID)]++
+			}
+		}
+	}
+
+	// Косинусная близость по зрителям
+	similarity := func(x, y primitive.ObjectID) float64 {
+		co := coViewers[newMoviePair(x, y)]
+		if co < minCoViewers {
+			return 0
+		}
+		return float64(co) / math.Sqrt(float64(movieViewers[x]*movieViewers[y]))
+	}
+
+	// === КАНДИДАТЫ: активные фильмы с будущими сеансами ===
+
+	cursor, err = config.GetCollection("showtimes").Aggregate(ctx, bson.A{
+		bson.M{"$match": bson.M{"startTime": bson.M{"$gt": time.Now()}}},
+		bson.M{"$group": bson.M{
+			"_id":       "$movieId",
+			"formats":   bson.M{"$addToSet": "$format"},
+			"languages": bson.M{"$addToSet": "$language"},
+		}},
+	})
+	if err != nil {
+		return 0, err
+	}
+	var upcoming []upcomingMovie
+	if err := cursor.All(ctx, &upcoming); err != nil {
+		return 0, err
+	}
+
+	cursor, err = config.GetCollection("movies").Find(ctx, bson.M{})
+	if err != nil {
+		return 0, err
+	}
+	var movieList []models.Movie
+	if err := cursor.All(ctx, &movieList); err != nil {
+		return 0, err
+	}
+	movies := map[primitive.ObjectID]*models.Movie{}
+	for i := range movieList {
+		movies[movieList[i].ID] = &movieList[i]
+	}
+
+	candidates := []upcomingMovie{}
+	for _, u := range upcoming {
+		if movie, ok := movies[u.MovieID]; ok && movie.IsActive {
+			candidates = append(candidates, u)
+		}
+	}
+
+	// === ОЦЕНКА ===
+
+	writes := []mongo.WriteModel{}
+	for userID, history := range histories {
+		taste := buildTaste(history, movies)
+
+		items := []models.RecommendedMovie{}
+		for _, candidate := range candidates {
+			if seen[userID][candidate.MovieID] {
+				continue
+			}
+			movie := movies[candidate.MovieID]
+			reasons := []scoredReason{}
+
+			// Похожие по зрителям: сумма близостей, объяснение - самый близкий просмотренный фильм
+			simTotal, simBest := 0.0, 0.0
+			var simMovie primitive.ObjectID
+			for _, w := range history {
+				s := similarity(w.Key.MovieID, candidate.MovieID)
+				simTotal += s
+				if s > simBest {
+					simBest, simMovie = s, w.Key.MovieID
+				}
+			}
+			if simBest > 0 {
+				title := ""
+				if seenMovie, ok := movies[simMovie]; ok {
+					title = seenMovie.Title
+				}
+				reasons = append(reasons, scoredReason{
+					reason: models.RecommendationReason{Type: models.ReasonSimilar, MovieID: simMovie, Value: title},
+					score:  recWeightSimilar * math.Min(simTotal, 1),
+				})
+			}
+
+			// Жанры: средняя доля по жанрам фильма, объяснение - самый любимый из них
+			if len(movie.Genres) > 0 {
+				genreTotal := 0.0
+				for _, genre := range movie.Genres {
+					genreTotal += taste.genres[genre]
+				}
+				if genre, _ := bestPreference(taste.genres, movie.Genres); genre != "" {
+					reasons = append(reasons, scoredReason{
+						reason: models.RecommendationReason{Type: models.ReasonGenre, Value: genre},
+						score:  recWeightGenre * genreTotal / float64(len(movie.Genres)),
+					})
+				}
+			}
+
+			// Режиссер или актер из просмотренных фильмов
+			personKeys := []string{}
+			for key := range moviePeople(movie) {
+				personKeys = append(personKeys, key)
+			}
+			sort.Strings(personKeys)
+			if key, pref := bestPreference(taste.people, personKeys); key != "" {
+				reasons = append(reasons, scoredReason{
+					reason: models.RecommendationReason{Type: models.ReasonPerson, Value: taste.peopleName[key]},
+					score:  recWeightPerson * pref,
+				})
+			}
+
+			// Формат и язык будущих сеансов
+			if format, pref := bestPreference(taste.formats, candidate.Formats); format != "" {
+				reasons = append(reasons, scoredReason{
+					reason: models.RecommendationReason{Type: models.ReasonFormat, Value: format},
+					score:  recWeightFormat * pref,
+				})
+			}
+			if language, pref := bestPreference(taste.languages, candidate.Languages); language != "" {
+				reasons = append(reasons, scoredReason{
+					reason: models.RecommendationReason{Type: models.ReasonLanguage, Value: language},
+					score:  recWeightLanguage * pref,
+				})
+			}
+
+			// Фильм, у которого совпал только формат или язык, не рекомендуется
+			total, substantive := 0.0, false
+			for _, r := range reasons {
+				total += r.score
+				if r.reason.Type != models.ReasonFormat && r.reason.Type != models.ReasonLanguage {
+					substantive = true
+				}
+			}
+			if !substantive {
+				continue
+			}
+
+			sort.SliceStable(reasons, func(i, j int) bool { return reasons[i].score > reasons[j].score })
+			item := models.RecommendedMovie{
+				MovieID: candidate.MovieID,
+				Score:   math.Round(total*1000) / 1000,
+				Reasons: []models.RecommendationReason{},
+			}
+			for _, r := range reasons {
+				if r.score < minReasonScore || len(item.Reasons) == maxRecReasons {
+					break
+				}
+				item.Reasons = append(item.Reasons, r.reason)
+			}
+			if len(item.Reasons) == 0 {
+				continue
+			}
+			items = append(items, item)
+		}
+
+		sort.SliceStable(items, func(i, j int) bool {
+			if items[i].Score != items[j].Score {
+				return items[i].Score > items[j].Score
+			}
+			return movies[items[i].MovieID].IMDBRating > movies[items[j].MovieID].IMDBRating
+		})
+		if len(items) > maxRecommendations {
+			items = items[:maxRecommendations]
+		}
+
+		writes = append(writes, mongo.NewReplaceOneModel().
+			SetFilter(bson.M{"userId": userID}).
+			SetReplacement(models.UserRecommendations{
+				UserID:     userID,
+				Items:      items,
+				ComputedAt: runStartedAt,
+			}).
+			SetUpsert(true))
+	}
+
+	recommendationsCollection := config.GetCollection("recommendations")
+	if len(writes) > 0 {
+		if _, err := recommendationsCollection.BulkWrite(ctx, writes, options.BulkWrite().SetOrdered(false)); err != nil {
+			return 0, err
+		}
+	}
+
+	// Пользователи, у которых не осталось подтвержденных броней
+	if _, err := recommendationsCollection.DeleteMany(ctx, bson.M{"computedAt": bson.M{"$lt": runStartedAt}}); err != nil {
+		return 0, err
+	}
+
+	return len(writes), nil
+}
+
+// recommendationText - объяснение рекомендации на языке запроса
+func recommendationText(lang string, reason models.RecommendationReason, movies map[primitive.ObjectID]*models.Movie) string {
+	switch reason.Type {
+	case models.ReasonSimilar:
+		title := reason.Value
+		if movie, ok := movies[reason.MovieID]; ok {
+			title = i18n.Pick(lang, map[string]string{
+				i18n.English: movie.Title,
+				i18n.Russian: movie.TitleRu,
+				i18n.Kazakh:  movie.TitleKz,
+			})
+		}
+		return i18n.T(lang, "Because you watched: "+title)
+	case models.ReasonGenre:
+		return i18n.T(lang, "Matches your favorite genre: "+i18n.Term(lang, reason.Value))
+	case models.ReasonPerson:
+		return i18n.T(lang, "Same director or cast: "+reason.Value)
+	case models.ReasonFormat:
+		return i18n.T(lang, "Showing in your favorite format: "+i18n.Term(lang, reason.Value))
+	case models.ReasonLanguage:
+		return i18n.T(lang, "Showing in your preferred language: "+i18n.Term(lang, reason.Value))
+	default:
+		return i18n.T(lang, "Popular right now")
+	}
+}
+
+// popularRecommendations - для пользователей без рассчитанных рекомендаций:
+// активные фильмы с будущими сеансами по рейтингу
+func popularRecommendations(ctx context.Context, limit int) ([]models.RecommendedMovie, error) {
+	movieIDs, err := config.GetCollection("showtimes").Distinct(ctx, "movieId", bson.M{"startTime": bson.M{"$gt": time.Now()}})
+	if err != nil {
+		return nil, err
+	}
+
+	cursor, err := config.GetCollection("movies").Find(ctx,
+		bson.M{"_id": bson.M{"$in": movieIDs}, "isActive": true},
+		options.Find().
+			SetSort(bson.D{{Key: "imdbRating", Value: -1}, {Key: "reviewRating", Value: -1}}).
+			SetLimit(int64(limit)).
+			SetProjection(bson.M{"_id": 1}),
+	)
+	if err != nil {
+		return nil, err
+	}
+	var movies []models.Movie
+	if err := cursor.All(ctx, &movies); err != nil {
+		return nil, err
+	}
+
+	items := []models.RecommendedMovie{}
+	for _, movie := range movies {
+		items = append(items, models.RecommendedMovie{
+			MovieID: movie.ID,
+			Reasons: []models.RecommendationReason{{Type: models.ReasonPopular}},
+		})
+	}
+	return items, nil
+}
+
+// GetRecommendations - персональные рекомендации с объяснениями
+// GET /api/recommendations?limit=10
+func GetRecommendations(c *gin.Context) {
+	userID, _ := c.Get("userId")
+	userObjectID, _ := primitive.ObjectIDFromHex(userID.(string))
+
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "10"))
+	if limit < 1 || limit > maxRecommendations {
+		limit = 10
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	personalized := true
+	var cached models.UserRecommendations
+	err := config.GetCollection("recommendations").FindOne(ctx, bson.M{"userId": userObjectID}).Decode(&cached)
+	if err == mongo.ErrNoDocuments || (err == nil && len(cached.Items) == 0) {
+		// Новый пользователь или история еще не обработана
+		personalized = false
+		cached.Items, err = popularRecommendations(ctx, limit)
+	}
+	if err != nil {
+		utils.ErrorResponse(c, 500, "Failed to fetch recommendations")
+		return
+	}
+
+	// Фильмы, забронированные после расчета, больше не рекомендуются
+	bookedSince := map[primitive.ObjectID]bool{}
+	if personalized {
+		showtimeIDs, err := config.GetCollection("bookings").Distinct(ctx, "showtimeId", bson.M{
+			"userId":    userObjectID,
+			"status":    "confirmed",
+			"createdAt": bson.M{"$gte": cached.ComputedAt},
+		})
+		if err == nil && len(showtimeIDs) > 0 {
+			movieIDs, err := config.GetCollection("showtimes").Distinct(ctx, "movieId", bson.M{"_id": bson.M{"$in": showtimeIDs}})
+			if err == nil {
+				for _, id := range movieIDs {
+					if oid, ok := id.(primitive.ObjectID); ok {
+						bookedSince[oid] = true
+					}
+				}
+			}
+		}
+	}
+
+	// Рекомендованные фильмы и фильмы из объяснений - одним запросом
+	movieIDs := []primitive.ObjectID{}
+	for _, item := range cached.Items {
+		movieIDs = append(movieIDs, item.MovieID)
+		for _, reason := range item.Reasons {
+			if !reason.MovieID.IsZero() {
+				movieIDs = append(movieIDs, reason.MovieID)
+			}
+		}
+	}
+	cursor, err := config.GetCollection("movies").Find(ctx, bson.M{"_id": bson.M{"$in": movieIDs}})
+	if err != nil {
+		utils.ErrorResponse(c, 500, "Failed to fetch movies")
+		return
+	}
+	var movieList []models.Movie
+	if err := cursor.All(ctx, &movieList); err != nil {
+		utils.ErrorResponse(c, 500, "Failed to decode movies")
+		return
+	}
+	movies := map[primitive.ObjectID]*models.Movie{}
+	for i := range movieList {
+		movies[movieList[i].ID] = &movieList[i]
+	}
+
+	lang := i18n.FromContext(c)
+	results := []gin.H{}
+	for _, item := range cached.Items {
+		movie, ok := movies[item.MovieID]
+		if !ok || !movie.IsActive || bookedSince[item.MovieID] {
+			continue
+		}
+
+		reasons := []gin.H{}
+		for _, reason := range item.Reasons {
+			entry := gin.H{
+				"type": reason.Type,
+				"text": recommendationText(lang, reason, movies),
+			}
+			if !reason.MovieID.IsZero() {
+				entry["movieId"] = reason.MovieID
+			}
+			if reason.Value != "" {
+				entry["value"] = reason.Value
+			}
+			reasons = append(reasons, entry)
+		}
+
+		localized := *movie
+		localizeMovie(lang, &localized)
+		results = append(results, gin.H{
+			"movie":   localized,
+			"score":   item.Score,
+			"reasons": reasons,
+		})
+		if len(results) == limit {
+			break
+		}
+	}
+
+	response := gin.H{
+		"personalized":    personalized,
+		"recommendations": results,
+	}
+	if personalized {
+		response["computedAt"] = cached.ComputedAt
+	}
+	utils.SuccessResponse(c, 200, response)
+}
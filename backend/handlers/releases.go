@@ -0,0 +1,233 @@
+package handlers
+
+import (
+	"cinema-booking/config"
+	"cinema-booking/i18n"
+	"cinema-booking/models"
+	"cinema-booking/utils"
+	"context"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// Фильмы с датой релиза за последние N дней активируются, даже если запуск задачи был пропущен.
+// Более старые неактивные фильмы не трогаем (сняты с проката вручную до появления releasedAt).
+const releaseActivationWindow = 7 * 24 * time.Hour
+
+// GetNowShowing - фильмы, у которых есть сеансы в ближайшие N дней (в городе, если указан)
+// GET /api/movies/now-showing?city=Almaty&days=7
+func GetNowShowing(c *gin.Context) {
+	days, _ := strconv.Atoi(c.DefaultQuery("days", "7"))
+	if days < 1 || days > 30 {
+		days = 7
+	}
+	city := c.Query("city")
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	now := time.Now()
+	match := bson.M{"startTime": bson.M{"$gte": now, "$lte": now.AddDate(0, 0, days)}}
+	if city != "" {
+		cities, err := cinemaCities(ctx)
+		if err != nil {
+			utils.ErrorResponse(c, 500, "Failed to fetch cinemas")
+			return
+		}
+		match["cinemaId"] = bson.M{"$in": cinemaIDsInCity(cities, city)}
+	}
+
+	cursor, err := config.GetCollection("showtimes").Aggregate(ctx, bson.A{
+		bson.M{"$match": match},
+		bson.M{"$group": bson.M{
+			"_id":          "$movieId",
+			"showtimes":    bson.M{"$sum": 1},
+			"cinemas":      bson.M{"$addToSet": "$cinemaId"},
+			"formats":      bson.M{"$addToSet": "$format"},
+			"nextShowtime": bson.M{"$min": "$startTime"},
+		}},
+		bson.M{"$lookup": bson.M{
+			"from":         "movies",
+			"localField":   "_id",
+			"foreignField": "_id",
+			"as":           "movie",
+		}},
+		bson.M{"$unwind": "$movie"},
+		bson.M{"$match": bson.M{"movie.isActive": true}},
+		bson.M{"$sort": bson.D{{Key: "showtimes", Value: -1}, {Key: "movie.title", Value: 1}}},
+	})
+	if err != nil {
+		utils.ErrorResponse(c, 500, "Failed to fetch showtimes")
+		return
+	}
+
+	var rows []struct {
+		Showtimes    int                  `bson:"showtimes"`
+		Cinemas      []primitive.ObjectID `bson:"cinemas"`
+		Formats      []string             `bson:"formats"`
+		NextShowtime time.Time            `bson:"nextShowtime"`
+		Movie        models.Movie         `bson:"movie"`
+	}
+	if err := cursor.All(ctx, &rows); err != nil {
+		utils.ErrorResponse(c, 500, "Failed to decode results")
+		return
+	}
+
+	lang := i18n.FromContext(c)
+	movies := []gin.H{}
+	for i := range rows {
+		row := &rows[i]
+		localizeMovie(lang, &row.Movie)
+		movies = append(movies, gin.H{
+			"movie":         row.Movie,
+			"showtimeCount": row.Showtimes,
+			"cinemaCount":   len(row.Cinemas),
+			"formats":       i18n.Terms(lang, row.Formats),
+			"nextShowtime":  row.NextShowtime,
+		})
+	}
+
+	utils.SuccessResponse(c, 200, gin.H{
+		"city":   city,
+		"days":   days,
+		"movies": movies,
+		"total":  len(movies),
+	})
+}
+
+// weekStart - понедельник недели (местное время)
+func weekStart(t time.Time) time.Time {
+	t = t.In(time.Local)
+	offset := (int(t.Weekday()) + 6) % 7 // понедельник = 0
+	return time.Date(t.Year(), t.Month(), t.Day()-offset, 0, 0, 0, 0, time.Local)
+}
+
+// GetComingSoon - фильмы с датой релиза в будущем, сгруппированные по неделям
+// GET /api/movies/coming-soon?weeks=12
+func GetComingSoon(c *gin.Context) {
+	weeks, _ := strconv.Atoi(c.DefaultQuery("weeks", "12"))
+	if weeks < 1 || weeks > 52 {
+		weeks = 12
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	now := time.Now()
+	cursor, err := config.GetCollection("movies").Find(ctx,
+		bson.M{
+			"releaseDate": bson.M{"$gt": now, "$lt": weekStart(now).AddDate(0, 0, 7*weeks)},
+			"deletedAt":   bson.M{"$exists": false},
+		},
+		options.Find().SetSort(bson.D{{Key: "releaseDate", Value: 1}, {Key: "title", Value: 1}}),
+	)
+	if err != nil {
+		utils.ErrorResponse(c, 500, "Failed to fetch movies")
+		return
+	}
+	var movies []models.Movie
+	if err := cursor.All(ctx, &movies); err != nil {
+		utils.ErrorResponse(c, 500, "Failed to decode movies")
+		return
+	}
+
+	lang := i18n.FromContext(c)
+	calendar := []gin.H{}
+	var current gin.H
+	var currentStart time.Time
+	for i := range movies {
+		movie := &movies[i]
+		localizeMovie(lang, movie)
+
+		start := weekStart(movie.ReleaseDate)
+		if current == nil || !start.Equal(currentStart) {
+			currentStart = start
+			current = gin.H{
+				"weekStart": start.Format("2006-01-02"),
+				"weekEnd":   start.AddDate(0, 0, 6).Format("2006-01-02"),
+				"movies":    []models.Movie{},
+			}
+			calendar = append(calendar, current)
+		}
+		current["movies"] = append(current["movies"].([]models.Movie), *movie)
+	}
+
+	utils.SuccessResponse(c, 200, gin.H{
+		"weeks": calendar,
+		"total": len(movies),
+	})
+}
+
+// UpdateMovieStatuses - активировать фильмы в день релиза и снять с проката фильмы,
+// у которых прошел последний сеанс (go run ./cmd update-movie-status, по cron раз в час)
+func UpdateMovieStatuses(ctx context.Context) (activated int, deactivated int, err error) {
+	moviesCollection := config.GetCollection("movies")
+	now := time.Now()
+
+	// Релиз: каждый фильм активируется автоматически один раз (releasedAt)
+	result, err := moviesCollection.UpdateMany(ctx,
+		bson.M{
+			"isActive":    false,
+			"releaseDate": bson.M{"$lte": now, "$gte": now.Add(-releaseActivationWindow)},
+			"releasedAt":  bson.M{"$exists": false},
+			"deletedAt":   bson.M{"$exists": false},
+		},
+		bson.M{"$set": bson.M{"isActive": true, "releasedAt": now}},
+	)
+	if err != nil {
+		return 0, 0, err
+	}
+	activated = int(result.ModifiedCount)
+
+	// Конец проката: у вышедшего активного фильма были сеансы, и все они закончились
+	activeIDs, err := moviesCollection.Distinct(ctx, "_id", bson.M{
+		"isActive":    true,
+		"releaseDate": bson.M{"$lte": now},
+	})
+	if err != nil {
+		return activated, 0, err
+	}
+	if len(activeIDs) == 0 {
+		return activated, 0, nil
+	}
+
+	cursor, err := config.GetCollection("showtimes").Aggregate(ctx, bson.A{
+		bson.M{"$match": bson.M{"movieId": bson.M{"$in": activeIDs}}},
+		bson.M{"$group": bson.M{
+			"_id":     "$movieId",
+			"lastEnd": bson.M{"$max": "$endTime"},
+		}},
+		bson.M{"$match": bson.M{"lastEnd": bson.M{"$lt": now}}},
+	})
+	if err != nil {
+		return activated, 0, err
+	}
+	var finished []struct {
+		MovieID primitive.ObjectID `bson:"_id"`
+	}
+	if err := cursor.All(ctx, &finished); err != nil {
+		return activated, 0, err
+	}
+	if len(finished) == 0 {
+		return activated, 0, nil
+	}
+
+	finishedIDs := make([]primitive.ObjectID, len(finished))
+	for i, f := range finished {
+		finishedIDs[i] = f.MovieID
+	}
+	result, err = moviesCollection.UpdateMany(ctx,
+		bson.M{"_id": bson.M{"$in": finishedIDs}, "isActive": true},
+		bson.M{"$set": bson.M{"isActive": false}},
+	)
+	if err != nil {
+		return activated, 0, err
+	}
+
+	return activated, int(result.ModifiedCount), nil
+}
@@ -0,0 +1,482 @@
+package handlers
+
+import (
+	"cinema-booking/config"
+	"cinema-booking/models"
+	"cinema-booking/utils"
+	"context"
+	"fmt"
+	"math"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// Максимальная длина текста отзыва
+const maxReviewLength = 2000
+
+// hasAttendedShowtime - есть ли у пользователя подтвержденная бронь на прошедший сеанс
+// (showtimeFilter сужает сеансы: по фильму, кинотеатру и т.д.)
+func hasAttendedShowtime(ctx context.Context, userID primitive.ObjectID, showtimeFilter bson.M) (bool, error) {
+	showtimeFilter["startTime"] = bson.M{"$lt": time.Now()}
+
+	// Сначала сеансы пользователя (их мало), затем проверка по фильтру
+	showtimeIDs, err := config.GetCollection("bookings").Distinct(ctx, "showtimeId", bson.M{
+		"userId": userID,
+		"status": "confirmed",
+	})
+	if err != nil {
+		return false, err
+	}
+	if len(showtimeIDs) == 0 {
+		return false, nil
+	}
+
+	showtimeFilter["_id"] = bson.M{"$in": showtimeIDs}
+	count, err := config.GetCollection("showtimes").CountDocuments(ctx, showtimeFilter, options.Count().SetLimit(1))
+	if err != nil {
+		return false, err
+	}
+	return count > 0, nil
+}
+
+// RecalculateMovieRating - пересчитать среднюю оценку фильма по опубликованным отзывам
+func RecalculateMovieRating(ctx context.Context, movieID primitive.ObjectID) {
+	pipeline := bson.A{
+		bson.M{"$match": bson.M{"movieId": movieID, "status": "published"}},
+		bson.M{"$group": bson.M{
+			"_id":     nil,
+			"average": bson.M{"$avg": "$rating"},
+			"count":   bson.M{"$sum": 1},
+		}},
+	}
+
+	cursor, err := config.GetCollection("movie_reviews").Aggregate(ctx, pipeline)
+	if err != nil {
+		fmt.Printf("Warning: failed to aggregate movie rating: %v\n", err)
+		return
+	}
+
+	var results []bson.M
+	if err := cursor.All(ctx, &results); err != nil {
+		fmt.Printf("Warning: failed to aggregate movie rating: %v\n", err)
+		return
+	}
+
+	// Нет опубликованных отзывов - рейтинг сбрасывается
+	var rating, count float64
+	if len(results) > 0 {
+		rating = aggregateNumber(results[0]["average"])
+		count = aggregateNumber(results[0]["count"])
+	}
+
+	_, err = config.GetCollection("movies").UpdateOne(ctx,
+		bson.M{"_id": movieID},
+		bson.M{"$set": bson.M{
+			"reviewRating": math.Round(rating*10) / 10,
+			"reviewCount":  int(count),
+		}},
+	)
+	if err != nil {
+		fmt.Printf("Warning: failed to update movie rating: %v\n", err)
+	}
+}
+
+// MovieReviewRequest - отзыв о фильме
+type MovieReviewRequest struct {
+	Rating  float64 `json:"rating" binding:"required"`
+	Comment string  `json:"comment"`
+}
+
+// validateReviewRequest - проверить оценку и текст отзыва
+func validateReviewRequest(req *MovieReviewRequest) string {
+	req.Comment = utils.SanitizeString(req.Comment)
+	if req.Rating < 1 || req.Rating > 10 {
+		return "Rating must be between 1 and 10"
+	}
+	if len([]rune(req.Comment)) > maxReviewLength {
+		return "Comment is too long"
+	}
+	return ""
+}
+
+// GetMovieReviews - опубликованные отзывы о фильме с пагинацией и сортировкой
+func GetMovieReviews(c *gin.Context) {
+	movieID, err := primitive.ObjectIDFromHex(c.Param("id"))
+	if err != nil {
+		utils.ErrorResponse(c, 400, "Invalid movie ID")
+		return
+	}
+
+	// Пагинация
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "10"))
+	if page < 1 {
+		page = 1
+	}
+	if limit < 1 || limit > 100 {
+		limit = 10
+	}
+	skip := (page - 1) * limit
+
+	// Сортировка: только по разрешенным полям
+	sortBy := c.DefaultQuery("sortBy", "createdAt")
+	if sortBy != "createdAt" && sortBy != "rating" {
+		sortBy = "createdAt"
+	}
+	sortDirection := -1
+	if c.DefaultQuery("sortOrder", "desc") == "asc" {
+		sortDirection = 1
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	reviewsCollection := config.GetCollection("movie_reviews")
+	filter := bson.M{"movieId": movieID, "status": "published"}
+
+	findOptions := options.Find().
+		SetSkip(int64(skip)).
+		SetLimit(int64(limit)).
+		SetSort(bson.D{{Key: sortBy, Value: sortDirection}, {Key: "_id", Value: -1}})
+
+	cursor, err := reviewsCollection.Find(ctx, filter, findOptions)
+	if err != nil {
+		utils.ErrorResponse(c, 500, "Failed to fetch reviews")
+		return
+	}
+	defer cursor.Close(ctx)
+
+	reviews := []models.MovieReview{}
+	if err = cursor.All(ctx, &reviews); err != nil {
+		utils.ErrorResponse(c, 500, "Failed to decode reviews")
+		return
+	}
+
+	total, err := reviewsCollection.CountDocuments(ctx, filter)
+	if err != nil {
+		utils.ErrorResponse(c, 500, "Failed to count reviews")
+		return
+	}
+
+	utils.PaginatedResponse(c, reviews, page, limit, int(total))
+}
+
+// CreateMovieReview - оставить отзыв (только зрители, посмотревшие фильм; один отзыв на пользователя)
+func CreateMovieReview(c *gin.Context) {
+	userID, _ := c.Get("userId")
+	userObjectID, _ := primitive.ObjectIDFromHex(userID.(string))
+
+	movieID, err := primitive.ObjectIDFromHex(c.Param("id"))
+	if err != nil {
+		utils.ErrorResponse(c, 400, "Invalid movie ID")
+		return
+	}
+
+	var req MovieReviewRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ErrorResponse(c, 400, "Invalid request data: "+err.Error())
+		return
+	}
+
+	if msg := validateReviewRequest(&req); msg != "" {
+		utils.ErrorResponse(c, 400, msg)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	count, err := config.GetCollection("movies").CountDocuments(ctx, bson.M{"_id": movieID})
+	if err != nil || count == 0 {
+		utils.ErrorResponse(c, 404, "Movie not found")
+		return
+	}
+
+	attended, err := hasAttendedShowtime(ctx, userObjectID, bson.M{"movieId": movieID})
+	if err != nil {
+		utils.ErrorResponse(c, 500, "Failed to verify booking history")
+		return
+	}
+	if !attended {
+		utils.ErrorResponse(c, 403, "Only viewers with a confirmed booking for a past showtime can review this movie")
+		return
+	}
+
+	var user models.User
+	config.GetCollection("users").FindOne(ctx, bson.M{"_id": userObjectID}).Decode(&user)
+
+	review := models.MovieReview{
+		MovieID:   movieID,
+		UserID:    userObjectID,
+		UserName:  user.FullName,
+		Rating:    req.Rating,
+		Comment:   req.Comment,
+		Status:    "published",
+		CreatedAt: time.Now(),
+		UpdatedAt: time.Now(),
+	}
+
+	// Уникальный индекс (movieId, userId) гарантирует один отзыв на пользователя
+	result, err := config.GetCollection("movie_reviews").InsertOne(ctx, review)
+	if mongo.IsDuplicateKeyError(err) {
+		utils.ErrorResponse(c, 409, "You have already reviewed this movie")
+		return
+	}
+	if err != nil {
+		utils.ErrorResponse(c, 500, "Failed to create review")
+		return
+	}
+
+	review.ID = result.InsertedID.(primitive.ObjectID)
+	RecalculateMovieRating(ctx, movieID)
+
+	utils.SuccessWithMessage(c, 201, "Review added successfully", review)
+}
+
+// UpdateMovieReview - изменить свой отзыв
+func UpdateMovieReview(c *gin.Context) {
+	userID, _ := c.Get("userId")
+	userObjectID, _ := primitive.ObjectIDFromHex(userID.(string))
+
+	movieID, err := primitive.ObjectIDFromHex(c.Param("id"))
+	if err != nil {
+		utils.ErrorResponse(c, 400, "Invalid movie ID")
+		return
+	}
+
+	var req MovieReviewRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ErrorResponse(c, 400, "Invalid request data: "+err.Error())
+		return
+	}
+
+	if msg := validateReviewRequest(&req); msg != "" {
+		utils.ErrorResponse(c, 400, msg)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	var review models.MovieReview
+	err = config.GetCollection("movie_reviews").FindOneAndUpdate(ctx,
+		bson.M{"movieId": movieID, "userId": userObjectID},
+		bson.M{"$set": bson.M{
+			"rating":    req.Rating,
+			"comment":   req.Comment,
+			"updatedAt": time.Now(),
+		}},
+		options.FindOneAndUpdate().SetReturnDocument(options.After),
+	).Decode(&review)
+	if err != nil {
+		utils.ErrorResponse(c, 404, "Review not found")
+		return
+	}
+
+	RecalculateMovieRating(ctx, movieID)
+
+	utils.SuccessWithMessage(c, 200, "Review updated successfully", review)
+}
+
+// DeleteMovieReview - удалить свой отзыв
+func DeleteMovieReview(c *gin.Context) {
+	userID, _ := c.Get("userId")
+	userObjectID, _ := primitive.ObjectIDFromHex(userID.(string))
+
+	movieIDStr := c.Param("id")
+	movieID, err := primitive.ObjectIDFromHex(movieIDStr)
+	if err != nil {
+		utils.ErrorResponse(c, 400, "Invalid movie ID")
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	result, err := config.GetCollection("movie_reviews").DeleteOne(ctx, bson.M{"movieId": movieID, "userId": userObjectID})
+	if err != nil {
+		utils.ErrorResponse(c, 500, "Failed to delete review")
+		return
+	}
+
+	if result.DeletedCount == 0 {
+		utils.ErrorResponse(c, 404, "Review not found")
+		return
+	}
+
+	RecalculateMovieRating(ctx, movieID)
+
+	utils.SuccessWithMessage(c, 200, "Review deleted successfully", gin.H{
+		"movieId": movieIDStr,
+		"deleted": true,
+	})
+}
+
+// GetReviewsForModeration - отзывы для модерации (admin only)
+func GetReviewsForModeration(c *gin.Context) {
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "20"))
+	if page < 1 {
+		page = 1
+	}
+	if limit < 1 || limit > 100 {
+		limit = 20
+	}
+	skip := (page - 1) * limit
+
+	filter := bson.M{}
+	if status := c.Query("status"); status != "" {
+		filter["status"] = status
+	}
+	if c.Query("flagged") == "true" {
+		filter["flagged"] = true
+	}
+	if movieID, err := primitive.ObjectIDFromHex(c.Query("movieId")); err == nil {
+		filter["movieId"] = movieID
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	reviewsCollection := config.GetCollection("movie_reviews")
+
+	cursor, err := reviewsCollection.Find(ctx, filter, options.Find().
+		SetSkip(int64(skip)).
+		SetLimit(int64(limit)).
+		SetSort(bson.D{{Key: "createdAt", Value: -1}}))
+	if err != nil {
+		utils.ErrorResponse(c, 500, "Failed to fetch reviews")
+		return
+	}
+	defer cursor.Close(ctx)
+
+	reviews := []models.MovieReview{}
+	if err = cursor.All(ctx, &reviews); err != nil {
+		utils.ErrorResponse(c, 500, "Failed to decode reviews")
+		return
+	}
+
+	total, err := reviewsCollection.CountDocuments(ctx, filter)
+	if err != nil {
+		utils.ErrorResponse(c, 500, "Failed to count reviews")
+		return
+	}
+
+	utils.PaginatedResponse(c, reviews, page, limit, int(total))
+}
+
+// ModerateReviewRequest - действие модератора
+type ModerateReviewRequest struct {
+	Action string `json:"action" binding:"required"` // "hide", "publish", "flag", "unflag"
+	Reason string `json:"reason"`
+}
+
+// ModerateMovieReview - скрыть/опубликовать отзыв или пометить его (admin only)
+func ModerateMovieReview(c *gin.Context) {
+	adminID, _ := c.Get("userId")
+	adminObjectID, _ := primitive.ObjectIDFromHex(adminID.(string))
+
+	reviewID, err := primitive.ObjectIDFromHex(c.Param("id"))
+	if err != nil {
+		utils.ErrorResponse(c, 400, "Invalid review ID")
+		return
+	}
+
+	var req ModerateReviewRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ErrorResponse(c, 400, "Invalid request data: "+err.Error())
+		return
+	}
+
+	now := time.Now()
+	set := bson.M{"moderatedBy": adminObjectID, "moderatedAt": now, "updatedAt": now}
+	update := bson.M{"$set": set}
+
+	switch req.Action {
+	case "hide":
+		set["status"] = "hidden"
+	case "publish":
+		set["status"] = "published"
+	case "flag":
+		set["flagged"] = true
+		set["flagReason"] = utils.SanitizeString(req.Reason)
+	case "unflag":
+		update["$unset"] = bson.M{"flagged": "", "flagReason": ""}
+	default:
+		utils.ErrorResponse(c, 400, "Invalid action. Use: hide, publish, flag, or unflag")
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	var review models.MovieReview
+	err = config.GetCollection("movie_reviews").FindOneAndUpdate(ctx,
+		bson.M{"_id": reviewID},
+		update,
+		options.FindOneAndUpdate().SetReturnDocument(options.After),
+	).Decode(&review)
+	if err != nil {
+		utils.ErrorResponse(c, 404, "Review not found")
+		return
+	}
+
+	// Скрытые отзывы не участвуют в рейтинге
+	if req.Action == "hide" || req.Action == "publish" {
+		RecalculateMovieRating(ctx, review.MovieID)
+	}
+
+	utils.SuccessWithMessage(c, 200, "Review moderated successfully", review)
+}
+
+// MigrateEmbeddedReviews - перенести отзывы из документов фильмов в movie_reviews
+func MigrateEmbeddedReviews(ctx context.Context) (int, int, error) {
+	moviesCollection := config.GetCollection("movies")
+	reviewsCollection := config.GetCollection("movie_reviews")
+
+	cursor, err := moviesCollection.Find(ctx, bson.M{"reviews.0": bson.M{"$exists": true}})
+	if err != nil {
+		return 0, 0, err
+	}
+	defer cursor.Close(ctx)
+
+	var movies []struct {
+		ID      primitive.ObjectID   `bson:"_id"`
+		Reviews []models.MovieReview `bson:"reviews"`
+	}
+	if err := cursor.All(ctx, &movies); err != nil {
+		return 0, 0, err
+	}
+
+	migrated := 0
+	for _, movie := range movies {
+		for _, review := range movie.Reviews {
+			review.ID = primitive.NilObjectID
+			review.MovieID = movie.ID
+			review.Status = "published"
+			review.UpdatedAt = review.CreatedAt
+
+			_, err := reviewsCollection.InsertOne(ctx, review)
+			if mongo.IsDuplicateKeyError(err) {
+				continue // отзыв этого пользователя уже перенесен
+			}
+			if err != nil {
+				return len(movies), migrated, err
+			}
+			migrated++
+		}
+
+		if _, err := moviesCollection.UpdateOne(ctx, bson.M{"_id": movie.ID}, bson.M{"$unset": bson.M{"reviews": ""}}); err != nil {
+			return len(movies), migrated, err
+		}
+		RecalculateMovieRating(ctx, movie.ID)
+	}
+
+	return len(movies), migrated, nil
+}
@@ -0,0 +1,688 @@
+package handlers
+
+import (
+	"bytes"
+	"cinema-booking/config"
+	"cinema-booking/models"
+	"cinema-booking/utils"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// ScheduleRow - одна строка расписания (формат импорта/экспорта для планировщиков)
+type ScheduleRow struct {
+	Cinema     string  `json:"cinema"`     // название или ID кинотеатра
+	HallNumber int     `json:"hallNumber"` // номер зала внутри кинотеатра
+	Movie      string  `json:"movie"`      // название или ID фильма
+	StartTime  string  `json:"startTime"`  // RFC3339 или "2006-01-02 15:04"
+	Format     string  `json:"format"`     // "2D", "3D", "IMAX"
+	Language   string  `json:"language"`
+	Subtitles  string  `json:"subtitles"`
+	BasePrice  float64 `json:"basePrice"`
+}
+
+// ScheduleRowError - ошибки валидации конкретной строки
+type ScheduleRowError struct {
+	Row    int      `json:"row"` // номер строки (с 1, без учета заголовка)
+	Errors []string `json:"errors"`
+}
+
+// ScheduleImportResult - результат импорта расписания
+type ScheduleImportResult struct {
+	TotalRows int                `json:"totalRows"`
+	Imported  int                `json:"imported"`
+	Failed    int                `json:"failed"`
+	DryRun    bool               `json:"dryRun"`
+	Errors    []ScheduleRowError `json:"errors"`
+	Showtimes []models.Showtime  `json:"showtimes"`
+}
+
+// Колонки CSV в порядке экспорта
+var scheduleCSVHeader = []string{"cinema", "hallNumber", "movie", "startTime", "format", "language", "subtitles", "basePrice"}
+
+// Допустимые форматы времени начала сеанса
+var scheduleTimeLayouts = []string{time.RFC3339, "2006-01-02 15:04", "2006-01-02T15:04"}
+
+// ParseScheduleCSV - прочитать строки расписания из CSV (первая строка - заголовок)
+func ParseScheduleCSV(r io.Reader) ([]ScheduleRow, error) {
+	reader := csv.NewReader(r)
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read CSV header: %w", err)
+	}
+
+	// Позиции колонок по имени (порядок колонок может быть любым)
+	columns := make(map[string]int)
+	for i, name := range header {
+		columns[strings.TrimSpace(strings.TrimPrefix(name, "\ufeff"))] = i
+	}
+	for _, required := range []string{"cinema", "hallNumber", "movie", "startTime"} {
+		if _, ok := columns[required]; !ok {
+			return nil, fmt.Errorf("CSV header is missing column %q", required)
+		}
+	}
+
+	get := func(record []string, name string) string {
+		if i, ok := columns[name]; ok && i < len(record) {
+			return strings.TrimSpace(record[i])
+		}
+		return ""
+	}
+
+	var rows []ScheduleRow
+	for line := 2; ; line++ {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read CSV line %d: %w", line, err)
+		}
+
+		row := ScheduleRow{
+			Cinema:    get(record, "cinema"),
+			Movie:     get(record, "movie"),
+			StartTime: get(record, "startTime"),
+			Format:    get(record, "format"),
+			Language:  get(record, "language"),
+			Subtitles: get(record, "subtitles"),
+		}
+
+		// Числовые поля: некорректное значение превращается в ошибку строки при валидации
+		row.HallNumber, _ = strconv.Atoi(get(record, "hallNumber"))
+		if price := get(record, "basePrice"); price != "" {
+			row.BasePrice, err = strconv.ParseFloat(price, 64)
+			if err != nil {
+				row.BasePrice = -1
+			}
+		}
+
+		rows = append(rows, row)
+	}
+
+	return rows, nil
+}
+
+// ParseScheduleJSON - прочитать строки расписания из JSON массива
+func ParseScheduleJSON(r io.Reader) ([]ScheduleRow, error) {
+	var rows []ScheduleRow
+	if err := json.NewDecoder(r).Decode(&rows); err != nil {
+		return nil, fmt.Errorf("failed to parse JSON: %w", err)
+	}
+	return rows, nil
+}
+
+// ParseSchedule - прочитать расписание в формате "csv" или "json"
+func ParseSchedule(r io.Reader, format string) ([]ScheduleRow, error) {
+	switch format {
+	case "csv":
+		return ParseScheduleCSV(r)
+	case "json":
+		return ParseScheduleJSON(r)
+	default:
+		return nil, fmt.Errorf("unsupported format %q. Use: csv or json", format)
+	}
+}
+
+// parseScheduleTime - разобрать время начала в одном из допустимых форматов
+func parseScheduleTime(value string) (time.Time, error) {
+	for _, layout := range scheduleTimeLayouts {
+		if t, err := time.ParseInLocation(layout, value, time.Local); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, errors.New("invalid startTime. Use RFC3339 or YYYY-MM-DD HH:MM")
+}
+
+// scheduleLookup - кэш справочников, чтобы не ходить в БД на каждую строку
+type scheduleLookup struct {
+	ctx     context.Context
+	cinemas map[string][]models.Cinema
+	movies  map[string][]models.Movie
+	halls   map[string]*models.Hall
+}
+
+func newScheduleLookup(ctx context.Context) *scheduleLookup {
+	return &scheduleLookup{
+		ctx:     ctx,
+		cinemas: make(map[string][]models.Cinema),
+		movies:  make(map[string][]models.Movie),
+		halls:   make(map[string]*models.Hall),
+	}
+}
+
+// findCinema - найти кинотеатр по ID или точному названию (без учета регистра)
+func (l *scheduleLookup) findCinema(ref string) (*models.Cinema, error) {
+	key := strings.ToLower(ref)
+	cinemas, ok := l.cinemas[key]
+	if !ok {
+		filter := bson.M{"name": bson.M{"$regex": "^" + regexp.QuoteMeta(ref) + "$", "$options": "i"}}
+		if id, err := primitive.ObjectIDFromHex(ref); err == nil {
+			filter = bson.M{"_id": id}
+		}
+
+		cursor, err := config.GetCollection("cinemas").Find(l.ctx, filter)
+		if err != nil {
+			return nil, errors.New("failed to look up cinema")
+		}
+		if err := cursor.All(l.ctx, &cinemas); err != nil {
+			return nil, errors.New("failed to decode cinema")
+		}
+		l.cinemas[key] = cinemas
+	}
+
+	switch len(cinemas) {
+	case 0:
+		return nil, fmt.Errorf("cinema %q not found", ref)
+	case 1:
+		return &cinemas[0], nil
+	default:
+		return nil, fmt.Errorf("cinema %q is ambiguous, use its ID", ref)
+	}
+}
+
+// findMovie - найти фильм по ID или названию (title, titleRu, titleKz)
+func (l *scheduleLookup) findMovie(ref string) (*models.Movie, error) {
+	key := strings.ToLower(ref)
+	movies, ok := l.movies[key]
+	if !ok {
+		pattern := bson.M{"$regex": "^" + regexp.QuoteMeta(ref) + "$", "$options": "i"}
+		filter := bson.M{"$or": bson.A{
+			bson.M{"title": pattern},
+			bson.M{"titleRu": pattern},
+			bson.M{"titleKz": pattern},
+		}}
+		if id, err := primitive.ObjectIDFromHex(ref); err == nil {
+			filter = bson.M{"_id": id}
+		}
+
+		cursor, err := config.GetCollection("movies").Find(l.ctx, filter)
+		if err != nil {
+			return nil, errors.New("failed to look up movie")
+		}
+		if err := cursor.All(l.ctx, &movies); err != nil {
+			return nil, errors.New("failed to decode movie")
+		}
+		l.movies[key] = movies
+	}
+
+	switch len(movies) {
+	case 0:
+		return nil, fmt.Errorf("movie %q not found", ref)
+	case 1:
+		return &movies[0], nil
+	default:
+		return nil, fmt.Errorf("movie %q is ambiguous, use its ID", ref)
+	}
+}
+
+// findHall - найти зал кинотеатра по номеру
+func (l *scheduleLookup) findHall(cinemaID primitive.ObjectID, hallNumber int) (*models.Hall, error) {
+	key := fmt.Sprintf("%s-%d", cinemaID.Hex(), hallNumber)
+	hall, ok := l.halls[key]
+	if !ok {
+		var found models.Hall
+		err := config.GetCollection("halls").FindOne(l.ctx, bson.M{
+			"cinemaId":   cinemaID,
+			"hallNumber": hallNumber,
+		}).Decode(&found)
+		if err == nil {
+			hall = &found
+		}
+		l.halls[key] = hall
+	}
+
+	if hall == nil {
+		return nil, fmt.Errorf("hall %d not found in this cinema", hallNumber)
+	}
+	return hall, nil
+}
+
+// ValidateScheduleRows - проверить строки расписания против фильмов, залов и существующих сеансов.
+// Возвращает готовые к вставке сеансы и ошибки по строкам.
+func ValidateScheduleRows(ctx context.Context, rows []ScheduleRow) ([]models.Showtime, []ScheduleRowError) {
+	lookup := newScheduleLookup(ctx)
+
+	var valid []models.Showtime
+	rowErrors := []ScheduleRowError{}
+
+	for i, row := range rows {
+		var errs []string
+
+		var cinema *models.Cinema
+		var hall *models.Hall
+		var movie *models.Movie
+
+		// Кинотеатр и зал
+		if strings.TrimSpace(row.Cinema) == "" {
+			errs = append(errs, "cinema is required")
+		} else if found, err := lookup.findCinema(strings.TrimSpace(row.Cinema)); err != nil {
+			errs = append(errs, err.Error())
+		} else {
+			cinema = found
+		}
+
+		if row.HallNumber < 1 {
+			errs = append(errs, "hallNumber must be a positive integer")
+		} else if cinema != nil {
+			if found, err := lookup.findHall(cinema.ID, row.HallNumber); err != nil {
+				errs = append(errs, err.Error())
+			} else {
+				hall = found
+			}
+		}
+
+		// Фильм
+		if strings.TrimSpace(row.Movie) == "" {
+			errs = append(errs, "movie is required")
+		} else if found, err := lookup.findMovie(strings.TrimSpace(row.Movie)); err != nil {
+			errs = append(errs, err.Error())
+		} else if !found.IsActive {
+			errs = append(errs, fmt.Sprintf("movie %q is not active", found.Title))
+		} else {
+			movie = found
+		}
+
+		// Время начала
+		startTime, err := parseScheduleTime(strings.TrimSpace(row.StartTime))
+		if err != nil {
+			errs = append(errs, err.Error())
+		} else if startTime.Before(time.Now()) {
+			errs = append(errs, "startTime must be in the future")
+		}
+
+		// Цена
+		if row.BasePrice < 0 {
+			errs = append(errs, "basePrice must be a non-negative number")
+		}
+
+		if len(errs) == 0 {
+			showtime := models.Showtime{
+				MovieID:        movie.ID,
+				CinemaID:       cinema.ID,
+				HallID:         hall.ID,
+				StartTime:      startTime,
+				EndTime:        startTime.Add(time.Duration(movie.Duration) * time.Minute),
+				BasePrice:      row.BasePrice,
+				Format:         row.Format,
+				Language:       row.Language,
+				Subtitles:      row.Subtitles,
+				AvailableSeats: hall.Capacity,
+				BookedSeats:    []models.BookedSeat{},
+				CreatedAt:      time.Now(),
+			}
+			if showtime.BasePrice == 0 {
+				showtime.BasePrice = 2000 // Базовая цена по умолчанию (как в CreateShowtime)
+			}
+			if showtime.Format == "" {
+				showtime.Format = "2D"
+			}
+
+			// Пересечение с уже существующими сеансами в этом зале
+			conflicts, err := countOverlappingShowtimes(ctx, hall.ID, showtime.StartTime, showtime.EndTime)
+			if err != nil {
+				errs = append(errs, "failed to check existing showtimes")
+			} else if conflicts > 0 {
+				errs = append(errs, "overlaps an existing showtime in this hall")
+			}
+
+			// Пересечение с другими строками этого же импорта
+			for _, other := range valid {
+				if other.HallID == showtime.HallID &&
+					other.StartTime.Before(showtime.EndTime) && other.EndTime.After(showtime.StartTime) {
+					errs = append(errs, "overlaps another row of this import in the same hall")
+					break
+				}
+			}
+
+			if len(errs) == 0 {
+				valid = append(valid, showtime)
+			}
+		}
+
+		if len(errs) > 0 {
+			rowErrors = append(rowErrors, ScheduleRowError{Row: i + 1, Errors: errs})
+		}
+	}
+
+	return valid, rowErrors
+}
+
+// ErrScheduleConflict - после проверки в тех же залах появились пересекающиеся сеансы
+var ErrScheduleConflict = errors.New("showtimes were created concurrently in the same halls, nothing was imported")
+
+// rollbackScheduleImport - удалить уже вставленные сеансы неудачного импорта
+func rollbackScheduleImport(ctx context.Context, ids []primitive.ObjectID) {
+	if _, err := config.GetCollection("showtimes").DeleteMany(ctx, bson.M{"_id": bson.M{"$in": ids}}); err != nil {
+		fmt.Printf("Warning: failed to roll back schedule import: %v\n", err)
+	}
+}
+
+// ImportScheduleRows - проверить строки и сохранить все валидные сеансы (либо ни одного)
+func ImportScheduleRows(ctx context.Context, rows []ScheduleRow, dryRun bool) (*ScheduleImportResult, error) {
+	showtimes, rowErrors := ValidateScheduleRows(ctx, rows)
+
+	result := &ScheduleImportResult{
+		TotalRows: len(rows),
+		Failed:    len(rowErrors),
+		DryRun:    dryRun,
+		Errors:    rowErrors,
+		Showtimes: []models.Showtime{},
+	}
+
+	if dryRun || len(showtimes) == 0 {
+		result.Showtimes = append(result.Showtimes, showtimes...)
+		return result, nil
+	}
+
+	// Залы импорта блокируются до конца вставки: CreateShowtime и другие импорты
+	// в этих залах ждут, поэтому повторная проверка ниже окончательная
+	hallIDs := make([]primitive.ObjectID, len(showtimes))
+	for i := range showtimes {
+		hallIDs[i] = showtimes[i].HallID
+	}
+	release, err := lockHalls(ctx, hallIDs)
+	if err != nil {
+		if errors.Is(err, ErrHallBusy) {
+			return nil, ErrScheduleConflict
+		}
+		return nil, fmt.Errorf("failed to lock halls: %w", err)
+	}
+	defer release()
+
+	// Между проверкой строк и блокировкой сеансы в этих залах могли создать
+	for _, showtime := range showtimes {
+		conflicts, err := countOverlappingShowtimes(ctx, showtime.HallID, showtime.StartTime, showtime.EndTime)
+		if err != nil {
+			return nil, fmt.Errorf("failed to check existing showtimes: %w", err)
+		}
+		if conflicts > 0 {
+			return nil, ErrScheduleConflict
+		}
+	}
+
+	// ID назначаются заранее: при ошибке вставленные сеансы удаляются (транзакции требуют
+	// replica set, а по умолчанию MongoDB запускается standalone)
+	ids := make([]primitive.ObjectID, len(showtimes))
+	docs := make([]interface{}, len(showtimes))
+	for i := range showtimes {
+		ids[i] = primitive.NewObjectID()
+		showtimes[i].ID = ids[i]
+		docs[i] = showtimes[i]
+	}
+
+	if _, err := config.GetCollection("showtimes").InsertMany(ctx, docs, options.InsertMany().SetOrdered(true)); err != nil {
+		rollbackScheduleImport(ctx, ids)
+		return nil, fmt.Errorf("failed to save showtimes: %w", err)
+	}
+
+	result.Imported = len(showtimes)
+	result.Showtimes = showtimes
+
+	// Уведомить тех, у кого фильмы в watchlist (первые сеансы в их городе)
+	if err := notifyWatchlistOnSale(ctx, showtimes); err != nil {
+		fmt.Printf("Warning: failed to enqueue watchlist notifications: %v\n", err)
+	}
+
+	return result, nil
+}
+
+// scheduleFormat - определить формат файла по параметру, имени файла или Content-Type
+func scheduleFormat(c *gin.Context, filename string) string {
+	if format := strings.ToLower(c.Query("format")); format != "" {
+		return format
+	}
+	switch {
+	case strings.HasSuffix(strings.ToLower(filename), ".json"):
+		return "json"
+	case strings.HasSuffix(strings.ToLower(filename), ".csv"):
+		return "csv"
+	case strings.Contains(c.ContentType(), "json"):
+		return "json"
+	default:
+		return "csv"
+	}
+}
+
+// ImportSchedule - импорт расписания из CSV/JSON (admin only)
+// Файл передается как multipart поле "file" или телом запроса.
+func ImportSchedule(c *gin.Context) {
+	var reader io.Reader
+	var filename string
+
+	// 1. Получить содержимое файла
+	if file, header, err := c.Request.FormFile("file"); err == nil {
+		defer file.Close()
+		reader = file
+		filename = header.Filename
+	} else {
+		body, err := io.ReadAll(io.LimitReader(c.Request.Body, config.AppConfig.MaxUploadSize))
+		if err != nil || len(body) == 0 {
+			utils.ErrorResponse(c, 400, "Schedule file is required (multipart field \"file\" or request body)")
+			return
+		}
+		reader = bytes.NewReader(body)
+	}
+
+	// 2. Парсинг
+	rows, err := ParseSchedule(reader, scheduleFormat(c, filename))
+	if err != nil {
+		utils.ErrorResponse(c, 400, err.Error())
+		return
+	}
+
+	if len(rows) == 0 {
+		utils.ErrorResponse(c, 400, "Schedule file contains no rows")
+		return
+	}
+
+	if len(rows) > 5000 {
+		utils.ErrorResponse(c, 400, "Maximum 5000 rows per import")
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
+	defer cancel()
+
+	// 3. Валидация и сохранение
+	result, err := ImportScheduleRows(ctx, rows, c.Query("dryRun") == "true")
+	if errors.Is(err, ErrScheduleConflict) {
+		utils.ErrorResponse(c, 409, "Showtimes were created concurrently in the same halls. Import rolled back, try again")
+		return
+	}
+	if err != nil {
+		utils.ErrorResponse(c, 500, err.Error())
+		return
+	}
+
+	message := fmt.Sprintf("Imported %d of %d rows", result.Imported, result.TotalRows)
+	if result.DryRun {
+		message = fmt.Sprintf("Dry run: %d of %d rows are valid", len(result.Showtimes), result.TotalRows)
+	}
+
+	utils.SuccessWithMessage(c, 200, message, result)
+}
+
+// ExportScheduleRows - выгрузить сеансы за период в формате импорта
+func ExportScheduleRows(ctx context.Context, from, to time.Time, cinemaID primitive.ObjectID) ([]ScheduleRow, error) {
+	filter := bson.M{"startTime": bson.M{"$gte": from, "$lt": to}}
+	if !cinemaID.IsZero() {
+		filter["cinemaId"] = cinemaID
+	}
+
+	pipeline := bson.A{
+		bson.M{"$match": filter},
+		bson.M{"$sort": bson.D{{Key: "cinemaId", Value: 1}, {Key: "startTime", Value: 1}}},
+		bson.M{"$lookup": bson.M{
+			"from":         "cinemas",
+			"localField":   "cinemaId",
+			"foreignField": "_id",
+			"as":           "cinema",
+		}},
+		bson.M{"$unwind": "$cinema"},
+		bson.M{"$lookup": bson.M{
+			"from":         "halls",
+			"localField":   "hallId",
+			"foreignField": "_id",
+			"as":           "hall",
+		}},
+		bson.M{"$unwind": "$hall"},
+		bson.M{"$lookup": bson.M{
+			"from":         "movies",
+			"localField":   "movieId",
+			"foreignField": "_id",
+			"as":           "movie",
+		}},
+		bson.M{"$unwind": "$movie"},
+		bson.M{"$project": bson.M{
+			"cinema":     "$cinema.name",
+			"hallNumber": "$hall.hallNumber",
+			"movie":      "$movie.title",
+			"startTime":  1,
+			"format":     1,
+			"language":   1,
+			"subtitles":  1,
+			"basePrice":  1,
+		}},
+	}
+
+	cursor, err := config.GetCollection("showtimes").Aggregate(ctx, pipeline)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var docs []struct {
+		Cinema     string    `bson:"cinema"`
+		HallNumber int       `bson:"hallNumber"`
+		Movie      string    `bson:"movie"`
+		StartTime  time.Time `bson:"startTime"`
+		Format     string    `bson:"format"`
+		Language   string    `bson:"language"`
+		Subtitles  string    `bson:"subtitles"`
+		BasePrice  float64   `bson:"basePrice"`
+	}
+	if err := cursor.All(ctx, &docs); err != nil {
+		return nil, err
+	}
+
+	rows := make([]ScheduleRow, 0, len(docs))
+	for _, doc := range docs {
+		rows = append(rows, ScheduleRow{
+			Cinema:     doc.Cinema,
+			HallNumber: doc.HallNumber,
+			Movie:      doc.Movie,
+			StartTime:  doc.StartTime.In(time.Local).Format(time.RFC3339),
+			Format:     doc.Format,
+			Language:   doc.Language,
+			Subtitles:  doc.Subtitles,
+			BasePrice:  doc.BasePrice,
+		})
+	}
+
+	return rows, nil
+}
+
+// WriteScheduleCSV - записать строки расписания в CSV
+func WriteScheduleCSV(w io.Writer, rows []ScheduleRow) error {
+	writer := csv.NewWriter(w)
+	if err := writer.Write(scheduleCSVHeader); err != nil {
+		return err
+	}
+	for _, row := range rows {
+		record := []string{
+			row.Cinema,
+			strconv.Itoa(row.HallNumber),
+			row.Movie,
+			row.StartTime,
+			row.Format,
+			row.Language,
+			row.Subtitles,
+			strconv.FormatFloat(row.BasePrice, 'f', -1, 64),
+		}
+		if err := writer.Write(record); err != nil {
+			return err
+		}
+	}
+	writer.Flush()
+	return writer.Error()
+}
+
+// ExportSchedule - экспорт расписания за период в CSV/JSON (admin only)
+func ExportSchedule(c *gin.Context) {
+	// Период: ?from=2026-02-01&to=2026-02-07 (включительно)
+	from, err := time.ParseInLocation("2006-01-02", c.Query("from"), time.Local)
+	if err != nil {
+		utils.ErrorResponse(c, 400, "Invalid or missing 'from' date. Use: YYYY-MM-DD")
+		return
+	}
+
+	to := from.AddDate(0, 0, 7)
+	if toStr := c.Query("to"); toStr != "" {
+		toDate, err := time.ParseInLocation("2006-01-02", toStr, time.Local)
+		if err != nil {
+			utils.ErrorResponse(c, 400, "Invalid 'to' date. Use: YYYY-MM-DD")
+			return
+		}
+		to = toDate.AddDate(0, 0, 1)
+	}
+
+	if !to.After(from) {
+		utils.ErrorResponse(c, 400, "'to' must not be before 'from'")
+		return
+	}
+
+	var cinemaID primitive.ObjectID
+	if cinemaIDStr := c.Query("cinemaId"); cinemaIDStr != "" {
+		cinemaID, err = primitive.ObjectIDFromHex(cinemaIDStr)
+		if err != nil {
+			utils.ErrorResponse(c, 400, "Invalid cinema ID")
+			return
+		}
+	}
+
+	format := c.DefaultQuery("format", "csv")
+	if format != "csv" && format != "json" {
+		utils.ErrorResponse(c, 400, "Invalid format. Use: csv or json")
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	rows, err := ExportScheduleRows(ctx, from, to, cinemaID)
+	if err != nil {
+		utils.ErrorResponse(c, 500, "Failed to export schedule")
+		return
+	}
+
+	filename := fmt.Sprintf("schedule_%s_%s.%s", from.Format("20060102"), to.AddDate(0, 0, -1).Format("20060102"), format)
+	c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%q", filename))
+
+	if format == "json" {
+		c.JSON(200, rows)
+		return
+	}
+
+	var buf bytes.Buffer
+	if err := WriteScheduleCSV(&buf, rows); err != nil {
+		utils.ErrorResponse(c, 500, "Failed to write CSV")
+		return
+	}
+	c.Data(200, "text/csv; charset=utf-8", buf.Bytes())
+}
@@ -0,0 +1,228 @@
+package handlers
+
+import (
+	"cinema-booking/config"
+	"cinema-booking/i18n"
+	"cinema-booking/models"
+	"cinema-booking/search"
+	"cinema-booking/utils"
+	"context"
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+var (
+	// Текущий поисковый индекс (заменяется целиком при перестроении)
+	searchIndex atomic.Pointer[search.Index]
+	// Перестроения выполняются по одному
+	searchRebuildMu sync.Mutex
+	// Уже запланировано отложенное перестроение
+	searchRebuildPending atomic.Bool
+)
+
+// Задержка перед перестроением: несколько изменений подряд дают одно перестроение
+const searchRebuildDelay = 2 * time.Second
+
+// loadSearchEntries - собрать объекты поиска: активные фильмы, персоны (people) и кинотеатры
+func loadSearchEntries(ctx context.Context) ([]search.Entry, error) {
+	entries := []search.Entry{}
+
+	cursor, err := config.GetCollection("movies").Find(ctx, bson.M{"isActive": true})
+	if err != nil {
+		return nil, err
+	}
+	var movies []models.Movie
+	if err := cursor.All(ctx, &movies); err != nil {
+		return nil, err
+	}
+
+	// Число активных фильмов персоны (для ранжирования)
+	movieCounts := map[primitive.ObjectID]int{}
+	for _, movie := range movies {
+		subtitle := ""
+		if !movie.ReleaseDate.IsZero() {
+			subtitle = strconv.Itoa(movie.ReleaseDate.Year())
+		}
+
+		fields := []string{movie.Title, movie.TitleRu, movie.TitleKz, movie.Director}
+		fields = append(fields, movie.Cast...)
+
+		entries = append(entries, search.Entry{
+			Type: search.TypeMovie,
+			ID:   movie.ID.Hex(),
+			Labels: map[string]string{
+				i18n.English: movie.Title,
+				i18n.Russian: movie.TitleRu,
+				i18n.Kazakh:  movie.TitleKz,
+			},
+			Subtitle: subtitle,
+			Fields:   fields,
+			Boost:    movie.IMDBRating / 100,
+		})
+
+		for _, credit := range movie.Credits {
+			movieCounts[credit.PersonID]++
+		}
+	}
+
+	cursor, err = config.GetCollection("people").Find(ctx, bson.M{})
+	if err != nil {
+		return nil, err
+	}
+	var people []models.Person
+	if err := cursor.All(ctx, &people); err != nil {
+		return nil, err
+	}
+
+	for _, person := range people {
+		entries = append(entries, search.Entry{
+			Type: search.TypePerson,
+			ID:   person.ID.Hex(),
+			Labels: map[string]string{
+				i18n.English: person.Name,
+				i18n.Russian: person.NameRu,
+				i18n.Kazakh:  person.NameKz,
+			},
+			Subtitle: strings.Join(person.Roles, ", "),
+			Fields:   []string{person.Name, person.NameRu, person.NameKz},
+			Boost:    min(float64(movieCounts[person.ID]), 10) / 200,
+		})
+	}
+
+	cursor, err = config.GetCollection("cinemas").Find(ctx, bson.M{})
+	if err != nil {
+		return nil, err
+	}
+	var cinemas []models.Cinema
+	if err := cursor.All(ctx, &cinemas); err != nil {
+		return nil, err
+	}
+
+	for _, cinema := range cinemas {
+		entries = append(entries, search.Entry{
+			Type:     search.TypeCinema,
+			ID:       cinema.ID.Hex(),
+			Labels:   map[string]string{i18n.English: cinema.Name},
+			Subtitle: cinema.City,
+			Fields:   []string{cinema.Name, cinema.City, cinema.Address},
+			Boost:    cinema.Rating / 100,
+		})
+	}
+
+	return entries, nil
+}
+
+// RebuildSearchIndex - перестроить поисковый индекс из базы
+func RebuildSearchIndex() error {
+	searchRebuildMu.Lock()
+	defer searchRebuildMu.Unlock()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	entries, err := loadSearchEntries(ctx)
+	if err != nil {
+		return err
+	}
+
+	searchIndex.Store(search.NewIndex(entries))
+	return nil
+}
+
+// scheduleSearchIndexRebuild - отложенно перестроить индекс после изменения фильмов/кинотеатров
+func scheduleSearchIndexRebuild() {
+	if !searchRebuildPending.CompareAndSwap(false, true) {
+		return
+	}
+
+	go func() {
+		time.Sleep(searchRebuildDelay)
+		searchRebuildPending.Store(false)
+		if err := RebuildSearchIndex(); err != nil {
+			fmt.Printf("Warning: failed to rebuild search index: %v\n", err)
+		}
+	}()
+}
+
+// StartSearchIndex - построить индекс при старте и периодически обновлять его
+// (подхватывает изменения, сделанные в обход API: сиды, импорт)
+func StartSearchIndex(refreshInterval time.Duration) {
+	if err := RebuildSearchIndex(); err != nil {
+		log.Printf("⚠️ Warning: failed to build search index: %v", err)
+	} else {
+		log.Printf("🔎 Search index built: %d entries", searchIndex.Load().Len())
+	}
+
+	go func() {
+		ticker := time.NewTicker(refreshInterval)
+		defer ticker.Stop()
+		for range ticker.C {
+			if err := RebuildSearchIndex(); err != nil {
+				fmt.Printf("Warning: failed to refresh search index: %v\n", err)
+			}
+		}
+	}()
+}
+
+// SearchSuggest - подсказки при вводе: фильмы, персоны и кинотеатры
+// GET /api/search/suggest?q=аватр&limit=10&types=movie,cinema
+func SearchSuggest(c *gin.Context) {
+	query := strings.TrimSpace(c.Query("q"))
+	if query == "" {
+		utils.ErrorResponse(c, 400, "Query parameter q is required")
+		return
+	}
+	if len([]rune(query)) > 100 {
+		utils.ErrorResponse(c, 400, "Query is too long")
+		return
+	}
+
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "10"))
+	if limit < 1 || limit > 25 {
+		limit = 10
+	}
+
+	types := map[string]bool{}
+	if typesParam := c.Query("types"); typesParam != "" {
+		for _, t := range strings.Split(typesParam, ",") {
+			t = strings.TrimSpace(t)
+			if t != search.TypeMovie && t != search.TypePerson && t != search.TypeCinema {
+				utils.ErrorResponse(c, 400, "Invalid types. Use: movie, person, cinema")
+				return
+			}
+			types[t] = true
+		}
+	}
+
+	index := searchIndex.Load()
+	if index == nil {
+		utils.ErrorResponse(c, 503, "Search index is not ready")
+		return
+	}
+
+	lang := i18n.FromContext(c)
+	suggestions := []gin.H{}
+	for _, s := range index.Suggest(query, limit, types) {
+		suggestions = append(suggestions, gin.H{
+			"type":     s.Entry.Type,
+			"id":       s.Entry.ID,
+			"label":    i18n.Pick(lang, s.Entry.Labels),
+			"subtitle": s.Entry.Subtitle,
+			"score":    float64(int(s.Score*1000)) / 1000,
+		})
+	}
+
+	utils.SuccessResponse(c, 200, gin.H{
+		"query":       query,
+		"suggestions": suggestions,
+	})
+}
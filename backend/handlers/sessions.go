@@ -0,0 +1,258 @@
+package handlers
+
+import (
+	"cinema-booking/config"
+	"cinema-booking/models"
+	"cinema-booking/utils"
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// Срок жизни сессии (семейства refresh токенов), если JWT_REFRESH_EXPIRATION некорректен
+const defaultRefreshTokenTTL = 30 * 24 * time.Hour
+
+// Окно, в котором повтор только что замененного токена - гонка вкладок, а не кража:
+// другая вкладка обменяла тот же токен из общего localStorage
+const refreshReuseGrace = 10 * time.Second
+
+// RefreshTokenRequest - обмен refresh токена на новую пару токенов
+type RefreshTokenRequest struct {
+	RefreshToken string `json:"refreshToken" binding:"required"`
+}
+
+// LogoutRequest - выход: текущая сессия, сессия refresh токена или все устройства
+type LogoutRequest struct {
+	RefreshToken string `json:"refreshToken"`
+	AllDevices   bool   `json:"allDevices"`
+}
+
+// refreshTokenTTL - срок жизни сессии из конфига
+func refreshTokenTTL() time.Duration {
+	ttl, err := time.ParseDuration(config.AppConfig.JWTRefreshTTL)
+	if err != nil || ttl <= 0 {
+		return defaultRefreshTokenTTL
+	}
+	return ttl
+}
+
+// issueTokens - выдать access токен и новый refresh токен.
+// family == nil - новая сессия; при обновлении семейство, срок сессии и отметка MFA сохраняются.
+func issueTokens(ctx context.Context, c *gin.Context, user models.User, deviceID string, mfa bool, family *models.RefreshToken) (gin.H, error) {
+	if family != nil {
+		mfa = family.MFA
+	}
+	accessToken, claims, err := utils.GenerateToken(user, mfa)
+	if err != nil {
+		return nil, err
+	}
+	refreshToken, err := utils.RandomToken(32)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	doc := models.RefreshToken{
+		UserID:        user.ID,
+		FamilyID:      primitive.NewObjectID(),
+		TokenHash:     utils.HashToken(refreshToken),
+		DeviceID:      deviceID,
+		UserAgent:     c.Request.UserAgent(),
+		IP:            c.ClientIP(),
+		AccessTokenID: claims.ID,
+		AccessExpires: claims.ExpiresAt.Time,
+		MFA:           mfa,
+		ExpiresAt:     now.Add(refreshTokenTTL()),
+		CreatedAt:     now,
+	}
+	if family != nil {
+		// Срок сессии не продлевается: замененные токены хранятся до конца сессии для обнаружения повторов
+		doc.FamilyID = family.FamilyID
+		doc.DeviceID = family.DeviceID
+		doc.ExpiresAt = family.ExpiresAt
+	}
+
+	if _, err := config.GetCollection("refresh_tokens").InsertOne(ctx, doc); err != nil {
+		return nil, err
+	}
+
+	return gin.H{
+		"token":            accessToken,
+		"expiresIn":        int(utils.AccessTokenTTL().Seconds()),
+		"refreshToken":     refreshToken,
+		"refreshExpiresAt": doc.ExpiresAt,
+	}, nil
+}
+
+// revokeSessions - отозвать все семейства refresh токенов, подходящие под фильтр,
+// вместе с еще действующими access токенами этих семейств. Возвращает число сессий.
+func revokeSessions(ctx context.Context, filter bson.M) (int, error) {
+	refreshTokens := config.GetCollection("refresh_tokens")
+
+	families, err := refreshTokens.Distinct(ctx, "familyId", filter)
+	if err != nil {
+		return 0, err
+	}
+	if len(families) == 0 {
+		return 0, nil
+	}
+
+	// Access токены живут до JWT_EXPIRATION - добавить в denylist
+	now := time.Now()
+	cursor, err := refreshTokens.Find(ctx,
+		bson.M{"familyId": bson.M{"$in": families}, "accessExpires": bson.M{"$gt": now}},
+		options.Find().SetProjection(bson.M{"userId": 1, "accessTokenId": 1, "accessExpires": 1}),
+	)
+	if err != nil {
+		return 0, err
+	}
+	var active []models.RefreshToken
+	if err := cursor.All(ctx, &active); err != nil {
+		return 0, err
+	}
+	for _, token := range active {
+		if err := utils.RevokeToken(ctx, token.AccessTokenID, token.UserID, token.AccessExpires); err != nil {
+			return 0, err
+		}
+	}
+
+	_, err = refreshTokens.UpdateMany(ctx,
+		bson.M{"familyId": bson.M{"$in": families}, "revokedAt": bson.M{"$exists": false}},
+		bson.M{"$set": bson.M{"revokedAt": now}},
+	)
+	if err != nil {
+		return 0, err
+	}
+	return len(families), nil
+}
+
+// RefreshToken - обменять refresh токен на новую пару (refresh токен одноразовый)
+// POST /api/auth/refresh
+func RefreshToken(c *gin.Context) {
+	var req RefreshTokenRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ErrorResponse(c, 400, "Invalid request data: "+err.Error())
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	refreshTokens := config.GetCollection("refresh_tokens")
+	tokenHash := utils.HashToken(req.RefreshToken)
+	now := time.Now()
+
+	// Атомарно пометить токен использованным: из двух одновременных запросов пройдет один
+	var current models.RefreshToken
+	err := refreshTokens.FindOneAndUpdate(ctx,
+		bson.M{
+			"tokenHash": tokenHash,
+			"usedAt":    bson.M{"$exists": false},
+			"revokedAt": bson.M{"$exists": false},
+			"expiresAt": bson.M{"$gt": now},
+		},
+		bson.M{"$set": bson.M{"usedAt": now}},
+	).Decode(&current)
+
+	if err == mongo.ErrNoDocuments {
+		var stored models.RefreshToken
+		if err := refreshTokens.FindOne(ctx, bson.M{"tokenHash": tokenHash}).Decode(&stored); err != nil {
+			utils.ErrorResponse(c, 401, "Invalid refresh token")
+			return
+		}
+
+		switch {
+		case stored.UsedAt != nil && stored.RevokedAt == nil && now.Sub(*stored.UsedAt) < refreshReuseGrace:
+			// Клиент повторяет запрос с новым токеном из хранилища
+			utils.ErrorResponse(c, 409, "Refresh token was just rotated. Retry with the latest token")
+		case stored.UsedAt != nil:
+			// Замененный токен предъявлен повторно - он мог быть украден: закрыть всю сессию
+			if _, err := revokeSessions(ctx, bson.M{"familyId": stored.FamilyID}); err != nil {
+				fmt.Printf("Warning: failed to revoke token family %s: %v\n", stored.FamilyID.Hex(), err)
+			}
+			fmt.Printf("Warning: refresh token reuse detected for user %s (family %s)\n", stored.UserID.Hex(), stored.FamilyID.Hex())
+			utils.ErrorResponse(c, 401, "Refresh token reuse detected. Please log in again")
+		case stored.RevokedAt != nil:
+			utils.ErrorResponse(c, 401, "Refresh token has been revoked")
+		default:
+			utils.ErrorResponse(c, 401, "Refresh token expired")
+		}
+		return
+	}
+	if err != nil {
+		utils.ErrorResponse(c, 500, "Failed to refresh token")
+		return
+	}
+
+	// Роль и email берутся из базы: могли измениться с момента входа
+	var user models.User
+	if err := config.GetCollection("users").FindOne(ctx, bson.M{"_id": current.UserID}).Decode(&user); err != nil {
+		utils.ErrorResponse(c, 401, "User not found")
+		return
+	}
+
+	tokens, err := issueTokens(ctx, c, user, current.DeviceID, false, &current)
+	if err != nil {
+		utils.ErrorResponse(c, 500, "Failed to generate token")
+		return
+	}
+
+	utils.SuccessResponse(c, 200, tokens)
+}
+
+// Logout - выйти: отозвать текущий access токен и сессию (или все сессии с allDevices)
+// POST /api/auth/logout
+func Logout(c *gin.Context) {
+	var req LogoutRequest
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			utils.ErrorResponse(c, 400, "Invalid request data: "+err.Error())
+			return
+		}
+	}
+
+	userID, _ := c.Get("userId")
+	userObjectID, _ := primitive.ObjectIDFromHex(userID.(string))
+	tokenID := c.GetString("tokenId")
+	tokenExpiresAt := c.GetTime("tokenExpiresAt")
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	// Текущий access токен - сразу в denylist
+	if err := utils.RevokeToken(ctx, tokenID, userObjectID, tokenExpiresAt); err != nil {
+		utils.ErrorResponse(c, 500, "Failed to log out")
+		return
+	}
+
+	// Сессия: по refresh токену, иначе та, что выдала текущий access токен
+	filter := bson.M{"userId": userObjectID, "accessTokenId": tokenID}
+	switch {
+	case req.AllDevices:
+		filter = bson.M{"userId": userObjectID}
+	case req.RefreshToken != "":
+		filter = bson.M{"userId": userObjectID, "tokenHash": utils.HashToken(req.RefreshToken)}
+	case tokenID == "":
+		filter = nil // токен выдан до появления сессий
+	}
+
+	revoked := 0
+	if filter != nil {
+		var err error
+		revoked, err = revokeSessions(ctx, filter)
+		if err != nil {
+			utils.ErrorResponse(c, 500, "Failed to log out")
+			return
+		}
+	}
+
+	utils.SuccessWithMessage(c, 200, "Logged out successfully", gin.H{
+		"revokedSessions": revoked,
+	})
+}
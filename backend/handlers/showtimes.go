@@ -2,9 +2,12 @@ package handlers
 
 import (
 	"cinema-booking/config"
+	"cinema-booking/i18n"
 	"cinema-booking/models"
 	"cinema-booking/utils"
 	"context"
+	"errors"
+	"fmt"
 	"strconv"
 	"time"
 
@@ -160,6 +163,11 @@ func GetShowtimes(c *gin.Context) {
 		return
 	}
 
+	lang := i18n.FromContext(c)
+	for i := range showtimes {
+		localizeShowtime(lang, &showtimes[i])
+	}
+
 	// === ПОДСЧЕТ ОБЩЕГО КОЛИЧЕСТВА ===
 
 	total, err := showtimesCollection.CountDocuments(ctx, filter)
@@ -225,6 +233,10 @@ func GetShowtimes(c *gin.Context) {
 			return
 		}
 
+		for _, showtime := range detailedShowtimes {
+			localizeShowtimeDoc(lang, showtime)
+		}
+
 		utils.PaginatedResponse(c, detailedShowtimes, page, limit, int(total))
 		return
 	}
@@ -313,6 +325,28 @@ func CreateShowtime(c *gin.Context) {
 	showtime.BookedSeats = []models.BookedSeat{} // Пустой массив
 	showtime.CreatedAt = time.Now()
 
+	// Проверка пересечений и вставка под блокировкой зала (как в импорте расписания)
+	release, err := lockHalls(ctx, []primitive.ObjectID{showtime.HallID})
+	if errors.Is(err, ErrHallBusy) {
+		utils.ErrorResponse(c, 409, "Hall schedule is being changed by another request, try again")
+		return
+	}
+	if err != nil {
+		utils.ErrorResponse(c, 500, "Failed to create showtime")
+		return
+	}
+	defer release()
+
+	conflicts, err := countOverlappingShowtimes(ctx, showtime.HallID, showtime.StartTime, showtime.EndTime)
+	if err != nil {
+		utils.ErrorResponse(c, 500, "Failed to create showtime")
+		return
+	}
+	if conflicts > 0 {
+		utils.ErrorResponse(c, 409, "Showtime overlaps an existing showtime in this hall")
+		return
+	}
+
 	// Сохранить в БД
 	showtimesCollection := config.GetCollection("showtimes")
 
@@ -324,6 +358,11 @@ func CreateShowtime(c *gin.Context) {
 
 	showtime.ID = result.InsertedID.(primitive.ObjectID)
 
+	// Уведомить тех, у кого фильм в watchlist (первые сеансы в их городе)
+	if err := notifyWatchlistOnSale(ctx, []models.Showtime{showtime}); err != nil {
+		fmt.Printf("Warning: failed to enqueue watchlist notifications: %v\n", err)
+	}
+
 	utils.SuccessWithMessage(c, 201, "Showtime created successfully", showtime)
 }
 
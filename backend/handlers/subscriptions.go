@@ -0,0 +1,633 @@
+package handlers
+
+import (
+	"cinema-booking/config"
+	"cinema-booking/models"
+	"cinema-booking/utils"
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// Расчетный период абонемента по умолчанию
+const defaultSubscriptionPeriodDays = 30
+
+// validateSubscriptionPlan - проверить поля тарифа, вернуть текст ошибки
+func validateSubscriptionPlan(plan *models.SubscriptionPlan) string {
+	plan.Name = utils.SanitizeString(plan.Name)
+	plan.Description = utils.SanitizeString(plan.Description)
+
+	if plan.Name == "" {
+		return "Plan name is required"
+	}
+	if plan.Price <= 0 {
+		return "price must be greater than 0"
+	}
+	if plan.FilmsPerPeriod <= 0 {
+		return "filmsPerPeriod must be greater than 0"
+	}
+	if plan.PeriodDays == 0 {
+		plan.PeriodDays = defaultSubscriptionPeriodDays
+	}
+	if plan.PeriodDays < 1 || plan.PeriodDays > 366 {
+		return "periodDays must be between 1 and 366"
+	}
+	return ""
+}
+
+// containsFold - значение есть в списке (без учета регистра)
+func containsFold(values []string, value string) bool {
+	for _, v := range values {
+		if strings.EqualFold(v, value) {
+			return true
+		}
+	}
+	return false
+}
+
+// passRestrictionError - проверить, что сеанс подходит под ограничения тарифа
+func passRestrictionError(plan *models.SubscriptionPlan, pc *pricingContext) error {
+	if len(plan.AllowedFormats) > 0 && !containsFold(plan.AllowedFormats, pc.Showtime.Format) {
+		return fmt.Errorf("Your pass is valid only for formats: %s", strings.Join(plan.AllowedFormats, ", "))
+	}
+	if len(plan.AllowedHallTypes) > 0 {
+		hallType := ""
+		if pc.Hall != nil {
+			hallType = pc.Hall.Type
+		}
+		if !containsFold(plan.AllowedHallTypes, hallType) {
+			return fmt.Errorf("Your pass is valid only for halls: %s", strings.Join(plan.AllowedHallTypes, ", "))
+		}
+	}
+	return nil
+}
+
+// chargeSubscription - атомарно списать стоимость периода с кошелька
+func chargeSubscription(ctx context.Context, userID primitive.ObjectID, plan *models.SubscriptionPlan) error {
+	result, err := config.GetCollection("users").UpdateOne(ctx,
+		bson.M{"_id": userID, "wallet.balance": bson.M{"$gte": plan.Price}},
+		bson.M{
+			"$inc": bson.M{"wallet.balance": -plan.Price},
+			"$set": bson.M{"updatedAt": time.Now()},
+		},
+	)
+	if err != nil {
+		return errors.New("Failed to charge wallet")
+	}
+	if result.ModifiedCount == 0 {
+		return fmt.Errorf("Insufficient wallet balance. Required: %.2f KZT", plan.Price)
+	}
+	return nil
+}
+
+// refundSubscriptionCharge - вернуть списание на кошелек (если период не удалось оформить)
+func refundSubscriptionCharge(ctx context.Context, userID primitive.ObjectID, plan *models.SubscriptionPlan) {
+	_, err := config.GetCollection("users").UpdateOne(ctx,
+		bson.M{"_id": userID},
+		bson.M{"$inc": bson.M{"wallet.balance": plan.Price}},
+	)
+	if err != nil {
+		fmt.Printf("Warning: failed to refund subscription charge: %v\n", err)
+	}
+}
+
+// recordSubscriptionTransaction - запись об оплате периода в истории кошелька
+func recordSubscriptionTransaction(ctx context.Context, sub *models.Subscription, amount float64, description string) {
+	transaction := models.Transaction{
+		UserID:         sub.UserID,
+		Type:           "subscription",
+		Amount:         -amount,
+		Status:         "completed",
+		Description:    description,
+		SubscriptionID: sub.ID,
+		CreatedAt:      time.Now(),
+	}
+	if _, err := config.GetCollection("transactions").InsertOne(ctx, transaction); err != nil {
+		fmt.Printf("Warning: failed to create transaction record: %v\n", err)
+	}
+}
+
+// renewSubscription - продлить истекший абонемент с оплатой из кошелька.
+// Если продление невозможно (автопродление выключено, тариф снят, не хватает денег),
+// абонемент переводится в "expired".
+func renewSubscription(ctx context.Context, sub *models.Subscription) error {
+	subscriptionsCollection := config.GetCollection("subscriptions")
+
+	expire := func(reason string) error {
+		if _, err := expireSubscription(ctx, sub); err != nil {
+			return err
+		}
+		return errors.New(reason)
+	}
+
+	if !sub.AutoRenew {
+		return expire("Auto-renewal is disabled")
+	}
+
+	var plan models.SubscriptionPlan
+	err := config.GetCollection("subscription_plans").FindOne(ctx, bson.M{"_id": sub.PlanID, "isActive": true}).Decode(&plan)
+	if err != nil {
+		return expire("Subscription plan is no longer available")
+	}
+
+	if err := chargeSubscription(ctx, sub.UserID, &plan); err != nil {
+		return expire(err.Error())
+	}
+
+	// Новый период начинается с конца предыдущего (или сейчас, если абонемент давно не продлевался)
+	now := time.Now()
+	periodStart := sub.PeriodEnd
+	if now.Sub(periodStart) > time.Duration(plan.PeriodDays)*24*time.Hour {
+		periodStart = now
+	}
+	periodEnd := periodStart.AddDate(0, 0, plan.PeriodDays)
+
+	// Условие на periodEnd защищает от двойного продления
+	result, err := subscriptionsCollection.UpdateOne(ctx,
+		bson.M{"_id": sub.ID, "status": "active", "periodEnd": sub.PeriodEnd},
+		bson.M{
+			"$set": bson.M{
+				"planName":     plan.Name,
+				"periodStart":  periodStart,
+				"periodEnd":    periodEnd,
+				"filmsAllowed": plan.FilmsPerPeriod,
+				"filmsUsed":    0,
+				"updatedAt":    now,
+			},
+			"$inc": bson.M{"renewals": 1},
+		},
+	)
+	if err != nil || result.ModifiedCount == 0 {
+		refundSubscriptionCharge(ctx, sub.UserID, &plan)
+		if err != nil {
+			return err
+		}
+		return errors.New("Subscription was already renewed")
+	}
+
+	sub.PlanName = plan.Name
+	sub.PeriodStart = periodStart
+	sub.PeriodEnd = periodEnd
+	sub.FilmsAllowed = plan.FilmsPerPeriod
+	sub.FilmsUsed = 0
+	sub.Renewals++
+	sub.UpdatedAt = now
+
+	recordSubscriptionTransaction(ctx, sub, plan.Price,
+		fmt.Sprintf("%s renewal until %s", plan.Name, periodEnd.Format("2006-01-02")))
+
+	return nil
+}
+
+// expireSubscription - закрыть абонемент с истекшим периодом без продления.
+// Условие на periodEnd не дает закрыть абонемент, который параллельно продлили.
+func expireSubscription(ctx context.Context, sub *models.Subscription) (bool, error) {
+	result, err := config.GetCollection("subscriptions").UpdateOne(ctx,
+		bson.M{"_id": sub.ID, "status": "active", "periodEnd": sub.PeriodEnd},
+		bson.M{"$set": bson.M{"status": "expired", "updatedAt": time.Now()}},
+	)
+	if err != nil {
+		return false, err
+	}
+	if result.ModifiedCount == 0 {
+		return false, nil
+	}
+	sub.Status = "expired"
+	return true, nil
+}
+
+// activeSubscription - действующий абонемент пользователя.
+// Только чтение: истекший период продлевает RenewDueSubscriptions, а не запросы пользователя.
+func activeSubscription(ctx context.Context, userID primitive.ObjectID) (*models.Subscription, error) {
+	var sub models.Subscription
+	err := config.GetCollection("subscriptions").FindOne(ctx,
+		bson.M{"userId": userID, "status": "active"},
+		options.FindOne().SetSort(bson.D{{Key: "periodEnd", Value: -1}}),
+	).Decode(&sub)
+	if err == mongo.ErrNoDocuments {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &sub, nil
+}
+
+// RenewDueSubscriptions - продлить (или закрыть) все абонементы с истекшим периодом (CLI)
+func RenewDueSubscriptions(ctx context.Context) (renewed int, expired int, err error) {
+	cursor, err := config.GetCollection("subscriptions").Find(ctx, bson.M{
+		"status":    "active",
+		"periodEnd": bson.M{"$lte": time.Now()},
+	})
+	if err != nil {
+		return 0, 0, err
+	}
+	defer cursor.Close(ctx)
+
+	var due []models.Subscription
+	if err := cursor.All(ctx, &due); err != nil {
+		return 0, 0, err
+	}
+
+	for i := range due {
+		if err := renewSubscription(ctx, &due[i]); err != nil {
+			if due[i].Status == "expired" {
+				expired++
+			}
+			continue
+		}
+		renewed++
+	}
+
+	return renewed, expired, nil
+}
+
+// validatePassForBooking - найти абонемент и проверить, что он покрывает сеанс
+func validatePassForBooking(ctx context.Context, userID primitive.ObjectID, pc *pricingContext, seatsCount int) (*models.Subscription, error) {
+	if seatsCount != 1 {
+		return nil, errors.New("A pass covers one seat per booking")
+	}
+
+	sub, err := activeSubscription(ctx, userID)
+	if err != nil {
+		return nil, errors.New("Failed to load subscription")
+	}
+	if sub == nil {
+		return nil, errors.New("You have no active pass")
+	}
+
+	if time.Now().After(sub.PeriodEnd) {
+		return nil, errors.New("Your pass period has ended and is awaiting renewal")
+	}
+
+	if sub.FilmsUsed >= sub.FilmsAllowed {
+		return nil, fmt.Errorf("Pass allowance used up until %s", sub.PeriodEnd.Format("2006-01-02"))
+	}
+
+	// Сеанс должен начаться до конца оплаченного периода
+	if pc.Showtime.StartTime.After(sub.PeriodEnd) {
+		return nil, errors.New("Showtime is after the end of your pass period")
+	}
+
+	var plan models.SubscriptionPlan
+	if err := config.GetCollection("subscription_plans").FindOne(ctx, bson.M{"_id": sub.PlanID}).Decode(&plan); err != nil {
+		return nil, errors.New("Subscription plan not found")
+	}
+
+	if err := passRestrictionError(&plan, pc); err != nil {
+		return nil, err
+	}
+
+	return sub, nil
+}
+
+// consumePassAllowance - атомарно использовать один фильм из лимита периода
+func consumePassAllowance(ctx context.Context, sub *models.Subscription) error {
+	result, err := config.GetCollection("subscriptions").UpdateOne(ctx,
+		bson.M{
+			"_id":       sub.ID,
+			"status":    "active",
+			"periodEnd": bson.M{"$gt": time.Now()},
+			"$expr":     bson.M{"$lt": bson.A{"$filmsUsed", "$filmsAllowed"}},
+		},
+		bson.M{
+			"$inc": bson.M{"filmsUsed": 1},
+			"$set": bson.M{"updatedAt": time.Now()},
+		},
+	)
+	if err != nil {
+		return errors.New("Failed to use pass")
+	}
+	if result.ModifiedCount == 0 {
+		return errors.New("Pass allowance used up")
+	}
+	return nil
+}
+
+// returnPassAllowance - вернуть фильм в лимит при отмене брони (только в том же периоде)
+func returnPassAllowance(ctx context.Context, booking models.Booking) {
+	if booking.Payment.Method != "pass" || booking.Payment.SubscriptionID.IsZero() {
+		return
+	}
+
+	_, err := config.GetCollection("subscriptions").UpdateOne(ctx,
+		bson.M{
+			"_id":         booking.Payment.SubscriptionID,
+			"periodStart": bson.M{"$lte": booking.CreatedAt},
+			"filmsUsed":   bson.M{"$gt": 0},
+		},
+		bson.M{
+			"$inc": bson.M{"filmsUsed": -1},
+			"$set": bson.M{"updatedAt": time.Now()},
+		},
+	)
+	if err != nil {
+		fmt.Printf("Warning: failed to return pass allowance: %v\n", err)
+	}
+}
+
+// GetSubscriptionPlans - доступные тарифы абонементов
+func GetSubscriptionPlans(c *gin.Context) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	cursor, err := config.GetCollection("subscription_plans").Find(ctx, bson.M{"isActive": true},
+		options.Find().SetSort(bson.D{{Key: "price", Value: 1}}))
+	if err != nil {
+		utils.ErrorResponse(c, 500, "Failed to fetch subscription plans")
+		return
+	}
+	defer cursor.Close(ctx)
+
+	plans := []models.SubscriptionPlan{}
+	if err = cursor.All(ctx, &plans); err != nil {
+		utils.ErrorResponse(c, 500, "Failed to decode subscription plans")
+		return
+	}
+
+	utils.SuccessResponse(c, 200, plans)
+}
+
+// CreateSubscriptionPlan - создать тариф абонемента (admin only)
+func CreateSubscriptionPlan(c *gin.Context) {
+	var plan models.SubscriptionPlan
+	if err := c.ShouldBindJSON(&plan); err != nil {
+		utils.ErrorResponse(c, 400, "Invalid request data: "+err.Error())
+		return
+	}
+
+	if msg := validateSubscriptionPlan(&plan); msg != "" {
+		utils.ErrorResponse(c, 400, msg)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	plan.ID = primitive.NilObjectID
+	plan.IsActive = true
+	plan.CreatedAt = time.Now()
+	plan.UpdatedAt = time.Now()
+
+	result, err := config.GetCollection("subscription_plans").InsertOne(ctx, plan)
+	if err != nil {
+		utils.ErrorResponse(c, 500, "Failed to create subscription plan")
+		return
+	}
+
+	plan.ID = result.InsertedID.(primitive.ObjectID)
+
+	utils.SuccessWithMessage(c, 201, "Subscription plan created successfully", plan)
+}
+
+// UpdateSubscriptionPlanRequest - изменение тарифа; isActive не передан - тариф остается в продаже как был
+type UpdateSubscriptionPlanRequest struct {
+	models.SubscriptionPlan
+	IsActive *bool `json:"isActive"`
+}
+
+// UpdateSubscriptionPlan - изменить тариф (admin only)
+// Изменения применяются к подписчикам со следующего периода.
+func UpdateSubscriptionPlan(c *gin.Context) {
+	planID, err := primitive.ObjectIDFromHex(c.Param("id"))
+	if err != nil {
+		utils.ErrorResponse(c, 400, "Invalid subscription plan ID")
+		return
+	}
+
+	var req UpdateSubscriptionPlanRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ErrorResponse(c, 400, "Invalid request data: "+err.Error())
+		return
+	}
+	plan := req.SubscriptionPlan
+
+	if msg := validateSubscriptionPlan(&plan); msg != "" {
+		utils.ErrorResponse(c, 400, msg)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	plansCollection := config.GetCollection("subscription_plans")
+
+	var existing models.SubscriptionPlan
+	if err := plansCollection.FindOne(ctx, bson.M{"_id": planID}).Decode(&existing); err != nil {
+		utils.ErrorResponse(c, 404, "Subscription plan not found")
+		return
+	}
+
+	// Тариф снимается с продажи только явным isActive: false (или DELETE)
+	plan.IsActive = existing.IsActive
+	if req.IsActive != nil {
+		plan.IsActive = *req.IsActive
+	}
+
+	plan.ID = planID
+	plan.CreatedAt = existing.CreatedAt
+	plan.UpdatedAt = time.Now()
+
+	if _, err := plansCollection.ReplaceOne(ctx, bson.M{"_id": planID}, plan); err != nil {
+		utils.ErrorResponse(c, 500, "Failed to update subscription plan")
+		return
+	}
+
+	utils.SuccessWithMessage(c, 200, "Subscription plan updated successfully", plan)
+}
+
+// DeleteSubscriptionPlan - снять тариф с продажи (admin only)
+// Действующие абонементы работают до конца периода и не продлеваются.
+func DeleteSubscriptionPlan(c *gin.Context) {
+	planIDStr := c.Param("id")
+	planID, err := primitive.ObjectIDFromHex(planIDStr)
+	if err != nil {
+		utils.ErrorResponse(c, 400, "Invalid subscription plan ID")
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	result, err := config.GetCollection("subscription_plans").UpdateOne(ctx,
+		bson.M{"_id": planID},
+		bson.M{"$set": bson.M{"isActive": false, "updatedAt": time.Now()}},
+	)
+	if err != nil {
+		utils.ErrorResponse(c, 500, "Failed to delete subscription plan")
+		return
+	}
+
+	if result.MatchedCount == 0 {
+		utils.ErrorResponse(c, 404, "Subscription plan not found")
+		return
+	}
+
+	utils.SuccessWithMessage(c, 200, "Subscription plan deactivated successfully", gin.H{
+		"planId":  planIDStr,
+		"deleted": true,
+	})
+}
+
+// SubscribeRequest - покупка абонемента
+type SubscribeRequest struct {
+	PlanID    string `json:"planId" binding:"required"`
+	AutoRenew *bool  `json:"autoRenew"` // по умолчанию true
+}
+
+// Subscribe - купить абонемент с оплатой из кошелька
+func Subscribe(c *gin.Context) {
+	userID, _ := c.Get("userId")
+	userObjectID, _ := primitive.ObjectIDFromHex(userID.(string))
+
+	var req SubscribeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ErrorResponse(c, 400, "Invalid request data: "+err.Error())
+		return
+	}
+
+	planID, err := primitive.ObjectIDFromHex(req.PlanID)
+	if err != nil {
+		utils.ErrorResponse(c, 400, "Invalid subscription plan ID")
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	var plan models.SubscriptionPlan
+	err = config.GetCollection("subscription_plans").FindOne(ctx, bson.M{"_id": planID, "isActive": true}).Decode(&plan)
+	if err != nil {
+		utils.ErrorResponse(c, 404, "Subscription plan not found")
+		return
+	}
+
+	// Один действующий абонемент на пользователя
+	current, err := activeSubscription(ctx, userObjectID)
+	if err != nil {
+		utils.ErrorResponse(c, 500, "Failed to check subscriptions")
+		return
+	}
+	if current != nil {
+		if !time.Now().After(current.PeriodEnd) {
+			utils.ErrorResponse(c, 409, fmt.Sprintf("You already have an active pass until %s", current.PeriodEnd.Format("2006-01-02")))
+			return
+		}
+		// Период истек, но еще не продлен: новая покупка заменяет продление старого абонемента
+		closed, err := expireSubscription(ctx, current)
+		if err != nil {
+			utils.ErrorResponse(c, 500, "Failed to check subscriptions")
+			return
+		}
+		if !closed {
+			utils.ErrorResponse(c, 409, "Your pass is being renewed, try again")
+			return
+		}
+	}
+
+	if err := chargeSubscription(ctx, userObjectID, &plan); err != nil {
+		utils.ErrorResponse(c, 400, err.Error())
+		return
+	}
+
+	now := time.Now()
+	sub := models.Subscription{
+		UserID:       userObjectID,
+		PlanID:       plan.ID,
+		PlanName:     plan.Name,
+		Status:       "active",
+		AutoRenew:    req.AutoRenew == nil || *req.AutoRenew,
+		PeriodStart:  now,
+		PeriodEnd:    now.AddDate(0, 0, plan.PeriodDays),
+		FilmsAllowed: plan.FilmsPerPeriod,
+		CreatedAt:    now,
+		UpdatedAt:    now,
+	}
+
+	result, err := config.GetCollection("subscriptions").InsertOne(ctx, sub)
+	if err != nil {
+		refundSubscriptionCharge(ctx, userObjectID, &plan)
+		// Частичный уникальный индекс {userId} для status "active" - параллельная покупка
+		if mongo.IsDuplicateKeyError(err) {
+			utils.ErrorResponse(c, 409, "You already have an active pass")
+			return
+		}
+		utils.ErrorResponse(c, 500, "Failed to create subscription")
+		return
+	}
+
+	sub.ID = result.InsertedID.(primitive.ObjectID)
+
+	recordSubscriptionTransaction(ctx, &sub, plan.Price,
+		fmt.Sprintf("%s until %s", plan.Name, sub.PeriodEnd.Format("2006-01-02")))
+
+	utils.SuccessWithMessage(c, 201, "Pass purchased successfully", sub)
+}
+
+// GetMySubscription - мой абонемент и остаток фильмов в периоде
+func GetMySubscription(c *gin.Context) {
+	userID, _ := c.Get("userId")
+	userObjectID, _ := primitive.ObjectIDFromHex(userID.(string))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	sub, err := activeSubscription(ctx, userObjectID)
+	if err != nil {
+		utils.ErrorResponse(c, 500, "Failed to fetch subscription")
+		return
+	}
+	if sub == nil {
+		utils.ErrorResponse(c, 404, "You have no active pass")
+		return
+	}
+
+	// Истекший период ждет продления фоновой задачей - фильмов в нем уже нет
+	renewalDue := time.Now().After(sub.PeriodEnd)
+	filmsRemaining := sub.FilmsAllowed - sub.FilmsUsed
+	if renewalDue {
+		filmsRemaining = 0
+	}
+
+	utils.SuccessResponse(c, 200, gin.H{
+		"subscription":   sub,
+		"filmsRemaining": filmsRemaining,
+		"renewalDue":     renewalDue,
+	})
+}
+
+// CancelSubscription - отключить автопродление (абонемент действует до конца периода)
+func CancelSubscription(c *gin.Context) {
+	userID, _ := c.Get("userId")
+	userObjectID, _ := primitive.ObjectIDFromHex(userID.(string))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	now := time.Now()
+	var sub models.Subscription
+	err := config.GetCollection("subscriptions").FindOneAndUpdate(ctx,
+		bson.M{"userId": userObjectID, "status": "active", "autoRenew": true},
+		bson.M{"$set": bson.M{"autoRenew": false, "cancelledAt": now, "updatedAt": now}},
+		options.FindOneAndUpdate().SetReturnDocument(options.After),
+	).Decode(&sub)
+	if err == mongo.ErrNoDocuments {
+		utils.ErrorResponse(c, 404, "No pass with auto-renewal found")
+		return
+	}
+	if err != nil {
+		utils.ErrorResponse(c, 500, "Failed to cancel subscription")
+		return
+	}
+
+	utils.SuccessWithMessage(c, 200, "Auto-renewal cancelled. The pass stays valid until the end of the period", sub)
+}
@@ -0,0 +1,233 @@
+package handlers
+
+import (
+	"cinema-booking/config"
+	"cinema-booking/models"
+	"cinema-booking/utils"
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Категория по умолчанию (полный билет)
+const defaultTicketCategory = "adult"
+
+// Категории билетов, если кинотеатр не настроил свои
+var defaultTicketCategories = []models.TicketCategory{
+	{Code: "adult", Name: "Adult", DiscountPercent: 0, IsActive: true},
+	{Code: "child", Name: "Child", DiscountPercent: 30, RequiresVerification: true, MaxAge: 11, IsActive: true},
+	{Code: "student", Name: "Student", DiscountPercent: 20, RequiresVerification: true, IsActive: true},
+	{Code: "senior", Name: "Senior", DiscountPercent: 25, RequiresVerification: true, IsActive: true},
+	{Code: "pensioner", Name: "Pensioner", DiscountPercent: 30, RequiresVerification: true, IsActive: true},
+}
+
+// ticketCategoriesFor - категории билетов кинотеатра (или категории по умолчанию)
+func ticketCategoriesFor(cinema *models.Cinema) []models.TicketCategory {
+	if cinema != nil && len(cinema.TicketCategories) > 0 {
+		return cinema.TicketCategories
+	}
+	return defaultTicketCategories
+}
+
+// ticketCategory - найти категорию и проверить, что она допустима для фильма
+func (pc *pricingContext) ticketCategory(code string) (*models.TicketCategory, error) {
+	if code == "" {
+		code = defaultTicketCategory
+	}
+
+	for _, category := range ticketCategoriesFor(pc.Cinema) {
+		if category.Code != code {
+			continue
+		}
+		if !category.IsActive {
+			return nil, fmt.Errorf("Ticket category %q is not available at this cinema", code)
+		}
+		// Детские билеты запрещены на фильмы с возрастным ограничением выше возраста категории
+		if category.MaxAge > 0 && pc.Movie != nil && pc.Movie.AgeRestriction > category.MaxAge {
+			return nil, fmt.Errorf("%s tickets are not allowed for this movie (%d+)", category.Name, pc.Movie.AgeRestriction)
+		}
+		return &category, nil
+	}
+
+	// "adult" доступен всегда, даже если кинотеатр не перечислил его явно
+	if code == defaultTicketCategory {
+		return &models.TicketCategory{Code: defaultTicketCategory, Name: "Adult", IsActive: true}, nil
+	}
+
+	return nil, fmt.Errorf("Unknown ticket category %q", code)
+}
+
+// categoryPrice - цена места с учетом скидки категории
+func categoryPrice(price float64, category *models.TicketCategory) (float64, float64) {
+	discount := roundMoney(price * category.DiscountPercent / 100)
+	return roundMoney(price - discount), discount
+}
+
+// UpdateTicketCategoriesRequest - настройка категорий билетов кинотеатра
+type UpdateTicketCategoriesRequest struct {
+	Categories []models.TicketCategory `json:"categories" binding:"required"`
+}
+
+// UpdateTicketCategories - настроить категории и скидки кинотеатра (admin only)
+func UpdateTicketCategories(c *gin.Context) {
+	cinemaID, err := primitive.ObjectIDFromHex(c.Param("id"))
+	if err != nil {
+		utils.ErrorResponse(c, 400, "Invalid cinema ID")
+		return
+	}
+
+	var req UpdateTicketCategoriesRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ErrorResponse(c, 400, "Invalid request data: "+err.Error())
+		return
+	}
+
+	// Валидация
+	seen := make(map[string]bool)
+	for i := range req.Categories {
+		category := &req.Categories[i]
+		category.Code = strings.ToLower(utils.SanitizeString(category.Code))
+		if category.Code == "" {
+			utils.ErrorResponse(c, 400, "Category code is required")
+			return
+		}
+		if seen[category.Code] {
+			utils.ErrorResponse(c, 400, fmt.Sprintf("Duplicate category %q", category.Code))
+			return
+		}
+		seen[category.Code] = true
+
+		if category.DiscountPercent < 0 || category.DiscountPercent > 100 {
+			utils.ErrorResponse(c, 400, "discountPercent must be between 0 and 100")
+			return
+		}
+		if category.MaxAge < 0 {
+			utils.ErrorResponse(c, 400, "maxAge must not be negative")
+			return
+		}
+		if category.Name == "" {
+			category.Name = category.Code
+		}
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	cinemasCollection := config.GetCollection("cinemas")
+
+	result, err := cinemasCollection.UpdateOne(ctx,
+		bson.M{"_id": cinemaID},
+		bson.M{"$set": bson.M{"ticketCategories": req.Categories}},
+	)
+	if err != nil {
+		utils.ErrorResponse(c, 500, "Failed to update ticket categories")
+		return
+	}
+
+	if result.MatchedCount == 0 {
+		utils.ErrorResponse(c, 404, "Cinema not found")
+		return
+	}
+
+	utils.SuccessWithMessage(c, 200, "Ticket categories updated successfully", gin.H{
+		"cinemaId":   cinemaID,
+		"categories": ticketCategoriesFor(&models.Cinema{TicketCategories: req.Categories}),
+	})
+}
+
+// CheckInRequest - места, для которых контролер проверил документы
+type CheckInRequest struct {
+	VerifiedSeats []string `json:"verifiedSeats"` // ["A-5", "A-6"]
+}
+
+// CheckInBooking - пропустить зрителей по брони (admin, cinema_manager)
+// Льготные билеты требуют подтверждения документа для каждого места.
+func CheckInBooking(c *gin.Context) {
+	bookingID, err := primitive.ObjectIDFromHex(c.Param("id"))
+	if err != nil {
+		utils.ErrorResponse(c, 400, "Invalid booking ID")
+		return
+	}
+
+	var req CheckInRequest
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			utils.ErrorResponse(c, 400, "Invalid request data: "+err.Error())
+			return
+		}
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	bookingsCollection := config.GetCollection("bookings")
+
+	var booking models.Booking
+	if err := bookingsCollection.FindOne(ctx, bson.M{"_id": bookingID}).Decode(&booking); err != nil {
+		utils.ErrorResponse(c, 404, "Booking not found")
+		return
+	}
+
+	if booking.Status != "confirmed" {
+		utils.ErrorResponse(c, 400, "Only confirmed bookings can be checked in")
+		return
+	}
+
+	if booking.CheckedInAt != nil {
+		utils.ErrorResponse(c, 400, "Booking is already checked in")
+		return
+	}
+
+	verified := make(map[string]bool)
+	for _, seat := range req.VerifiedSeats {
+		verified[strings.TrimSpace(seat)] = true
+	}
+
+	// Все льготные места должны быть подтверждены
+	var missing []string
+	for i, seat := range booking.Seats {
+		key := fmt.Sprintf("%s-%d", seat.Row, seat.Number)
+		if !seat.RequiresVerification {
+			continue
+		}
+		if !verified[key] {
+			missing = append(missing, fmt.Sprintf("%s (%s)", key, seat.Category))
+			continue
+		}
+		booking.Seats[i].Verified = true
+	}
+
+	if len(missing) > 0 {
+		utils.ErrorResponse(c, 400, "Document verification required for seats: "+strings.Join(missing, ", "))
+		return
+	}
+
+	now := time.Now()
+	result, err := bookingsCollection.UpdateOne(ctx,
+		bson.M{"_id": bookingID, "checkedInAt": bson.M{"$exists": false}},
+		bson.M{"$set": bson.M{
+			"seats":       booking.Seats,
+			"checkedInAt": now,
+			"updatedAt":   now,
+		}},
+	)
+	if err != nil {
+		utils.ErrorResponse(c, 500, "Failed to check in booking")
+		return
+	}
+	// Параллельный проход по той же брони уже отмечен
+	if result.ModifiedCount == 0 {
+		utils.ErrorResponse(c, 409, "Booking is already checked in")
+		return
+	}
+
+	booking.CheckedInAt = &now
+	booking.UpdatedAt = now
+
+	utils.SuccessWithMessage(c, 200, "Booking checked in successfully", booking)
+}
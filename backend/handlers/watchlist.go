@@ -0,0 +1,361 @@
+package handlers
+
+import (
+	"cinema-booking/config"
+	"cinema-booking/i18n"
+	"cinema-booking/models"
+	"cinema-booking/utils"
+	"context"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// cinemaCities - город каждого кинотеатра
+func cinemaCities(ctx context.Context) (map[primitive.ObjectID]string, error) {
+	cursor, err := config.GetCollection("cinemas").Find(ctx, bson.M{}, options.Find().SetProjection(bson.M{"city": 1}))
+	if err != nil {
+		return nil, err
+	}
+	var cinemas []models.Cinema
+	if err := cursor.All(ctx, &cinemas); err != nil {
+		return nil, err
+	}
+
+	cities := map[primitive.ObjectID]string{}
+	for _, cinema := range cinemas {
+		cities[cinema.ID] = cinema.City
+	}
+	return cities, nil
+}
+
+// cinemaIDsInCity - кинотеатры города (пустой город - все кинотеатры)
+func cinemaIDsInCity(cities map[primitive.ObjectID]string, city string) []primitive.ObjectID {
+	ids := []primitive.ObjectID{}
+	for id, c := range cities {
+		if city == "" || strings.EqualFold(c, city) {
+			ids = append(ids, id)
+		}
+	}
+	return ids
+}
+
+// notifyWatchlistOnSale - после добавления сеансов уведомить пользователей, у которых фильм
+// в watchlist, если это первые сеансы в их городе (без города - первые сеансы вообще).
+// Каждый пользователь уведомляется о фильме один раз (notifiedAt): в приложении и письмом.
+func notifyWatchlistOnSale(ctx context.Context, showtimes []models.Showtime) error {
+	if len(showtimes) == 0 {
+		return nil
+	}
+
+	cities, err := cinemaCities(ctx)
+	if err != nil {
+		return err
+	}
+
+	// Города новых сеансов по фильмам
+	movieCities := map[primitive.ObjectID]map[string]bool{}
+	for _, showtime := range showtimes {
+		if movieCities[showtime.MovieID] == nil {
+			movieCities[showtime.MovieID] = map[string]bool{}
+		}
+		movieCities[showtime.MovieID][strings.ToLower(cities[showtime.CinemaID])] = true
+	}
+
+	watchlistCollection := config.GetCollection("watchlist")
+	queued := false
+	for movieID, newCities := range movieCities {
+		cursor, err := watchlistCollection.Find(ctx, bson.M{
+			"movieId":    movieID,
+			"notifiedAt": bson.M{"$exists": false},
+		})
+		if err != nil {
+			return err
+		}
+		var items []models.WatchlistItem
+		if err := cursor.All(ctx, &items); err != nil {
+			return err
+		}
+		if len(items) == 0 {
+			continue
+		}
+
+		userIDs := make([]primitive.ObjectID, len(items))
+		for i, item := range items {
+			userIDs[i] = item.UserID
+		}
+		cursor, err = config.GetCollection("users").Find(ctx,
+			bson.M{"_id": bson.M{"$in": userIDs}},
+			options.Find().SetProjection(bson.M{"city": 1}),
+		)
+		if err != nil {
+			return err
+		}
+		var users []models.User
+		if err := cursor.All(ctx, &users); err != nil {
+			return err
+		}
+
+		notifications := []models.Notification{}
+		notifiedIDs := []primitive.ObjectID{}
+		for _, user := range users {
+			if user.City != "" && !newCities[strings.ToLower(user.City)] {
+				continue
+			}
+			notifications = append(notifications, models.Notification{
+				UserID:  user.ID,
+				Type:    models.NotificationTicketsOnSale,
+				MovieID: movieID,
+				City:    user.City,
+			})
+			notifiedIDs = append(notifiedIDs, user.ID)
+		}
+		if len(notifications) == 0 {
+			continue
+		}
+
+		if err := enqueueNotifications(ctx, notifications); err != nil {
+			return err
+		}
+		if _, err := watchlistCollection.UpdateMany(ctx,
+			bson.M{"movieId": movieID, "userId": bson.M{"$in": notifiedIDs}},
+			bson.M{"$set": bson.M{"notifiedAt": time.Now()}},
+		); err != nil {
+			return err
+		}
+		queued = true
+	}
+
+	// Письма уходят в фоне; неотправленные остаются "pending" для send-notifications
+	if queued {
+		sendPendingNotificationsAsync()
+	}
+	return nil
+}
+
+// AddToWatchlist - добавить фильм в "Хочу посмотреть"
+// POST /api/watchlist/:movieId
+func AddToWatchlist(c *gin.Context) {
+	userID, _ := c.Get("userId")
+	userObjectID, _ := primitive.ObjectIDFromHex(userID.(string))
+
+	movieID, err := primitive.ObjectIDFromHex(c.Param("movieId"))
+	if err != nil {
+		utils.ErrorResponse(c, 400, "Invalid movie ID")
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	// Будущие релизы еще не активны (isActive=false), поэтому отсекаются только удаленные фильмы
+	if err := config.GetCollection("movies").FindOne(ctx, bson.M{"_id": movieID, "deletedAt": bson.M{"$exists": false}}).Err(); err != nil {
+		utils.ErrorResponse(c, 404, "Movie not found")
+		return
+	}
+
+	var user models.User
+	if err := config.GetCollection("users").FindOne(ctx, bson.M{"_id": userObjectID}).Decode(&user); err != nil {
+		utils.ErrorResponse(c, 404, "User not found")
+		return
+	}
+
+	now := time.Now()
+	item := models.WatchlistItem{
+		UserID:    userObjectID,
+		MovieID:   movieID,
+		CreatedAt: now,
+	}
+
+	// Если сеансы в городе пользователя уже есть, уведомлять о начале продаж не нужно
+	cities, err := cinemaCities(ctx)
+	if err != nil {
+		utils.ErrorResponse(c, 500, "Failed to fetch cinemas")
+		return
+	}
+	onSale, err := config.GetCollection("showtimes").CountDocuments(ctx, bson.M{
+		"movieId":   movieID,
+		"cinemaId":  bson.M{"$in": cinemaIDsInCity(cities, user.City)},
+		"startTime": bson.M{"$gt": now},
+	}, options.Count().SetLimit(1))
+	if err != nil {
+		utils.ErrorResponse(c, 500, "Failed to fetch showtimes")
+		return
+	}
+	if onSale > 0 {
+		item.NotifiedAt = &now
+	}
+
+	result, err := config.GetCollection("watchlist").UpdateOne(ctx,
+		bson.M{"userId": userObjectID, "movieId": movieID},
+		bson.M{"$setOnInsert": item},
+		options.Update().SetUpsert(true),
+	)
+	if err != nil {
+		utils.ErrorResponse(c, 500, "Failed to update watchlist")
+		return
+	}
+
+	if result.UpsertedCount == 0 {
+		utils.SuccessWithMessage(c, 200, "Movie is already in watchlist", gin.H{"movieId": movieID})
+		return
+	}
+
+	utils.SuccessWithMessage(c, 201, "Movie added to watchlist", gin.H{
+		"movieId": movieID,
+		"onSale":  onSale > 0,
+	})
+}
+
+// RemoveFromWatchlist - убрать фильм из "Хочу посмотреть"
+// DELETE /api/watchlist/:movieId
+func RemoveFromWatchlist(c *gin.Context) {
+	userID, _ := c.Get("userId")
+	userObjectID, _ := primitive.ObjectIDFromHex(userID.(string))
+
+	movieID, err := primitive.ObjectIDFromHex(c.Param("movieId"))
+	if err != nil {
+		utils.ErrorResponse(c, 400, "Invalid movie ID")
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	result, err := config.GetCollection("watchlist").DeleteOne(ctx, bson.M{"userId": userObjectID, "movieId": movieID})
+	if err != nil {
+		utils.ErrorResponse(c, 500, "Failed to update watchlist")
+		return
+	}
+	if result.DeletedCount == 0 {
+		utils.ErrorResponse(c, 404, "Movie is not in watchlist")
+		return
+	}
+
+	utils.SuccessWithMessage(c, 200, "Movie removed from watchlist", gin.H{"movieId": movieID})
+}
+
+// GetWatchlist - фильмы из "Хочу посмотреть" с ближайшим сеансом (в городе пользователя, если указан)
+// GET /api/watchlist
+func GetWatchlist(c *gin.Context) {
+	userID, _ := c.Get("userId")
+	userObjectID, _ := primitive.ObjectIDFromHex(userID.(string))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	var user models.User
+	if err := config.GetCollection("users").FindOne(ctx, bson.M{"_id": userObjectID}).Decode(&user); err != nil {
+		utils.ErrorResponse(c, 404, "User not found")
+		return
+	}
+
+	cursor, err := config.GetCollection("watchlist").Find(ctx,
+		bson.M{"userId": userObjectID},
+		options.Find().SetSort(bson.D{{Key: "createdAt", Value: -1}}),
+	)
+	if err != nil {
+		utils.ErrorResponse(c, 500, "Failed to fetch watchlist")
+		return
+	}
+	var items []models.WatchlistItem
+	if err := cursor.All(ctx, &items); err != nil {
+		utils.ErrorResponse(c, 500, "Failed to decode watchlist")
+		return
+	}
+
+	movieIDs := make([]primitive.ObjectID, len(items))
+	for i, item := range items {
+		movieIDs[i] = item.MovieID
+	}
+
+	cursor, err = config.GetCollection("movies").Find(ctx, bson.M{"_id": bson.M{"$in": movieIDs}})
+	if err != nil {
+		utils.ErrorResponse(c, 500, "Failed to fetch movies")
+		return
+	}
+	var movieList []models.Movie
+	if err := cursor.All(ctx, &movieList); err != nil {
+		utils.ErrorResponse(c, 500, "Failed to decode movies")
+		return
+	}
+	movies := map[primitive.ObjectID]*models.Movie{}
+	for i := range movieList {
+		movies[movieList[i].ID] = &movieList[i]
+	}
+
+	// Ближайший сеанс каждого фильма
+	cinemas, err := cinemaCities(ctx)
+	if err != nil {
+		utils.ErrorResponse(c, 500, "Failed to fetch cinemas")
+		return
+	}
+	cursor, err = config.GetCollection("showtimes").Aggregate(ctx, bson.A{
+		bson.M{"$match": bson.M{
+			"movieId":   bson.M{"$in": movieIDs},
+			"cinemaId":  bson.M{"$in": cinemaIDsInCity(cinemas, user.City)},
+			"startTime": bson.M{"$gt": time.Now()},
+		}},
+		bson.M{"$sort": bson.M{"startTime": 1}},
+		bson.M{"$group": bson.M{
+			"_id":      "$movieId",
+			"showtime": bson.M{"$first": "$$ROOT"},
+		}},
+	})
+	if err != nil {
+		utils.ErrorResponse(c, 500, "Failed to fetch showtimes")
+		return
+	}
+	var next []struct {
+		MovieID  primitive.ObjectID `bson:"_id"`
+		Showtime models.Showtime    `bson:"showtime"`
+	}
+	if err := cursor.All(ctx, &next); err != nil {
+		utils.ErrorResponse(c, 500, "Failed to decode showtimes")
+		return
+	}
+	nextShowtimes := map[primitive.ObjectID]*models.Showtime{}
+	for i := range next {
+		nextShowtimes[next[i].MovieID] = &next[i].Showtime
+	}
+
+	lang := i18n.FromContext(c)
+	results := []gin.H{}
+	for _, item := range items {
+		movie, ok := movies[item.MovieID]
+		if !ok {
+			continue
+		}
+		localizeMovie(lang, movie)
+
+		var nextShowtime interface{}
+		if showtime, ok := nextShowtimes[item.MovieID]; ok {
+			localizeShowtime(lang, showtime)
+			nextShowtime = gin.H{
+				"id":        showtime.ID,
+				"cinemaId":  showtime.CinemaID,
+				"city":      cinemas[showtime.CinemaID],
+				"startTime": showtime.StartTime,
+				"format":    showtime.Format,
+				"language":  showtime.Language,
+				"basePrice": showtime.BasePrice,
+			}
+		}
+
+		results = append(results, gin.H{
+			"movie":        movie,
+			"addedAt":      item.CreatedAt,
+			"nextShowtime": nextShowtime,
+		})
+	}
+
+	utils.SuccessResponse(c, 200, gin.H{
+		"city":  user.City,
+		"items": results,
+		"total": len(results),
+	})
+}
@@ -0,0 +1,187 @@
+package i18n
+
+import (
+	"cinema-booking/config"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Поддерживаемые языки интерфейса
+const (
+	Kazakh  = "kk"
+	Russian = "ru"
+	English = "en"
+)
+
+// Ключ языка запроса в gin.Context
+const ContextKey = "lang"
+
+var supported = map[string]bool{Kazakh: true, Russian: true, English: true}
+
+// IsSupported - поддерживается ли язык
+func IsSupported(lang string) bool {
+	return supported[lang]
+}
+
+// FallbackOrder - порядок выбора языка, если перевода нет (LANG_FALLBACK, по умолчанию en,ru,kk).
+// Первый язык списка используется, когда клиент не указал предпочтений.
+func FallbackOrder() []string {
+	order := []string{}
+	seen := map[string]bool{}
+	if config.AppConfig != nil {
+		for _, lang := range strings.Split(config.AppConfig.LangFallback, ",") {
+			lang = normalize(lang)
+			if supported[lang] && !seen[lang] {
+				order = append(order, lang)
+				seen[lang] = true
+			}
+		}
+	}
+	// Недостающие языки - в конец списка
+	for _, lang := range []string{English, Russian, Kazakh} {
+		if !seen[lang] {
+			order = append(order, lang)
+		}
+	}
+	return order
+}
+
+// normalize - "ru-RU" -> "ru", "KZ" -> "kk"
+func normalize(tag string) string {
+	tag = strings.ToLower(strings.TrimSpace(tag))
+	if i := strings.IndexAny(tag, "-_"); i >= 0 {
+		tag = tag[:i]
+	}
+	// Часто встречающийся код страны вместо кода языка
+	if tag == "kz" {
+		return Kazakh
+	}
+	return tag
+}
+
+// Negotiate - выбрать язык: ?lang= важнее Accept-Language, иначе первый язык FallbackOrder
+func Negotiate(queryLang, acceptLanguage string) string {
+	if lang := normalize(queryLang); supported[lang] {
+		return lang
+	}
+
+	type weighted struct {
+		lang string
+		q    float64
+	}
+	var candidates []weighted
+	for _, part := range strings.Split(acceptLanguage, ",") {
+		fields := strings.Split(part, ";")
+		lang := normalize(fields[0])
+		if !supported[lang] {
+			continue
+		}
+		q := 1.0
+		for _, param := range fields[1:] {
+			param = strings.TrimSpace(param)
+			if strings.HasPrefix(param, "q=") {
+				if v, err := strconv.ParseFloat(param[2:], 64); err == nil {
+					q = v
+				}
+			}
+		}
+		if q > 0 {
+			candidates = append(candidates, weighted{lang: lang, q: q})
+		}
+	}
+
+	if len(candidates) > 0 {
+		// Стабильная сортировка сохраняет порядок языков с одинаковым q
+		sort.SliceStable(candidates, func(i, j int) bool { return candidates[i].q > candidates[j].q })
+		return candidates[0].lang
+	}
+
+	return FallbackOrder()[0]
+}
+
+// FromContext - язык текущего запроса (выставляется middleware.Localization)
+func FromContext(c *gin.Context) string {
+	if lang := c.GetString(ContextKey); lang != "" {
+		return lang
+	}
+	if c.Request == nil {
+		return FallbackOrder()[0]
+	}
+	return Negotiate(c.Query("lang"), c.GetHeader("Accept-Language"))
+}
+
+// fallbackChain - lang, затем остальные языки в порядке FallbackOrder
+func fallbackChain(lang string) []string {
+	order := []string{lang}
+	for _, l := range FallbackOrder() {
+		if l != lang {
+			order = append(order, l)
+		}
+	}
+	return order
+}
+
+// Pick - значение на нужном языке; пустые переводы пропускаются по FallbackOrder
+func Pick(lang string, values map[string]string) string {
+	for _, l := range fallbackChain(lang) {
+		if v := values[l]; v != "" {
+			return v
+		}
+	}
+	return ""
+}
+
+// T - перевести сообщение (ключ - английский текст). Для сообщений вида
+// "Invalid request data: <детали>" переводится часть до двоеточия.
+func T(lang, message string) string {
+	key, details := message, ""
+	if !hasKey(messages, message) {
+		if i := strings.Index(message, ": "); i > 0 && hasKey(messages, message[:i]) {
+			key, details = message[:i], message[i:]
+		}
+	}
+	return lookup(messages, lang, key) + details
+}
+
+// Term - перевести термин справочника (жанр, язык, формат); неизвестные возвращаются как есть
+func Term(lang, term string) string {
+	return lookup(terms, lang, term)
+}
+
+// Terms - перевести список терминов
+func Terms(lang string, values []string) []string {
+	if values == nil {
+		return nil
+	}
+	result := make([]string, len(values))
+	for i, v := range values {
+		result[i] = Term(lang, v)
+	}
+	return result
+}
+
+// hasKey - есть ли перевод ключа хотя бы на одном языке
+func hasKey(catalog map[string]map[string]string, key string) bool {
+	for _, translations := range catalog {
+		if _, ok := translations[key]; ok {
+			return true
+		}
+	}
+	return false
+}
+
+// lookup - перевод с учетом FallbackOrder (английский текст - сам ключ)
+func lookup(catalog map[string]map[string]string, lang, key string) string {
+	for _, l := range fallbackChain(lang) {
+		if l == English {
+			return key
+		}
+		if translated, ok := catalog[l][key]; ok {
+			return translated
+		}
+	}
+	return key
+}
@@ -0,0 +1,868 @@
+package i18n
+
+// messages - каталог сообщений об ошибках. Ключ - английский текст из utils.ErrorResponse,
+// сообщения без перевода отдаются по FallbackOrder (в итоге - на английском).
+var messages = map[string]map[string]string{
+	Russian: {
+		// Общие
+		"Invalid request data":     "Некорректные данные запроса",
+		"Internal server error":    "Внутренняя ошибка сервера",
+		"No fields to update":      "Нет полей для обновления",
+		"Failed to decode results": "Не удалось обработать результаты",
+
+		// Авторизация
+		"Authorization header is required":                         "Требуется заголовок Authorization",
+		"Invalid authorization header format. Use: Bearer <token>": "Неверный формат заголовка Authorization. Используйте: Bearer <token>",
+		"Invalid or expired token":                                 "Недействительный или просроченный токен",
+		"Unauthorized":                                             "Требуется авторизация",
+		"Forbidden: No role information":                           "Доступ запрещен: роль не определена",
+		"Forbidden: Insufficient permissions":                      "Доступ запрещен: недостаточно прав",
+		"Invalid email or password":                                "Неверный email или пароль",
+		"Invalid email format":                                     "Неверный формат email",
+		"Invalid recipient email format":                           "Неверный формат email получателя",
+		"Invalid phone format. Use: +7XXXXXXXXXX":                  "Неверный формат телефона. Используйте: +7XXXXXXXXXX",
+		"Full name must be at least 2 characters":                  "Имя должно содержать не менее 2 символов",
+		"Password must be at least 6 characters long":              "Пароль должен содержать не менее 6 символов",
+		"Email already registered":                                 "Email уже зарегистрирован",
+		"User not found":                                           "Пользователь не найден",
+		"Invalid user ID":                                          "Некорректный ID пользователя",
+		"Failed to generate token":                                 "Не удалось создать токен",
+		"Failed to create user":                                    "Не удалось создать пользователя",
+		"Failed to update profile":                                 "Не удалось обновить профиль",
+
+		"Failed to hash password":         "Не удалось обработать пароль",
+		"Failed to fetch updated profile": "Не удалось загрузить обновленный профиль",
+		"Failed to fetch updated user":    "Не удалось загрузить обновленные данные пользователя",
+
+		// Фильмы и кинотеатры
+		"Invalid movie ID":        "Некорректный ID фильма",
+		"Movie not found":         "Фильм не найден",
+		"Movie ID is required":    "Требуется ID фильма",
+		"Title is required":       "Требуется название",
+		"Failed to fetch movies":  "Не удалось загрузить фильмы",
+		"Invalid cinema ID":       "Некорректный ID кинотеатра",
+		"Cinema not found":        "Кинотеатр не найден",
+		"Cinema ID is required":   "Требуется ID кинотеатра",
+		"Failed to fetch cinemas": "Не удалось загрузить кинотеатры",
+		"Invalid sortBy. Use: name, rating, or totalReviews": "Некорректный sortBy. Используйте: name, rating или totalReviews",
+		"Hall ID is required":                                "Требуется ID зала",
+		"Hall not found or doesn't belong to this cinema":    "Зал не найден или не принадлежит этому кинотеатру",
+
+		"Failed to count cinemas":  "Не удалось подсчитать кинотеатры",
+		"Failed to count movies":   "Не удалось подсчитать фильмы",
+		"Failed to create movie":   "Не удалось создать фильм",
+		"Failed to update movie":   "Не удалось обновить фильм",
+		"Failed to delete movie":   "Не удалось удалить фильм",
+		"Failed to decode movies":  "Не удалось обработать фильмы",
+		"Failed to decode cinemas": "Не удалось обработать кинотеатры",
+		"Failed to update cinema":  "Не удалось обновить кинотеатр",
+
+		// Сеансы
+		"Invalid showtime ID":                           "Некорректный ID сеанса",
+		"Showtime not found":                            "Сеанс не найден",
+		"Showtime has already started":                  "Сеанс уже начался",
+		"Start time is required":                        "Требуется время начала",
+		"Start time must be in the future":              "Время начала должно быть в будущем",
+		"Cannot delete showtime with existing bookings": "Нельзя удалить сеанс, на который есть бронирования",
+		"Failed to fetch showtimes":                     "Не удалось загрузить сеансы",
+
+		"Showtime overlaps an existing showtime in this hall":          "Сеанс пересекается с другим сеансом в этом зале",
+		"Hall schedule is being changed by another request, try again": "Расписание зала сейчас меняет другой запрос, попробуйте снова",
+
+		"Failed to create showtime":           "Не удалось создать сеанс",
+		"Failed to delete showtime":           "Не удалось удалить сеанс",
+		"Failed to count showtimes":           "Не удалось подсчитать сеансы",
+		"Failed to decode showtimes":          "Не удалось обработать сеансы",
+		"Failed to fetch detailed showtimes":  "Не удалось загрузить подробные сеансы",
+		"Failed to decode detailed showtimes": "Не удалось обработать подробные сеансы",
+
+		// Бронирования и оплата
+		"Invalid booking ID":                                               "Некорректный ID бронирования",
+		"Booking not found":                                                "Бронирование не найдено",
+		"Booking has expired":                                              "Срок бронирования истек",
+		"Booking is cancelled":                                             "Бронирование отменено",
+		"Booking is already cancelled":                                     "Бронирование уже отменено",
+		"Booking is already confirmed":                                     "Бронирование уже подтверждено",
+		"Booking is already checked in":                                    "Проход по бронированию уже отмечен",
+		"Booking was changed, please try again":                            "Бронирование изменилось, попробуйте еще раз",
+		"Only confirmed bookings can be checked in":                        "Пропустить в зал можно только по подтвержденному бронированию",
+		"Cannot cancel booking less than 2 hours before showtime":          "Нельзя отменить бронирование менее чем за 2 часа до сеанса",
+		"Maximum 10 seats per booking":                                     "Не более 10 мест в одном бронировании",
+		"Document verification required for seats":                         "Для мест требуется проверка документа",
+		"Invalid payment method. Use: wallet, card, cash, points, or pass": "Неверный способ оплаты. Используйте: wallet, card, cash, points или pass",
+		"Invalid payment method. Use: wallet or card":                      "Неверный способ оплаты. Используйте: wallet или card",
+		"Insufficient wallet balance":                                      "Недостаточно средств на кошельке",
+		"Amount must be greater than 0":                                    "Сумма должна быть больше 0",
+		"Maximum top-up amount is 1,000,000 KZT":                           "Максимальная сумма пополнения - 1 000 000 KZT",
+		"Failed to create booking":                                         "Не удалось создать бронирование",
+		"Failed to cancel booking":                                         "Не удалось отменить бронирование",
+		"Failed to confirm booking":                                        "Не удалось подтвердить бронирование",
+		"Failed to fetch bookings":                                         "Не удалось загрузить бронирования",
+
+		"Booking is no longer pending":          "Бронирование больше не ожидает оплаты",
+		"Booking status has changed, try again": "Статус бронирования изменился, попробуйте снова",
+		"Failed to decode bookings":             "Не удалось обработать бронирования",
+		"Insufficient wallet balance. Required": "Недостаточно средств на кошельке. Требуется",
+		"Failed to charge wallet":               "Не удалось списать средства с кошелька",
+		"Failed to credit wallet":               "Не удалось зачислить средства на кошелек",
+		"Failed to top up wallet":               "Не удалось пополнить кошелек",
+
+		// Промокоды, подарочные карты, лояльность
+		"Promo code not found":                   "Промокод не найден",
+		"Promo code already exists":              "Промокод уже существует",
+		"Invalid promo code ID":                  "Некорректный ID промокода",
+		"Gift card not found":                    "Подарочная карта не найдена",
+		"Invalid gift card ID":                   "Некорректный ID подарочной карты",
+		"Gift card has already been redeemed":    "Подарочная карта уже активирована",
+		"Message must be at most 500 characters": "Сообщение должно быть не длиннее 500 символов",
+		"loyaltyPoints must not be negative":     "loyaltyPoints не может быть отрицательным",
+
+		"Promo code is not active":                                          "Промокод не активен",
+		"Promo code is not valid yet":                                       "Промокод еще не действует",
+		"Promo code has expired":                                            "Срок действия промокода истек",
+		"Promo code is not valid for this movie":                            "Промокод не действует для этого фильма",
+		"Promo code is not valid at this cinema":                            "Промокод не действует в этом кинотеатре",
+		"Promo code usage limit reached":                                    "Лимит использований промокода исчерпан",
+		"You have already used this promo code the maximum number of times": "Вы уже использовали этот промокод максимальное число раз",
+		"Failed to check promo code usage":                                  "Не удалось проверить использование промокода",
+		"Failed to apply promo code":                                        "Не удалось применить промокод",
+		"Code must be 3-32 characters long":                                 "Код должен содержать от 3 до 32 символов",
+		"Code may contain only letters, digits, '-' and '_'":                "Код может содержать только буквы, цифры, '-' и '_'",
+		"Percentage discount must be between 0 and 100":                     "Процентная скидка должна быть от 0 до 100",
+		"Fixed discount must be greater than 0":                             "Фиксированная скидка должна быть больше 0",
+		"Invalid discount type. Use: percentage or fixed":                   "Некорректный тип скидки. Используйте: percentage или fixed",
+		"Limits must not be negative":                                       "Лимиты не могут быть отрицательными",
+		"validUntil must be after validFrom":                                "validUntil должен быть позже validFrom",
+		"weekdays must be between 0 (Sunday) and 6 (Saturday)":              "weekdays должны быть от 0 (воскресенье) до 6 (суббота)",
+		"Count must be between 1 and 10000":                                 "Количество должно быть от 1 до 10000",
+		"Length must be between 6 and 16":                                   "Длина должна быть от 6 до 16",
+		"Failed to create promo code":                                       "Не удалось создать промокод",
+		"Failed to update promo code":                                       "Не удалось обновить промокод",
+		"Failed to delete promo code":                                       "Не удалось удалить промокод",
+		"Failed to fetch promo codes":                                       "Не удалось загрузить промокоды",
+		"Failed to decode promo codes":                                      "Не удалось обработать промокоды",
+		"Failed to generate promo codes":                                    "Не удалось сгенерировать промокоды",
+		"Failed to save promo codes":                                        "Не удалось сохранить промокоды",
+		"Gift card has expired":                                             "Срок действия подарочной карты истек",
+		"Gift card balance is empty":                                        "На подарочной карте нет средств",
+		"Gift card balance is insufficient":                                 "Недостаточно средств на подарочной карте",
+		"Failed to charge gift card":                                        "Не удалось списать средства с подарочной карты",
+		"Failed to create gift card":                                        "Не удалось создать подарочную карту",
+		"Failed to disable gift card":                                       "Не удалось отключить подарочную карту",
+		"Failed to fetch gift cards":                                        "Не удалось загрузить подарочные карты",
+		"Failed to decode gift cards":                                       "Не удалось обработать подарочные карты",
+		"Count must be between 1 and 5000":                                  "Количество должно быть от 1 до 5000",
+		"expiresAt must be in the future":                                   "expiresAt должен быть в будущем",
+		"Insufficient loyalty points":                                       "Недостаточно баллов",
+		"Failed to redeem loyalty points":                                   "Не удалось списать баллы",
+		"Failed to calculate spend":                                         "Не удалось рассчитать сумму покупок",
+		"Failed to fetch loyalty history":                                   "Не удалось загрузить историю баллов",
+		"Failed to decode loyalty history":                                  "Не удалось обработать историю баллов",
+		"Invalid loyalty rate ID":                                           "Некорректный ID ставки начисления баллов",
+		"Loyalty rate not found":                                            "Ставка начисления баллов не найдена",
+		"cinemaId or format is required":                                    "Требуется cinemaId или format",
+		"pointsPer100 must be between 0 and 100":                            "pointsPer100 должен быть от 0 до 100",
+		"Failed to create loyalty rate":                                     "Не удалось создать ставку начисления баллов",
+		"Failed to delete loyalty rate":                                     "Не удалось удалить ставку начисления баллов",
+		"Failed to fetch loyalty rates":                                     "Не удалось загрузить ставки начисления баллов",
+		"Failed to decode loyalty rates":                                    "Не удалось обработать ставки начисления баллов",
+
+		// Абонементы
+		"Subscription plan not found":     "Тариф абонемента не найден",
+		"Invalid subscription plan ID":    "Некорректный ID тарифа абонемента",
+		"You have no active pass":         "У вас нет активного абонемента",
+		"No pass with auto-renewal found": "Абонемент с автопродлением не найден",
+		"A pass cannot be combined with promo codes, gift cards or loyalty points": "Абонемент нельзя совмещать с промокодами, подарочными картами и баллами",
+
+		"Plan name is required":                              "Требуется название тарифа",
+		"price must be greater than 0":                       "price должен быть больше 0",
+		"filmsPerPeriod must be greater than 0":              "filmsPerPeriod должен быть больше 0",
+		"periodDays must be between 1 and 366":               "periodDays должен быть от 1 до 366",
+		"Your pass is valid only for formats":                "Абонемент действует только для форматов",
+		"Your pass is valid only for halls":                  "Абонемент действует только в залах",
+		"A pass covers one seat per booking":                 "Абонемент покрывает одно место в бронировании",
+		"Failed to load subscription":                        "Не удалось загрузить абонемент",
+		"Your pass period has ended and is awaiting renewal": "Период абонемента закончился и ожидает продления",
+		"Showtime is after the end of your pass period":      "Сеанс начинается после окончания периода абонемента",
+		"Failed to use pass":                                 "Не удалось использовать абонемент",
+		"Pass allowance used up":                             "Лимит фильмов по абонементу исчерпан",
+		"Failed to check subscriptions":                      "Не удалось проверить абонементы",
+		"Your pass is being renewed, try again":              "Абонемент продлевается, попробуйте снова",
+		"You already have an active pass":                    "У вас уже есть активный абонемент",
+		"Failed to create subscription":                      "Не удалось оформить абонемент",
+		"Failed to fetch subscription":                       "Не удалось загрузить абонемент",
+		"Failed to cancel subscription":                      "Не удалось отключить автопродление",
+		"Failed to create subscription plan":                 "Не удалось создать тариф абонемента",
+		"Failed to update subscription plan":                 "Не удалось обновить тариф абонемента",
+		"Failed to delete subscription plan":                 "Не удалось удалить тариф абонемента",
+		"Failed to fetch subscription plans":                 "Не удалось загрузить тарифы абонементов",
+		"Failed to decode subscription plans":                "Не удалось обработать тарифы абонементов",
+
+		// Бар
+		"Invalid concession item ID":                            "Некорректный ID позиции бара",
+		"Concession item not found":                             "Позиция бара не найдена",
+		"Concession item not found or insufficient stock":       "Позиция бара не найдена или закончилась",
+		"Concessions can be added only to active bookings":      "Заказ из бара можно добавить только к активному бронированию",
+		"Concessions for pass bookings are added after booking": "Заказ из бара для брони по абонементу добавляется после бронирования",
+		"Active concession order not found":                     "Активный заказ из бара не найден",
+		"The concession order has already been prepared":        "Заказ из бара уже приготовлен",
+		"Invalid status. Use: preparing, ready, or collected":   "Неверный статус. Используйте: preparing, ready или collected",
+
+		"Concession quantity must be positive":                            "Количество товаров бара должно быть положительным",
+		"Failed to load concessions":                                      "Не удалось загрузить товары бара",
+		"Failed to reserve concessions":                                   "Не удалось зарезервировать товары бара",
+		"Some concession items are out of stock":                          "Некоторых товаров бара нет в наличии",
+		"Pickup time is in the past":                                      "Время выдачи уже прошло",
+		"Item name is required":                                           "Требуется название позиции",
+		"stock must not be negative":                                      "stock не может быть отрицательным",
+		"A combo must contain at least one item":                          "Комбо должно содержать хотя бы одну позицию",
+		"Invalid category. Use: snack, drink, or combo":                   "Неверная категория. Используйте: snack, drink или combo",
+		"Combo item quantity must be positive":                            "Количество позиции в комбо должно быть положительным",
+		"Failed to validate combo items":                                  "Не удалось проверить позиции комбо",
+		"Combo items must be existing non-combo items of the same cinema": "Комбо может состоять только из существующих позиций (не комбо) того же кинотеатра",
+		"A combo cannot contain itself":                                   "Комбо не может содержать само себя",
+		"Failed to create concession item":                                "Не удалось создать позицию бара",
+		"Failed to update concession item":                                "Не удалось обновить позицию бара",
+		"Failed to delete concession item":                                "Не удалось удалить позицию бара",
+		"Failed to fetch concessions":                                     "Не удалось загрузить меню бара",
+		"Failed to decode concessions":                                    "Не удалось обработать меню бара",
+		"cinemaId is required":                                            "Требуется cinemaId",
+		"Failed to fetch orders":                                          "Не удалось загрузить заказы",
+		"Failed to decode orders":                                         "Не удалось обработать заказы",
+
+		// Отзывы
+		"Invalid review ID":                     "Некорректный ID отзыва",
+		"Review not found":                      "Отзыв не найден",
+		"You have already reviewed this movie":  "Вы уже оставили отзыв об этом фильме",
+		"You have already reviewed this cinema": "Вы уже оставили отзыв об этом кинотеатре",
+		"Only viewers with a confirmed booking for a past showtime can review this movie":   "Оставить отзыв о фильме могут только зрители с подтвержденным бронированием на прошедший сеанс",
+		"Only visitors with a confirmed booking for a past showtime can review this cinema": "Оставить отзыв о кинотеатре могут только посетители с подтвержденным бронированием на прошедший сеанс",
+		"All scores must be between 1 and 5":                                                "Все оценки должны быть от 1 до 5",
+		"Comment is too long":                                                               "Комментарий слишком длинный",
+		"Invalid action. Use: hide, publish, flag, or unflag":                               "Неверное действие. Используйте: hide, publish, flag или unflag",
+
+		"Rating must be between 1 and 10":  "Оценка должна быть от 1 до 10",
+		"Failed to verify booking history": "Не удалось проверить историю бронирований",
+		"Failed to create review":          "Не удалось создать отзыв",
+		"Failed to delete review":          "Не удалось удалить отзыв",
+		"Failed to fetch reviews":          "Не удалось загрузить отзывы",
+		"Failed to decode reviews":         "Не удалось обработать отзывы",
+		"Failed to count reviews":          "Не удалось подсчитать отзывы",
+
+		// Файлы
+		"Invalid file ID": "Некорректный ID файла",
+		"File not found":  "Файл не найден",
+		"File is empty":   "Файл пустой",
+		"File is required (multipart field \"file\")": "Требуется файл (поле multipart \"file\")",
+		"Failed to read file":                         "Не удалось прочитать файл",
+		"Unsupported file type":                       "Неподдерживаемый тип файла",
+		"Invalid image":                               "Некорректное изображение",
+		"Invalid width":                               "Некорректная ширина",
+		"Unsupported format (allowed: jpeg, webp)":    "Неподдерживаемый формат (допустимы: jpeg, webp)",
+		"Image not found":                             "Изображение не найдено",
+
+		"Failed to fetch file":        "Не удалось загрузить файл",
+		"Failed to open file storage": "Не удалось открыть хранилище файлов",
+		"Failed to store file":        "Не удалось сохранить файл",
+
+		// Поиск
+		"Query parameter q is required":             "Требуется параметр q",
+		"Query is too long":                         "Слишком длинный запрос",
+		"Invalid types. Use: movie, person, cinema": "Неверный types. Используйте: movie, person, cinema",
+		"Search index is not ready":                 "Поисковый индекс еще не готов",
+
+		// Персоны
+		"Invalid person ID":                    "Некорректный ID персоны",
+		"Person not found":                     "Персона не найдена",
+		"Name must be at least 2 characters":   "Имя должно содержать не менее 2 символов",
+		"Invalid role. Use: director or actor": "Неверная роль. Используйте: director или actor",
+
+		"Failed to create person":      "Не удалось создать персону",
+		"Failed to update person":      "Не удалось обновить персону",
+		"Failed to fetch people":       "Не удалось загрузить персоны",
+		"Failed to decode people":      "Не удалось обработать персоны",
+		"Failed to link movie credits": "Не удалось связать фильм с персонами",
+
+		// Рекомендации
+		"Failed to fetch recommendations":    "Не удалось получить рекомендации",
+		"Because you watched":                "Потому что вы смотрели",
+		"Matches your favorite genre":        "Ваш любимый жанр",
+		"Same director or cast":              "Тот же режиссер или актер",
+		"Showing in your favorite format":    "Идет в вашем любимом формате",
+		"Showing in your preferred language": "Идет на предпочитаемом языке",
+		"Popular right now":                  "Сейчас популярно",
+
+		// Watchlist и уведомления
+		"Movie is not in watchlist":     "Фильма нет в списке «Хочу посмотреть»",
+		"Failed to update watchlist":    "Не удалось обновить список",
+		"Failed to fetch watchlist":     "Не удалось получить список",
+		"Failed to fetch notifications": "Не удалось получить уведомления",
+		"Tickets are on sale":           "Билеты в продаже",
+
+		"Failed to decode watchlist":     "Не удалось обработать список",
+		"Failed to decode notifications": "Не удалось обработать уведомления",
+
+		"Showtimes for a movie from your watchlist are now available.": "Появились сеансы фильма из вашего списка «Хочу посмотреть».",
+		"Open the movie page to choose a showtime:":                    "Откройте страницу фильма, чтобы выбрать сеанс:",
+
+		// Возрастные ограничения
+		"Invalid date of birth. Use: YYYY-MM-DD":                     "Некорректная дата рождения. Используйте: ГГГГ-ММ-ДД",
+		"Date of birth is already set. Contact support to change it": "Дата рождения уже указана. Для изменения обратитесь в поддержку",
+		"Invalid age policy. Use: block or warn":                     "Неверная политика. Используйте: block или warn",
+
+		"Failed to update user": "Не удалось обновить пользователя",
+
+		// Импорт фильмов
+		"Metadata file is required":      "Нужен файл метаданных",
+		"failed to fetch movie metadata": "Не удалось получить метаданные фильмов",
+
+		// Фильтры каталога фильмов
+		"Invalid sortBy. Use: createdAt, title, releaseDate, imdbRating, reviewRating, duration, popularity, or nextShowtime": "Некорректный sortBy. Используйте: createdAt, title, releaseDate, imdbRating, reviewRating, duration, popularity или nextShowtime",
+		"Invalid genreMatch. Use: any or all": "Некорректный genreMatch. Используйте: any или all",
+		"Invalid date. Use: YYYY-MM-DD":       "Некорректная дата. Используйте: ГГГГ-ММ-ДД",
+
+		// Сессии
+		"Invalid refresh token":                             "Недействительный refresh токен",
+		"Refresh token expired":                             "Срок действия refresh токена истек",
+		"Refresh token has been revoked":                    "Refresh токен отозван",
+		"Refresh token reuse detected. Please log in again": "Обнаружено повторное использование refresh токена. Войдите снова",
+		"Failed to refresh token":                           "Не удалось обновить токен",
+		"Token has been revoked":                            "Токен отозван",
+		"Failed to verify token":                            "Не удалось проверить токен",
+		"Failed to log out":                                 "Не удалось выйти",
+
+		"Refresh token was just rotated. Retry with the latest token": "Refresh токен только что заменен. Повторите запрос с новым токеном",
+
+		// Сброс пароля и подтверждение email
+		"Failed to reset password":                                    "Не удалось сбросить пароль",
+		"Invalid or expired reset link":                               "Ссылка для сброса пароля недействительна или устарела",
+		"Failed to verify email":                                      "Не удалось подтвердить email",
+		"Invalid or expired verification link":                        "Ссылка для подтверждения недействительна или устарела",
+		"Email is already verified":                                   "Email уже подтвержден",
+		"Failed to send verification email":                           "Не удалось отправить письмо",
+		"Verification email was sent recently. Try again in a minute": "Письмо уже отправлено. Повторите через минуту",
+
+		"Please confirm your email address first. Check your inbox or request a new link": "Сначала подтвердите email. Проверьте почту или запросите новую ссылку",
+		"Failed to verify account": "Не удалось проверить аккаунт",
+
+		// Письма
+		"Hello":              "Здравствуйте",
+		"Confirm your email": "Подтвердите email",
+		"Password reset":     "Сброс пароля",
+		"Open the link to confirm your email address (valid for 48 hours):": "Перейдите по ссылке, чтобы подтвердить email (действует 48 часов):",
+		"If you did not create an account, ignore this email.":              "Если вы не регистрировались, просто проигнорируйте это письмо.",
+		"Open the link to set a new password (valid for 1 hour):":           "Перейдите по ссылке, чтобы задать новый пароль (действует 1 час):",
+		"If you did not request a password reset, ignore this email.":       "Если вы не запрашивали сброс пароля, просто проигнорируйте это письмо.",
+
+		// Защита входа
+		"Too many failed login attempts. Try again later":                                                                 "Слишком много неудачных попыток входа. Повторите позже",
+		"Too many failed login attempts. Sign-in is temporarily locked, check your email to unlock it or try again later": "Слишком много неудачных попыток входа. Вход временно заблокирован: разблокируйте его по ссылке из письма или повторите позже",
+		"Failed to unlock account":                                         "Не удалось разблокировать аккаунт",
+		"Invalid or expired unlock link":                                   "Ссылка для разблокировки недействительна или устарела",
+		"Failed to fetch audit log":                                        "Не удалось получить журнал безопасности",
+		"Failed to decode audit log":                                       "Не удалось обработать журнал безопасности",
+		"Failed to count audit entries":                                    "Не удалось подсчитать записи журнала",
+		"Sign-in to your account has been locked":                          "Вход в аккаунт заблокирован",
+		"We locked sign-in to your account after several failed attempts.": "Мы заблокировали вход в ваш аккаунт после нескольких неудачных попыток.",
+		"If it was you, open the link to unlock it (valid for 24 hours):":  "Если это были вы, перейдите по ссылке, чтобы разблокировать вход (действует 24 часа):",
+		"If it was not you, we recommend resetting your password.":         "Если это были не вы, рекомендуем сменить пароль.",
+
+		// Двухфакторная аутентификация
+		"Two-factor authentication is required for your role. Set it up and sign in again": "Для вашей роли обязательна двухфакторная аутентификация. Подключите ее и войдите снова",
+		"Two-factor authentication is mandatory for your role":                             "Для вашей роли двухфакторная аутентификация обязательна",
+		"Two-factor authentication is already enabled":                                     "Двухфакторная аутентификация уже включена",
+		"Two-factor authentication is not enabled":                                         "Двухфакторная аутентификация не включена",
+		"Start two-factor authentication setup first":                                      "Сначала начните настройку двухфакторной аутентификации",
+		"Two-factor authentication setup has changed. Start again":                         "Настройка двухфакторной аутентификации изменилась. Начните заново",
+		"Invalid authentication code":                                                      "Неверный код подтверждения",
+		"Sign-in session expired. Please log in again":                                     "Время на ввод кода истекло. Войдите снова",
+		"Failed to start two-factor authentication":                                        "Не удалось начать проверку второго фактора",
+		"Failed to verify code":                                                            "Не удалось проверить код",
+		"Failed to set up two-factor authentication":                                       "Не удалось настроить двухфакторную аутентификацию",
+		"Failed to enable two-factor authentication":                                       "Не удалось включить двухфакторную аутентификацию",
+		"Failed to disable two-factor authentication":                                      "Не удалось отключить двухфакторную аутентификацию",
+		"Failed to generate recovery codes":                                                "Не удалось создать резервные коды",
+
+		// Расписание: импорт и экспорт
+		"Schedule file is required (multipart field \"file\" or request body)":                 "Требуется файл расписания (multipart поле \"file\" или тело запроса)",
+		"Schedule file contains no rows":                                                       "В файле расписания нет строк",
+		"Maximum 5000 rows per import":                                                         "Не более 5000 строк за один импорт",
+		"Showtimes were created concurrently in the same halls. Import rolled back, try again": "В тех же залах одновременно созданы сеансы. Импорт отменен, попробуйте снова",
+		"failed to read CSV header":                                                            "не удалось прочитать заголовок CSV",
+		"failed to parse JSON":                                                                 "не удалось разобрать JSON",
+		"failed to check existing showtimes":                                                   "не удалось проверить существующие сеансы",
+		"failed to save showtimes":                                                             "не удалось сохранить сеансы",
+		"Invalid or missing 'from' date. Use: YYYY-MM-DD":                                      "Дата 'from' не указана или некорректна. Используйте: ГГГГ-ММ-ДД",
+		"Invalid 'to' date. Use: YYYY-MM-DD":                                                   "Некорректная дата 'to'. Используйте: ГГГГ-ММ-ДД",
+		"'to' must not be before 'from'":                                                       "'to' не может быть раньше 'from'",
+		"Invalid format. Use: csv or json":                                                     "Некорректный формат. Используйте: csv или json",
+		"Failed to export schedule":                                                            "Не удалось выгрузить расписание",
+		"Failed to write CSV":                                                                  "Не удалось записать CSV",
+
+		// Правила цен
+		"Invalid pricing rule ID":                                                        "Некорректный ID правила цены",
+		"Pricing rule not found":                                                         "Правило цены не найдено",
+		"Name is required":                                                               "Требуется название",
+		"Multiplier must be between 0 and 10":                                            "Множитель должен быть от 0 до 10",
+		"Surcharge must not be negative":                                                 "Надбавка не может быть отрицательной",
+		"startHour must be 0-23 and endHour 0-24, and they must differ":                  "startHour должен быть 0-23, endHour 0-24, и они должны различаться",
+		"weekdays are required (0 = Sunday ... 6 = Saturday)":                            "Требуется weekdays (0 = воскресенье ... 6 = суббота)",
+		"formats are required, e.g. [\"3D\", \"IMAX\"]":                                  "Требуется formats, например [\"3D\", \"IMAX\"]",
+		"premiereDays must not be negative":                                              "premiereDays не может быть отрицательным",
+		"minOccupancy must be between 0 and 1":                                           "minOccupancy должен быть от 0 до 1",
+		"Invalid rule type. Use: time_of_day, weekday, format, premiere_week, occupancy": "Неверный тип правила. Используйте: time_of_day, weekday, format, premiere_week, occupancy",
+		"Failed to load pricing rules":                                                   "Не удалось загрузить правила цен",
+		"Failed to fetch pricing rules":                                                  "Не удалось загрузить правила цен",
+		"Failed to decode pricing rules":                                                 "Не удалось обработать правила цен",
+		"Failed to create pricing rule":                                                  "Не удалось создать правило цены",
+		"Failed to update pricing rule":                                                  "Не удалось обновить правило цены",
+		"Failed to delete pricing rule":                                                  "Не удалось удалить правило цены",
+
+		// Категории билетов и вход в зал
+		"Category code is required":                 "Требуется код категории",
+		"discountPercent must be between 0 and 100": "discountPercent должен быть от 0 до 100",
+		"maxAge must not be negative":               "maxAge не может быть отрицательным",
+		"Failed to update ticket categories":        "Не удалось обновить категории билетов",
+		"Failed to check in booking":                "Не удалось отметить вход по бронированию",
+
+		// Аналитика
+		"Failed to aggregate popular movies":      "Не удалось рассчитать популярные фильмы",
+		"Failed to aggregate cinema stats":        "Не удалось рассчитать статистику кинотеатров",
+		"Failed to aggregate revenue":             "Не удалось рассчитать выручку",
+		"Failed to aggregate revenue by category": "Не удалось рассчитать выручку по категориям билетов",
+		"Failed to aggregate pass revenue":        "Не удалось рассчитать выручку от абонементов",
+		"Failed to aggregate pass redemptions":    "Не удалось рассчитать использование абонементов",
+		"Failed to aggregate watchlist":           "Не удалось рассчитать статистику списков «Хочу посмотреть»",
+	},
+	Kazakh: {
+		// Общие
+		"Invalid request data":     "Сұраныс деректері қате",
+		"Internal server error":    "Сервердің ішкі қатесі",
+		"No fields to update":      "Жаңартатын өрістер жоқ",
+		"Failed to decode results": "Нәтижелерді өңдеу мүмкін болмады",
+
+		// Авторизация
+		"Authorization header is required":                         "Authorization тақырыбы қажет",
+		"Invalid authorization header format. Use: Bearer <token>": "Authorization тақырыбының пішімі қате. Пайдаланыңыз: Bearer <token>",
+		"Invalid or expired token":                                 "Токен жарамсыз немесе мерзімі өткен",
+		"Unauthorized":                                             "Авторизация қажет",
+		"Forbidden: No role information":                           "Қол жеткізу жабық: рөл анықталмаған",
+		"Forbidden: Insufficient permissions":                      "Қол жеткізу жабық: құқықтар жеткіліксіз",
+		"Invalid email or password":                                "Email немесе құпия сөз қате",
+		"Invalid email format":                                     "Email пішімі қате",
+		"Invalid recipient email format":                           "Алушының email пішімі қате",
+		"Invalid phone format. Use: +7XXXXXXXXXX":                  "Телефон пішімі қате. Пайдаланыңыз: +7XXXXXXXXXX",
+		"Full name must be at least 2 characters":                  "Аты кемінде 2 таңбадан тұруы керек",
+		"Password must be at least 6 characters long":              "Құпия сөз кемінде 6 таңбадан тұруы керек",
+		"Email already registered":                                 "Бұл email тіркелген",
+		"User not found":                                           "Пайдаланушы табылмады",
+		"Invalid user ID":                                          "Пайдаланушы ID қате",
+		"Failed to generate token":                                 "Токен жасау мүмкін болмады",
+		"Failed to create user":                                    "Пайдаланушыны жасау мүмкін болмады",
+		"Failed to update profile":                                 "Профильді жаңарту мүмкін болмады",
+
+		"Failed to hash password":         "Құпиясөзді өңдеу мүмкін болмады",
+		"Failed to fetch updated profile": "Жаңартылған профильді жүктеу мүмкін болмады",
+		"Failed to fetch updated user":    "Пайдаланушының жаңартылған деректерін жүктеу мүмкін болмады",
+
+		// Фильмы и кинотеатры
+		"Invalid movie ID":        "Фильм ID қате",
+		"Movie not found":         "Фильм табылмады",
+		"Movie ID is required":    "Фильм ID қажет",
+		"Title is required":       "Атауы қажет",
+		"Failed to fetch movies":  "Фильмдерді жүктеу мүмкін болмады",
+		"Invalid cinema ID":       "Кинотеатр ID қате",
+		"Cinema not found":        "Кинотеатр табылмады",
+		"Cinema ID is required":   "Кинотеатр ID қажет",
+		"Failed to fetch cinemas": "Кинотеатрларды жүктеу мүмкін болмады",
+		"Invalid sortBy. Use: name, rating, or totalReviews": "sortBy қате. Пайдаланыңыз: name, rating немесе totalReviews",
+		"Hall ID is required":                                "Зал ID қажет",
+		"Hall not found or doesn't belong to this cinema":    "Зал табылмады немесе бұл кинотеатрға тиесілі емес",
+
+		"Failed to count cinemas":  "Кинотеатрларды санау мүмкін болмады",
+		"Failed to count movies":   "Фильмдерді санау мүмкін болмады",
+		"Failed to create movie":   "Фильмді жасау мүмкін болмады",
+		"Failed to update movie":   "Фильмді жаңарту мүмкін болмады",
+		"Failed to delete movie":   "Фильмді жою мүмкін болмады",
+		"Failed to decode movies":  "Фильмдерді өңдеу мүмкін болмады",
+		"Failed to decode cinemas": "Кинотеатрларды өңдеу мүмкін болмады",
+		"Failed to update cinema":  "Кинотеатрды жаңарту мүмкін болмады",
+
+		// Сеансы
+		"Invalid showtime ID":                           "Сеанс ID қате",
+		"Showtime not found":                            "Сеанс табылмады",
+		"Showtime has already started":                  "Сеанс басталып кетті",
+		"Start time is required":                        "Басталу уақыты қажет",
+		"Start time must be in the future":              "Басталу уақыты болашақта болуы керек",
+		"Cannot delete showtime with existing bookings": "Брондаулары бар сеансты жою мүмкін емес",
+		"Failed to fetch showtimes":                     "Сеанстарды жүктеу мүмкін болмады",
+
+		"Showtime overlaps an existing showtime in this hall":          "Сеанс осы залдағы басқа сеанспен қиылысады",
+		"Hall schedule is being changed by another request, try again": "Зал кестесін қазір басқа сұраныс өзгертуде, қайталап көріңіз",
+
+		"Failed to create showtime":           "Сеансты жасау мүмкін болмады",
+		"Failed to delete showtime":           "Сеансты жою мүмкін болмады",
+		"Failed to count showtimes":           "Сеанстарды санау мүмкін болмады",
+		"Failed to decode showtimes":          "Сеанстарды өңдеу мүмкін болмады",
+		"Failed to fetch detailed showtimes":  "Сеанстардың толық деректерін жүктеу мүмкін болмады",
+		"Failed to decode detailed showtimes": "Сеанстардың толық деректерін өңдеу мүмкін болмады",
+
+		// Бронирования и оплата
+		"Invalid booking ID":                                               "Брондау ID қате",
+		"Booking not found":                                                "Брондау табылмады",
+		"Booking has expired":                                              "Брондау мерзімі өтті",
+		"Booking is cancelled":                                             "Брондау жойылған",
+		"Booking is already cancelled":                                     "Брондау бұрын жойылған",
+		"Booking is already confirmed":                                     "Брондау бұрын расталған",
+		"Booking is already checked in":                                    "Брондау бойынша кіру бұрын белгіленген",
+		"Booking was changed, please try again":                            "Брондау өзгерді, қайталап көріңіз",
+		"Only confirmed bookings can be checked in":                        "Залға тек расталған брондау бойынша кіруге болады",
+		"Cannot cancel booking less than 2 hours before showtime":          "Сеансқа 2 сағаттан аз уақыт қалғанда брондауды жою мүмкін емес",
+		"Maximum 10 seats per booking":                                     "Бір брондауда 10 орыннан артық болмайды",
+		"Document verification required for seats":                         "Орындар үшін құжатты тексеру қажет",
+		"Invalid payment method. Use: wallet, card, cash, points, or pass": "Төлем әдісі қате. Пайдаланыңыз: wallet, card, cash, points немесе pass",
+		"Invalid payment method. Use: wallet or card":                      "Төлем әдісі қате. Пайдаланыңыз: wallet немесе card",
+		"Insufficient wallet balance":                                      "Әмиянда қаражат жеткіліксіз",
+		"Amount must be greater than 0":                                    "Сома 0-ден көп болуы керек",
+		"Maximum top-up amount is 1,000,000 KZT":                           "Толтырудың ең жоғары сомасы - 1 000 000 KZT",
+		"Failed to create booking":                                         "Брондау жасау мүмкін болмады",
+		"Failed to cancel booking":                                         "Брондауды жою мүмкін болмады",
+		"Failed to confirm booking":                                        "Брондауды растау мүмкін болмады",
+		"Failed to fetch bookings":                                         "Брондауларды жүктеу мүмкін болмады",
+
+		"Booking is no longer pending":          "Брондау енді төлемді күтпейді",
+		"Booking status has changed, try again": "Брондау мәртебесі өзгерді, қайталап көріңіз",
+		"Failed to decode bookings":             "Брондауларды өңдеу мүмкін болмады",
+		"Insufficient wallet balance. Required": "Әмиянда қаражат жеткіліксіз. Қажет",
+		"Failed to charge wallet":               "Әмияннан қаражат алу мүмкін болмады",
+		"Failed to credit wallet":               "Әмиянға қаражат аудару мүмкін болмады",
+		"Failed to top up wallet":               "Әмиянды толтыру мүмкін болмады",
+
+		// Промокоды, подарочные карты, лояльность
+		"Promo code not found":                   "Промокод табылмады",
+		"Promo code already exists":              "Промокод бұрыннан бар",
+		"Invalid promo code ID":                  "Промокод ID қате",
+		"Gift card not found":                    "Сыйлық картасы табылмады",
+		"Invalid gift card ID":                   "Сыйлық картасының ID қате",
+		"Gift card has already been redeemed":    "Сыйлық картасы бұрын пайдаланылған",
+		"Message must be at most 500 characters": "Хабарлама 500 таңбадан аспауы керек",
+		"loyaltyPoints must not be negative":     "loyaltyPoints теріс болмауы керек",
+
+		"Promo code is not active":                                          "Промокод белсенді емес",
+		"Promo code is not valid yet":                                       "Промокод әлі күшіне енбеген",
+		"Promo code has expired":                                            "Промокодтың мерзімі өтті",
+		"Promo code is not valid for this movie":                            "Промокод бұл фильмге жарамсыз",
+		"Promo code is not valid at this cinema":                            "Промокод бұл кинотеатрда жарамсыз",
+		"Promo code usage limit reached":                                    "Промокодты пайдалану лимиті таусылды",
+		"You have already used this promo code the maximum number of times": "Сіз бұл промокодты рұқсат етілген ең көп рет пайдаландыңыз",
+		"Failed to check promo code usage":                                  "Промокодтың пайдаланылуын тексеру мүмкін болмады",
+		"Failed to apply promo code":                                        "Промокодты қолдану мүмкін болмады",
+		"Code must be 3-32 characters long":                                 "Код 3-32 таңбадан тұруы керек",
+		"Code may contain only letters, digits, '-' and '_'":                "Кодта тек әріптер, цифрлар, '-' және '_' болуы мүмкін",
+		"Percentage discount must be between 0 and 100":                     "Пайыздық жеңілдік 0 мен 100 аралығында болуы керек",
+		"Fixed discount must be greater than 0":                             "Тіркелген жеңілдік 0-ден көп болуы керек",
+		"Invalid discount type. Use: percentage or fixed":                   "Жеңілдік түрі қате. Пайдаланыңыз: percentage немесе fixed",
+		"Limits must not be negative":                                       "Лимиттер теріс болмауы керек",
+		"validUntil must be after validFrom":                                "validUntil validFrom-нан кейін болуы керек",
+		"weekdays must be between 0 (Sunday) and 6 (Saturday)":              "weekdays 0 (жексенбі) мен 6 (сенбі) аралығында болуы керек",
+		"Count must be between 1 and 10000":                                 "Саны 1 мен 10000 аралығында болуы керек",
+		"Length must be between 6 and 16":                                   "Ұзындығы 6 мен 16 аралығында болуы керек",
+		"Failed to create promo code":                                       "Промокодты жасау мүмкін болмады",
+		"Failed to update promo code":                                       "Промокодты жаңарту мүмкін болмады",
+		"Failed to delete promo code":                                       "Промокодты жою мүмкін болмады",
+		"Failed to fetch promo codes":                                       "Промокодтарды жүктеу мүмкін болмады",
+		"Failed to decode promo codes":                                      "Промокодтарды өңдеу мүмкін болмады",
+		"Failed to generate promo codes":                                    "Промокодтарды жасау мүмкін болмады",
+		"Failed to save promo codes":                                        "Промокодтарды сақтау мүмкін болмады",
+		"Gift card has expired":                                             "Сыйлық картасының мерзімі өтті",
+		"Gift card balance is empty":                                        "Сыйлық картасында қаражат жоқ",
+		"Gift card balance is insufficient":                                 "Сыйлық картасында қаражат жеткіліксіз",
+		"Failed to charge gift card":                                        "Сыйлық картасынан қаражат алу мүмкін болмады",
+		"Failed to create gift card":                                        "Сыйлық картасын жасау мүмкін болмады",
+		"Failed to disable gift card":                                       "Сыйлық картасын өшіру мүмкін болмады",
+		"Failed to fetch gift cards":                                        "Сыйлық карталарын жүктеу мүмкін болмады",
+		"Failed to decode gift cards":                                       "Сыйлық карталарын өңдеу мүмкін болмады",
+		"Count must be between 1 and 5000":                                  "Саны 1 мен 5000 аралығында болуы керек",
+		"expiresAt must be in the future":                                   "expiresAt болашақта болуы керек",
+		"Insufficient loyalty points":                                       "Ұпайлар жеткіліксіз",
+		"Failed to redeem loyalty points":                                   "Ұпайларды есептен шығару мүмкін болмады",
+		"Failed to calculate spend":                                         "Сатып алулар сомасын есептеу мүмкін болмады",
+		"Failed to fetch loyalty history":                                   "Ұпайлар тарихын жүктеу мүмкін болмады",
+		"Failed to decode loyalty history":                                  "Ұпайлар тарихын өңдеу мүмкін болмады",
+		"Invalid loyalty rate ID":                                           "Ұпай есептеу мөлшерлемесінің ID қате",
+		"Loyalty rate not found":                                            "Ұпай есептеу мөлшерлемесі табылмады",
+		"cinemaId or format is required":                                    "cinemaId немесе format қажет",
+		"pointsPer100 must be between 0 and 100":                            "pointsPer100 0 мен 100 аралығында болуы керек",
+		"Failed to create loyalty rate":                                     "Ұпай есептеу мөлшерлемесін жасау мүмкін болмады",
+		"Failed to delete loyalty rate":                                     "Ұпай есептеу мөлшерлемесін жою мүмкін болмады",
+		"Failed to fetch loyalty rates":                                     "Ұпай есептеу мөлшерлемелерін жүктеу мүмкін болмады",
+		"Failed to decode loyalty rates":                                    "Ұпай есептеу мөлшерлемелерін өңдеу мүмкін болмады",
+
+		// Абонементы
+		"Subscription plan not found":     "Абонемент тарифі табылмады",
+		"Invalid subscription plan ID":    "Абонемент тарифінің ID қате",
+		"You have no active pass":         "Сізде белсенді абонемент жоқ",
+		"No pass with auto-renewal found": "Автоұзартылатын абонемент табылмады",
+		"A pass cannot be combined with promo codes, gift cards or loyalty points": "Абонементті промокодтармен, сыйлық карталарымен және ұпайлармен біріктіруге болмайды",
+
+		"Plan name is required":                              "Тариф атауы қажет",
+		"price must be greater than 0":                       "price 0-ден көп болуы керек",
+		"filmsPerPeriod must be greater than 0":              "filmsPerPeriod 0-ден көп болуы керек",
+		"periodDays must be between 1 and 366":               "periodDays 1 мен 366 аралығында болуы керек",
+		"Your pass is valid only for formats":                "Абонемент тек мына форматтарға жарамды",
+		"Your pass is valid only for halls":                  "Абонемент тек мына залдарда жарамды",
+		"A pass covers one seat per booking":                 "Абонемент бір брондауда бір орынды қамтиды",
+		"Failed to load subscription":                        "Абонементті жүктеу мүмкін болмады",
+		"Your pass period has ended and is awaiting renewal": "Абонемент кезеңі аяқталды және ұзартуды күтуде",
+		"Showtime is after the end of your pass period":      "Сеанс абонемент кезеңі аяқталғаннан кейін басталады",
+		"Failed to use pass":                                 "Абонементті пайдалану мүмкін болмады",
+		"Pass allowance used up":                             "Абонемент бойынша фильмдер лимиті таусылды",
+		"Failed to check subscriptions":                      "Абонементтерді тексеру мүмкін болмады",
+		"Your pass is being renewed, try again":              "Абонемент ұзартылуда, қайталап көріңіз",
+		"You already have an active pass":                    "Сізде белсенді абонемент бар",
+		"Failed to create subscription":                      "Абонементті рәсімдеу мүмкін болмады",
+		"Failed to fetch subscription":                       "Абонементті жүктеу мүмкін болмады",
+		"Failed to cancel subscription":                      "Автоұзартуды өшіру мүмкін болмады",
+		"Failed to create subscription plan":                 "Абонемент тарифін жасау мүмкін болмады",
+		"Failed to update subscription plan":                 "Абонемент тарифін жаңарту мүмкін болмады",
+		"Failed to delete subscription plan":                 "Абонемент тарифін жою мүмкін болмады",
+		"Failed to fetch subscription plans":                 "Абонемент тарифтерін жүктеу мүмкін болмады",
+		"Failed to decode subscription plans":                "Абонемент тарифтерін өңдеу мүмкін болмады",
+
+		// Бар
+		"Invalid concession item ID":                            "Бар позициясының ID қате",
+		"Concession item not found":                             "Бар позициясы табылмады",
+		"Concession item not found or insufficient stock":       "Бар позициясы табылмады немесе таусылды",
+		"Concessions can be added only to active bookings":      "Бар тапсырысын тек белсенді брондауға қосуға болады",
+		"Concessions for pass bookings are added after booking": "Абонемент брондауына бар тапсырысы брондаудан кейін қосылады",
+		"Active concession order not found":                     "Белсенді бар тапсырысы табылмады",
+		"The concession order has already been prepared":        "Бар тапсырысы дайын болып қойған",
+		"Invalid status. Use: preparing, ready, or collected":   "Мәртебе қате. Пайдаланыңыз: preparing, ready немесе collected",
+
+		"Concession quantity must be positive":                            "Бар тауарларының саны оң болуы керек",
+		"Failed to load concessions":                                      "Бар тауарларын жүктеу мүмкін болмады",
+		"Failed to reserve concessions":                                   "Бар тауарларын резервтеу мүмкін болмады",
+		"Some concession items are out of stock":                          "Бар тауарларының кейбірі таусылды",
+		"Pickup time is in the past":                                      "Беру уақыты өтіп кеткен",
+		"Item name is required":                                           "Позиция атауы қажет",
+		"stock must not be negative":                                      "stock теріс болмауы керек",
+		"A combo must contain at least one item":                          "Комбода кемінде бір позиция болуы керек",
+		"Invalid category. Use: snack, drink, or combo":                   "Санат қате. Пайдаланыңыз: snack, drink немесе combo",
+		"Combo item quantity must be positive":                            "Комбодағы позиция саны оң болуы керек",
+		"Failed to validate combo items":                                  "Комбо позицияларын тексеру мүмкін болмады",
+		"Combo items must be existing non-combo items of the same cinema": "Комбо тек сол кинотеатрдың бар позицияларынан тұруы керек (комбо емес)",
+		"A combo cannot contain itself":                                   "Комбо өзін-өзі қамти алмайды",
+		"Failed to create concession item":                                "Бар позициясын жасау мүмкін болмады",
+		"Failed to update concession item":                                "Бар позициясын жаңарту мүмкін болмады",
+		"Failed to delete concession item":                                "Бар позициясын жою мүмкін болмады",
+		"Failed to fetch concessions":                                     "Бар мәзірін жүктеу мүмкін болмады",
+		"Failed to decode concessions":                                    "Бар мәзірін өңдеу мүмкін болмады",
+		"cinemaId is required":                                            "cinemaId қажет",
+		"Failed to fetch orders":                                          "Тапсырыстарды жүктеу мүмкін болмады",
+		"Failed to decode orders":                                         "Тапсырыстарды өңдеу мүмкін болмады",
+
+		// Отзывы
+		"Invalid review ID":                     "Пікір ID қате",
+		"Review not found":                      "Пікір табылмады",
+		"You have already reviewed this movie":  "Сіз бұл фильмге пікір қалдырғансыз",
+		"You have already reviewed this cinema": "Сіз бұл кинотеатрға пікір қалдырғансыз",
+		"Only viewers with a confirmed booking for a past showtime can review this movie":   "Фильмге тек өткен сеансқа расталған брондауы бар көрермендер пікір қалдыра алады",
+		"Only visitors with a confirmed booking for a past showtime can review this cinema": "Кинотеатрға тек өткен сеансқа расталған брондауы бар келушілер пікір қалдыра алады",
+		"All scores must be between 1 and 5":                                                "Барлық бағалар 1 мен 5 аралығында болуы керек",
+		"Comment is too long":                                                               "Пікір тым ұзын",
+		"Invalid action. Use: hide, publish, flag, or unflag":                               "Әрекет қате. Пайдаланыңыз: hide, publish, flag немесе unflag",
+
+		"Rating must be between 1 and 10":  "Баға 1 мен 10 аралығында болуы керек",
+		"Failed to verify booking history": "Брондаулар тарихын тексеру мүмкін болмады",
+		"Failed to create review":          "Пікірді жасау мүмкін болмады",
+		"Failed to delete review":          "Пікірді жою мүмкін болмады",
+		"Failed to fetch reviews":          "Пікірлерді жүктеу мүмкін болмады",
+		"Failed to decode reviews":         "Пікірлерді өңдеу мүмкін болмады",
+		"Failed to count reviews":          "Пікірлерді санау мүмкін болмады",
+
+		// Файлы
+		"Invalid file ID": "Файл ID қате",
+		"File not found":  "Файл табылмады",
+		"File is empty":   "Файл бос",
+		"File is required (multipart field \"file\")": "Файл қажет (multipart \"file\" өрісі)",
+		"Failed to read file":                         "Файлды оқу мүмкін болмады",
+		"Unsupported file type":                       "Файл түріне қолдау көрсетілмейді",
+		"Invalid image":                               "Сурет қате",
+		"Invalid width":                               "Ені қате",
+		"Unsupported format (allowed: jpeg, webp)":    "Пішімге қолдау көрсетілмейді (рұқсат етілген: jpeg, webp)",
+		"Image not found":                             "Сурет табылмады",
+
+		"Failed to fetch file":        "Файлды жүктеу мүмкін болмады",
+		"Failed to open file storage": "Файл қоймасын ашу мүмкін болмады",
+		"Failed to store file":        "Файлды сақтау мүмкін болмады",
+
+		// Поиск
+		"Query parameter q is required":             "q параметрі қажет",
+		"Query is too long":                         "Сұраныс тым ұзын",
+		"Invalid types. Use: movie, person, cinema": "types қате. Пайдаланыңыз: movie, person, cinema",
+		"Search index is not ready":                 "Іздеу индексі әлі дайын емес",
+
+		// Персоны
+		"Invalid person ID":                    "Тұлға ID қате",
+		"Person not found":                     "Тұлға табылмады",
+		"Name must be at least 2 characters":   "Есім кемінде 2 таңбадан тұруы керек",
+		"Invalid role. Use: director or actor": "Рөл қате. Пайдаланыңыз: director немесе actor",
+
+		"Failed to create person":      "Тұлғаны жасау мүмкін болмады",
+		"Failed to update person":      "Тұлғаны жаңарту мүмкін болмады",
+		"Failed to fetch people":       "Тұлғаларды жүктеу мүмкін болмады",
+		"Failed to decode people":      "Тұлғаларды өңдеу мүмкін болмады",
+		"Failed to link movie credits": "Фильмді тұлғалармен байланыстыру мүмкін болмады",
+
+		// Рекомендации
+		"Failed to fetch recommendations":    "Ұсыныстарды алу мүмкін болмады",
+		"Because you watched":                "Сіз көрген фильмге ұқсас",
+		"Matches your favorite genre":        "Сүйікті жанрыңыз",
+		"Same director or cast":              "Сол режиссер немесе актер",
+		"Showing in your favorite format":    "Сүйікті форматыңызда көрсетіледі",
+		"Showing in your preferred language": "Сіз қалайтын тілде көрсетіледі",
+		"Popular right now":                  "Қазір танымал",
+
+		// Watchlist и уведомления
+		"Movie is not in watchlist":     "Фильм «Көргім келеді» тізімінде жоқ",
+		"Failed to update watchlist":    "Тізімді жаңарту мүмкін болмады",
+		"Failed to fetch watchlist":     "Тізімді алу мүмкін болмады",
+		"Failed to fetch notifications": "Хабарламаларды алу мүмкін болмады",
+		"Tickets are on sale":           "Билеттер сатылымда",
+
+		"Failed to decode watchlist":     "Тізімді өңдеу мүмкін болмады",
+		"Failed to decode notifications": "Хабарламаларды өңдеу мүмкін болмады",
+
+		"Showtimes for a movie from your watchlist are now available.": "«Көргім келеді» тізіміңіздегі фильмнің сеанстары пайда болды.",
+		"Open the movie page to choose a showtime:":                    "Сеансты таңдау үшін фильм бетін ашыңыз:",
+
+		// Возрастные ограничения
+		"Invalid date of birth. Use: YYYY-MM-DD":                     "Туған күн қате. Пайдаланыңыз: ЖЖЖЖ-АА-КК",
+		"Date of birth is already set. Contact support to change it": "Туған күн бұрыннан көрсетілген. Өзгерту үшін қолдау қызметіне жазыңыз",
+		"Invalid age policy. Use: block or warn":                     "Саясат қате. Пайдаланыңыз: block немесе warn",
+
+		"Failed to update user": "Пайдаланушыны жаңарту мүмкін болмады",
+
+		// Импорт фильмов
+		"Metadata file is required":      "Метадеректер файлы қажет",
+		"failed to fetch movie metadata": "Фильмдердің метадеректерін алу мүмкін болмады",
+
+		// Фильтры каталога фильмов
+		"Invalid sortBy. Use: createdAt, title, releaseDate, imdbRating, reviewRating, duration, popularity, or nextShowtime": "sortBy қате. Пайдаланыңыз: createdAt, title, releaseDate, imdbRating, reviewRating, duration, popularity немесе nextShowtime",
+		"Invalid genreMatch. Use: any or all": "genreMatch қате. Пайдаланыңыз: any немесе all",
+		"Invalid date. Use: YYYY-MM-DD":       "Күн қате. Пайдаланыңыз: ЖЖЖЖ-АА-КК",
+
+		// Сессии
+		"Invalid refresh token":                             "Refresh токен жарамсыз",
+		"Refresh token expired":                             "Refresh токеннің мерзімі өтті",
+		"Refresh token has been revoked":                    "Refresh токен кері қайтарылды",
+		"Refresh token reuse detected. Please log in again": "Refresh токен қайта пайдаланылды. Қайта кіріңіз",
+		"Failed to refresh token":                           "Токенді жаңарту мүмкін болмады",
+		"Token has been revoked":                            "Токен кері қайтарылды",
+		"Failed to verify token":                            "Токенді тексеру мүмкін болмады",
+		"Failed to log out":                                 "Шығу мүмкін болмады",
+
+		"Refresh token was just rotated. Retry with the latest token": "Refresh токен жаңа ғана ауыстырылды. Сұранысты жаңа токенмен қайталаңыз",
+
+		// Сброс пароля и подтверждение email
+		"Failed to reset password":                                    "Құпиясөзді қалпына келтіру мүмкін болмады",
+		"Invalid or expired reset link":                               "Құпиясөзді қалпына келтіру сілтемесі жарамсыз немесе ескірген",
+		"Failed to verify email":                                      "Email растау мүмкін болмады",
+		"Invalid or expired verification link":                        "Растау сілтемесі жарамсыз немесе ескірген",
+		"Email is already verified":                                   "Email бұрыннан расталған",
+		"Failed to send verification email":                           "Хатты жіберу мүмкін болмады",
+		"Verification email was sent recently. Try again in a minute": "Хат жақында жіберілді. Бір минуттан кейін қайталаңыз",
+
+		"Please confirm your email address first. Check your inbox or request a new link": "Алдымен email растаңыз. Поштаңызды тексеріңіз немесе жаңа сілтеме сұраңыз",
+		"Failed to verify account": "Аккаунтты тексеру мүмкін болмады",
+
+		// Письма
+		"Hello":              "Сәлеметсіз бе",
+		"Confirm your email": "Email растаңыз",
+		"Password reset":     "Құпиясөзді қалпына келтіру",
+		"Open the link to confirm your email address (valid for 48 hours):": "Email растау үшін сілтемені ашыңыз (48 сағат жарамды):",
+		"If you did not create an account, ignore this email.":              "Егер сіз тіркелмеген болсаңыз, бұл хатты елемеңіз.",
+		"Open the link to set a new password (valid for 1 hour):":           "Жаңа құпиясөз орнату үшін сілтемені ашыңыз (1 сағат жарамды):",
+		"If you did not request a password reset, ignore this email.":       "Егер сіз құпиясөзді қалпына келтіруді сұрамаған болсаңыз, бұл хатты елемеңіз.",
+
+		// Защита входа
+		"Too many failed login attempts. Try again later":                                                                 "Сәтсіз кіру әрекеттері тым көп. Кейінірек қайталаңыз",
+		"Too many failed login attempts. Sign-in is temporarily locked, check your email to unlock it or try again later": "Сәтсіз кіру әрекеттері тым көп. Кіру уақытша бұғатталды: хаттағы сілтеме арқылы бұғаттан шығарыңыз немесе кейінірек қайталаңыз",
+		"Failed to unlock account":                                         "Аккаунтты бұғаттан шығару мүмкін болмады",
+		"Invalid or expired unlock link":                                   "Бұғаттан шығару сілтемесі жарамсыз немесе ескірген",
+		"Failed to fetch audit log":                                        "Қауіпсіздік журналын алу мүмкін болмады",
+		"Failed to decode audit log":                                       "Қауіпсіздік журналын өңдеу мүмкін болмады",
+		"Failed to count audit entries":                                    "Журнал жазбаларын санау мүмкін болмады",
+		"Sign-in to your account has been locked":                          "Аккаунтқа кіру бұғатталды",
+		"We locked sign-in to your account after several failed attempts.": "Бірнеше сәтсіз әрекеттен кейін аккаунтыңызға кіруді бұғаттадық.",
+		"If it was you, open the link to unlock it (valid for 24 hours):":  "Егер бұл сіз болсаңыз, кіруді бұғаттан шығару үшін сілтемені ашыңыз (24 сағат жарамды):",
+		"If it was not you, we recommend resetting your password.":         "Егер бұл сіз болмасаңыз, құпиясөзді ауыстыруды ұсынамыз.",
+
+		// Двухфакторная аутентификация
+		"Two-factor authentication is required for your role. Set it up and sign in again": "Сіздің рөліңіз үшін екі факторлы аутентификация міндетті. Оны қосып, қайта кіріңіз",
+		"Two-factor authentication is mandatory for your role":                             "Сіздің рөліңіз үшін екі факторлы аутентификация міндетті",
+		"Two-factor authentication is already enabled":                                     "Екі факторлы аутентификация қосылып қойған",
+		"Two-factor authentication is not enabled":                                         "Екі факторлы аутентификация қосылмаған",
+		"Start two-factor authentication setup first":                                      "Алдымен екі факторлы аутентификацияны баптауды бастаңыз",
+		"Two-factor authentication setup has changed. Start again":                         "Екі факторлы аутентификация баптауы өзгерді. Қайта бастаңыз",
+		"Invalid authentication code":                                                      "Растау коды қате",
+		"Sign-in session expired. Please log in again":                                     "Кодты енгізу уақыты өтті. Қайта кіріңіз",
+		"Failed to start two-factor authentication":                                        "Екінші факторды тексеруді бастау мүмкін болмады",
+		"Failed to verify code":                                                            "Кодты тексеру мүмкін болмады",
+		"Failed to set up two-factor authentication":                                       "Екі факторлы аутентификацияны баптау мүмкін болмады",
+		"Failed to enable two-factor authentication":                                       "Екі факторлы аутентификацияны қосу мүмкін болмады",
+		"Failed to disable two-factor authentication":                                      "Екі факторлы аутентификацияны өшіру мүмкін болмады",
+		"Failed to generate recovery codes":                                                "Резервтік кодтарды жасау мүмкін болмады",
+
+		// Расписание: импорт и экспорт
+		"Schedule file is required (multipart field \"file\" or request body)":                 "Кесте файлы қажет (multipart \"file\" өрісі немесе сұраныс денесі)",
+		"Schedule file contains no rows":                                                       "Кесте файлында жолдар жоқ",
+		"Maximum 5000 rows per import":                                                         "Бір импортта 5000 жолдан артық болмайды",
+		"Showtimes were created concurrently in the same halls. Import rolled back, try again": "Сол залдарда бір мезгілде сеанстар жасалды. Импорт болдырылмады, қайталап көріңіз",
+		"failed to read CSV header":                                                            "CSV тақырыбын оқу мүмкін болмады",
+		"failed to parse JSON":                                                                 "JSON талдау мүмкін болмады",
+		"failed to check existing showtimes":                                                   "Бар сеанстарды тексеру мүмкін болмады",
+		"failed to save showtimes":                                                             "Сеанстарды сақтау мүмкін болмады",
+		"Invalid or missing 'from' date. Use: YYYY-MM-DD":                                      "'from' күні көрсетілмеген немесе қате. Пайдаланыңыз: ЖЖЖЖ-АА-КК",
+		"Invalid 'to' date. Use: YYYY-MM-DD":                                                   "'to' күні қате. Пайдаланыңыз: ЖЖЖЖ-АА-КК",
+		"'to' must not be before 'from'":                                                       "'to' күні 'from' күнінен ерте болмауы керек",
+		"Invalid format. Use: csv or json":                                                     "Пішім қате. Пайдаланыңыз: csv немесе json",
+		"Failed to export schedule":                                                            "Кестені экспорттау мүмкін болмады",
+		"Failed to write CSV":                                                                  "CSV жазу мүмкін болмады",
+
+		// Правила цен
+		"Invalid pricing rule ID":                                                        "Баға ережесінің ID қате",
+		"Pricing rule not found":                                                         "Баға ережесі табылмады",
+		"Name is required":                                                               "Атауы қажет",
+		"Multiplier must be between 0 and 10":                                            "Көбейткіш 0 мен 10 аралығында болуы керек",
+		"Surcharge must not be negative":                                                 "Үстеме ақы теріс болмауы керек",
+		"startHour must be 0-23 and endHour 0-24, and they must differ":                  "startHour 0-23, endHour 0-24 болуы керек және олар әртүрлі болуы тиіс",
+		"weekdays are required (0 = Sunday ... 6 = Saturday)":                            "weekdays қажет (0 = жексенбі ... 6 = сенбі)",
+		"formats are required, e.g. [\"3D\", \"IMAX\"]":                                  "formats қажет, мысалы [\"3D\", \"IMAX\"]",
+		"premiereDays must not be negative":                                              "premiereDays теріс болмауы керек",
+		"minOccupancy must be between 0 and 1":                                           "minOccupancy 0 мен 1 аралығында болуы керек",
+		"Invalid rule type. Use: time_of_day, weekday, format, premiere_week, occupancy": "Ереже түрі қате. Пайдаланыңыз: time_of_day, weekday, format, premiere_week, occupancy",
+		"Failed to load pricing rules":                                                   "Баға ережелерін жүктеу мүмкін болмады",
+		"Failed to fetch pricing rules":                                                  "Баға ережелерін жүктеу мүмкін болмады",
+		"Failed to decode pricing rules":                                                 "Баға ережелерін өңдеу мүмкін болмады",
+		"Failed to create pricing rule":                                                  "Баға ережесін жасау мүмкін болмады",
+		"Failed to update pricing rule":                                                  "Баға ережесін жаңарту мүмкін болмады",
+		"Failed to delete pricing rule":                                                  "Баға ережесін жою мүмкін болмады",
+
+		// Категории билетов и вход в зал
+		"Category code is required":                 "Санат коды қажет",
+		"discountPercent must be between 0 and 100": "discountPercent 0 мен 100 аралығында болуы керек",
+		"maxAge must not be negative":               "maxAge теріс болмауы керек",
+		"Failed to update ticket categories":        "Билет санаттарын жаңарту мүмкін болмады",
+		"Failed to check in booking":                "Брондау бойынша кіруді белгілеу мүмкін болмады",
+
+		// Аналитика
+		"Failed to aggregate popular movies":      "Танымал фильмдерді есептеу мүмкін болмады",
+		"Failed to aggregate cinema stats":        "Кинотеатрлар статистикасын есептеу мүмкін болмады",
+		"Failed to aggregate revenue":             "Түсімді есептеу мүмкін болмады",
+		"Failed to aggregate revenue by category": "Билет санаттары бойынша түсімді есептеу мүмкін болмады",
+		"Failed to aggregate pass revenue":        "Абонементтерден түскен түсімді есептеу мүмкін болмады",
+		"Failed to aggregate pass redemptions":    "Абонементтердің пайдаланылуын есептеу мүмкін болмады",
+		"Failed to aggregate watchlist":           "«Көргім келеді» тізімдерінің статистикасын есептеу мүмкін болмады",
+	},
+}
@@ -0,0 +1,77 @@
+package i18n
+
+// terms - справочник жанров, языков и форматов (ключ - значение в базе)
+var terms = map[string]map[string]string{
+	Russian: {
+		// Жанры
+		"Action":      "Боевик",
+		"Adventure":   "Приключения",
+		"Animation":   "Мультфильм",
+		"Biography":   "Биография",
+		"Comedy":      "Комедия",
+		"Crime":       "Криминал",
+		"Documentary": "Документальный",
+		"Drama":       "Драма",
+		"Family":      "Семейный",
+		"Fantasy":     "Фэнтези",
+		"History":     "История",
+		"Horror":      "Ужасы",
+		"Musical":     "Мюзикл",
+		"Mystery":     "Детектив",
+		"Romance":     "Мелодрама",
+		"Sci-Fi":      "Фантастика",
+		"Sport":       "Спорт",
+		"Thriller":    "Триллер",
+		"War":         "Военный",
+		"Western":     "Вестерн",
+
+		// Языки
+		"Kazakh":  "Казахский",
+		"Russian": "Русский",
+		"English": "Английский",
+
+		// Форматы и типы залов
+		"Standard": "Стандарт",
+		"VIP":      "VIP",
+		"IMAX":     "IMAX",
+		"4DX":      "4DX",
+		"2D":       "2D",
+		"3D":       "3D",
+	},
+	Kazakh: {
+		// Жанры
+		"Action":      "Экшн",
+		"Adventure":   "Шытырман оқиға",
+		"Animation":   "Мультфильм",
+		"Biography":   "Өмірбаян",
+		"Comedy":      "Комедия",
+		"Crime":       "Қылмыс",
+		"Documentary": "Деректі",
+		"Drama":       "Драма",
+		"Family":      "Отбасылық",
+		"Fantasy":     "Фэнтези",
+		"History":     "Тарихи",
+		"Horror":      "Қорқынышты",
+		"Musical":     "Мюзикл",
+		"Mystery":     "Детектив",
+		"Romance":     "Мелодрама",
+		"Sci-Fi":      "Ғылыми фантастика",
+		"Sport":       "Спорт",
+		"Thriller":    "Триллер",
+		"War":         "Соғыс",
+		"Western":     "Вестерн",
+
+		// Языки
+		"Kazakh":  "Қазақ тілі",
+		"Russian": "Орыс тілі",
+		"English": "Ағылшын тілі",
+
+		// Форматы и типы залов
+		"Standard": "Стандарт",
+		"VIP":      "VIP",
+		"IMAX":     "IMAX",
+		"4DX":      "4DX",
+		"2D":       "2D",
+		"3D":       "3D",
+	},
+}
@@ -0,0 +1,133 @@
+package mailer
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"mime"
+	"net"
+	"net/mail"
+	"net/smtp"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// Message - письмо (только текст)
+type Message struct {
+	To      string
+	Subject string
+	Body    string
+}
+
+// Mailer - способ отправки писем: SMTP в продакшене, файлы или консоль локально и в тестах
+type Mailer interface {
+	Send(ctx context.Context, msg Message) error
+}
+
+// SMTPMailer - отправка через SMTP сервер (STARTTLS, если сервер поддерживает)
+type SMTPMailer struct {
+	Host     string
+	Port     string
+	Username string // пустой - без авторизации
+	Password string
+	From     string
+}
+
+// Send - отправить письмо
+func (m SMTPMailer) Send(ctx context.Context, msg Message) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	var auth smtp.Auth
+	if m.Username != "" {
+		auth = smtp.PlainAuth("", m.Username, m.Password, m.Host)
+	}
+
+	// Конверт - только адреса (From может быть "Имя <адрес>")
+	from, err := mail.ParseAddress(m.From)
+	if err != nil {
+		return fmt.Errorf("invalid sender address: %w", err)
+	}
+	to, err := mail.ParseAddress(headerValue(msg.To))
+	if err != nil {
+		return fmt.Errorf("invalid recipient address: %w", err)
+	}
+
+	addr := net.JoinHostPort(m.Host, m.Port)
+	return smtp.SendMail(addr, auth, from.Address, []string{to.Address}, Render(m.From, msg))
+}
+
+// FileMailer - сохраняет письма в каталог как .eml (локальная разработка, тесты)
+type FileMailer struct {
+	Dir  string
+	From string
+}
+
+// Send - записать письмо в файл <время>-<получатель>.eml
+func (m FileMailer) Send(ctx context.Context, msg Message) error {
+	if err := os.MkdirAll(m.Dir, 0o755); err != nil {
+		return err
+	}
+	name := fmt.Sprintf("%s-%s.eml", time.Now().Format("20060102-150405.000000"), unsafeFileChars.ReplaceAllString(msg.To, "_"))
+	return os.WriteFile(filepath.Join(m.Dir, name), Render(m.From, msg), 0o644)
+}
+
+// ConsoleMailer - печатает письма (по умолчанию в stdout)
+type ConsoleMailer struct {
+	Out  io.Writer
+	From string
+}
+
+// Send - напечатать письмо
+func (m ConsoleMailer) Send(ctx context.Context, msg Message) error {
+	out := m.Out
+	if out == nil {
+		out = os.Stdout
+	}
+	_, err := fmt.Fprintf(out, "📧 ----- email -----\n%s\n📧 -------------------\n", Render(m.From, msg))
+	return err
+}
+
+var unsafeFileChars = regexp.MustCompile(`[^a-zA-Z0-9._@-]`)
+
+// headerValue - убрать переводы строк (защита от внедрения заголовков)
+func headerValue(s string) string {
+	return strings.NewReplacer("\r", "", "\n", "").Replace(strings.TrimSpace(s))
+}
+
+// Render - письмо в формате RFC 5322 (UTF-8, тема в MIME-кодировке)
+func Render(from string, msg Message) []byte {
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "From: %s\r\n", headerValue(from))
+	fmt.Fprintf(&buf, "To: %s\r\n", headerValue(msg.To))
+	fmt.Fprintf(&buf, "Subject: %s\r\n", mime.QEncoding.Encode("utf-8", headerValue(msg.Subject)))
+	fmt.Fprintf(&buf, "Date: %s\r\n", time.Now().Format(time.RFC1123Z))
+	buf.WriteString("MIME-Version: 1.0\r\n")
+	buf.WriteString("Content-Type: text/plain; charset=UTF-8\r\n")
+	buf.WriteString("Content-Transfer-Encoding: 8bit\r\n\r\n")
+	buf.WriteString(strings.ReplaceAll(strings.ReplaceAll(msg.Body, "\r\n", "\n"), "\n", "\r\n"))
+	buf.WriteString("\r\n")
+	return buf.Bytes()
+}
+
+// New - mailer по имени драйвера: "smtp", "file" или "console"
+func New(driver string, smtpMailer SMTPMailer, dir string) (Mailer, error) {
+	switch driver {
+	case "smtp":
+		if smtpMailer.Host == "" {
+			return nil, fmt.Errorf("SMTP host is required for the smtp mail driver")
+		}
+		return smtpMailer, nil
+	case "file":
+		return FileMailer{Dir: dir, From: smtpMailer.From}, nil
+	case "", "console":
+		return ConsoleMailer{From: smtpMailer.From}, nil
+	default:
+		return nil, fmt.Errorf("unknown mail driver %q (use smtp, file or console)", driver)
+	}
+}
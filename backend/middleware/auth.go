@@ -2,7 +2,9 @@ package middleware
 
 import (
 	"cinema-booking/utils"
+	"context"
 	"strings"
+	"time"
 
 	"github.com/gin-gonic/gin"
 )
@@ -36,10 +38,30 @@ func AuthMiddleware() gin.HandlerFunc {
 			return
 		}
 
-		// 4. Сохранить данные пользователя в контекст
+		// 4. Проверить, что токен не отозван (logout, повтор refresh токена)
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		revoked, err := utils.IsTokenRevoked(ctx, claims.ID)
+		if err != nil {
+			utils.ErrorResponse(c, 500, "Failed to verify token")
+			c.Abort()
+			return
+		}
+		if revoked {
+			utils.ErrorResponse(c, 401, "Token has been revoked")
+			c.Abort()
+			return
+		}
+
+		// 5. Сохранить данные пользователя в контекст
 		c.Set("userId", claims.UserID)
 		c.Set("userEmail", claims.Email)
 		c.Set("userRole", claims.Role)
+		c.Set("tokenId", claims.ID)
+		c.Set("mfa", claims.MFA)
+		if claims.ExpiresAt != nil {
+			c.Set("tokenExpiresAt", claims.ExpiresAt.Time)
+		}
 
 		// Продолжить выполнение
 		c.Next()
@@ -0,0 +1,43 @@
+package middleware
+
+import (
+	"cinema-booking/config"
+	"cinema-booking/utils"
+	"context"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// RequireVerifiedEmail - middleware: платежи (брони, пополнение, покупки) только с подтвержденным email.
+// Флаг читается из базы, а не из JWT: подтверждение действует сразу, без повторного входа.
+// Ставится после AuthMiddleware.
+func RequireVerifiedEmail() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userObjectID, err := primitive.ObjectIDFromHex(c.GetString("userId"))
+		if err != nil {
+			utils.ErrorResponse(c, 401, "Invalid or expired token")
+			c.Abort()
+			return
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+
+		count, err := config.GetCollection("users").CountDocuments(ctx, bson.M{"_id": userObjectID, "emailVerified": true})
+		if err != nil {
+			utils.ErrorResponse(c, 500, "Failed to verify account")
+			c.Abort()
+			return
+		}
+		if count == 0 {
+			utils.ErrorResponse(c, 403, "Please confirm your email address first. Check your inbox or request a new link")
+			c.Abort()
+			return
+		}
+
+		c.Next()
+	}
+}
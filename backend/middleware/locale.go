@@ -0,0 +1,20 @@
+package middleware
+
+import (
+	"cinema-booking/i18n"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Localization - определить язык ответа по ?lang= или Accept-Language (kk, ru, en)
+func Localization() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		lang := i18n.Negotiate(c.Query("lang"), c.GetHeader("Accept-Language"))
+		c.Set(i18n.ContextKey, lang)
+
+		c.Header("Content-Language", lang)
+		c.Writer.Header().Add("Vary", "Accept-Language")
+
+		c.Next()
+	}
+}
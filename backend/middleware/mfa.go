@@ -0,0 +1,21 @@
+package middleware
+
+import (
+	"cinema-booking/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+// RequireMFA - middleware: админы и менеджеры кинотеатров должны войти с кодом TOTP
+// (claim mfa в JWT). Ставится после AuthMiddleware.
+func RequireMFA() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if utils.RoleRequiresMFA(c.GetString("userRole")) && !c.GetBool("mfa") {
+			utils.ErrorResponse(c, 403, "Two-factor authentication is required for your role. Set it up and sign in again")
+			c.Abort()
+			return
+		}
+
+		c.Next()
+	}
+}
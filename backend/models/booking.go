@@ -11,25 +11,61 @@ type Booking struct {
 	BookingNumber string             `bson:"bookingNumber" json:"bookingNumber"` // "BK-20260201-001234"
 	UserID        primitive.ObjectID `bson:"userId" json:"userId"`
 	ShowtimeID    primitive.ObjectID `bson:"showtimeId" json:"showtimeId"`
-	Seats         []BookingSeat      `bson:"seats" json:"seats"` // Embedded
+	Seats         []BookingSeat      `bson:"seats" json:"seats"`                             // Embedded
+	Subtotal      float64            `bson:"subtotal,omitempty" json:"subtotal,omitempty"`   // сумма до скидок
+	Discounts     []BookingDiscount  `bson:"discounts,omitempty" json:"discounts,omitempty"` // Embedded
 	TotalAmount   float64            `bson:"totalAmount" json:"totalAmount"`
 	Status        string             `bson:"status" json:"status"`   // "pending", "confirmed", "cancelled", "expired"
 	Payment       Payment            `bson:"payment" json:"payment"` // Embedded
 	QRCode        string             `bson:"qrCode" json:"qrCode"`
-	ExpiresAt     time.Time          `bson:"expiresAt" json:"expiresAt"`
-	CreatedAt     time.Time          `bson:"createdAt" json:"createdAt"`
-	UpdatedAt     time.Time          `bson:"updatedAt" json:"updatedAt"`
+	// Заказ из бара (входит в TotalAmount)
+	Concessions      []BookingConcession `bson:"concessions,omitempty" json:"concessions,omitempty"`
+	ConcessionsTotal float64             `bson:"concessionsTotal,omitempty" json:"concessionsTotal,omitempty"`
+	PickupTime       *time.Time          `bson:"pickupTime,omitempty" json:"pickupTime,omitempty"`
+	ConcessionStatus string              `bson:"concessionStatus,omitempty" json:"concessionStatus,omitempty"` // "pending", "preparing", "ready", "collected"
+	PointsEarned     int                 `bson:"pointsEarned,omitempty" json:"pointsEarned,omitempty"`         // баллы лояльности за бронь
+	CheckedInAt      *time.Time          `bson:"checkedInAt,omitempty" json:"checkedInAt,omitempty"`
+	AgeCheck         string              `bson:"ageCheck,omitempty" json:"ageCheck,omitempty"`   // "underage", "unknown_age": возраст не подтвержден, проверить документы
+	ExpiresAt        *time.Time          `bson:"expiresAt,omitempty" json:"expiresAt,omitempty"` // срок оплаты; только у неоплаченных броней
+	CreatedAt        time.Time           `bson:"createdAt" json:"createdAt"`
+	UpdatedAt        time.Time           `bson:"updatedAt" json:"updatedAt"`
 }
 
 type BookingSeat struct {
-	Row    string  `bson:"row" json:"row"`
-	Number int     `bson:"number" json:"number"`
-	Price  float64 `bson:"price" json:"price"`
+	Row      string  `bson:"row" json:"row"`
+	Number   int     `bson:"number" json:"number"`
+	Price    float64 `bson:"price" json:"price"`
+	Category string  `bson:"category,omitempty" json:"category,omitempty"` // "adult", "child", "student", "senior", "pensioner"
+	Discount float64 `bson:"discount,omitempty" json:"discount,omitempty"` // скидка категории, KZT
+	// Льготный билет или возрастное ограничение: проверить документ при входе
+	RequiresVerification bool `bson:"requiresVerification,omitempty" json:"requiresVerification,omitempty"`
+	Verified             bool `bson:"verified,omitempty" json:"verified,omitempty"`
+}
+
+// BookingDiscount - скидка, примененная к брони
+type BookingDiscount struct {
+	Type          string             `bson:"type" json:"type"` // "promo", "pass"
+	Code          string             `bson:"code,omitempty" json:"code,omitempty"`
+	PromoCodeID   primitive.ObjectID `bson:"promoCodeId,omitempty" json:"promoCodeId,omitempty"`
+	DiscountType  string             `bson:"discountType,omitempty" json:"discountType,omitempty"` // "percentage", "fixed"
+	DiscountValue float64            `bson:"discountValue,omitempty" json:"discountValue,omitempty"`
+	Amount        float64            `bson:"amount" json:"amount"` // KZT
 }
 
 type Payment struct {
-	Method        string    `bson:"method" json:"method"` // "card", "wallet", "cash"
+	Method        string    `bson:"method" json:"method"` // "card", "wallet", "cash", "points", "pass"
 	TransactionID string    `bson:"transactionId" json:"transactionId"`
 	PaidAt        time.Time `bson:"paidAt,omitempty" json:"paidAt,omitempty"`
 	Status        string    `bson:"status" json:"status"` // "pending", "completed", "failed"
+
+	// Частичная оплата подарочной картой (остаток оплачивается методом Method)
+	GiftCardID     primitive.ObjectID `bson:"giftCardId,omitempty" json:"giftCardId,omitempty"`
+	GiftCardCode   string             `bson:"giftCardCode,omitempty" json:"giftCardCode,omitempty"`
+	GiftCardAmount float64            `bson:"giftCardAmount,omitempty" json:"giftCardAmount,omitempty"`
+
+	// Оплата баллами лояльности (1 балл = 1 KZT)
+	LoyaltyPoints int `bson:"loyaltyPoints,omitempty" json:"loyaltyPoints,omitempty"`
+
+	// Оплата абонементом (Method = "pass")
+	SubscriptionID primitive.ObjectID `bson:"subscriptionId,omitempty" json:"subscriptionId,omitempty"`
 }
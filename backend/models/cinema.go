@@ -6,6 +6,12 @@ import (
 	"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
+// Политики возрастных ограничений кинотеатра
+const (
+	AgePolicyBlock = "block"
+	AgePolicyWarn  = "warn"
+)
+
 type Cinema struct {
 	ID           primitive.ObjectID   `bson:"_id,omitempty" json:"id"`
 	Name         string               `bson:"name" json:"name" validate:"required"`
@@ -16,8 +22,47 @@ type Cinema struct {
 	HallIDs      []primitive.ObjectID `bson:"hallIds" json:"hallIds"`       // Referenced
 	Rating       float64              `bson:"rating" json:"rating"`
 	TotalReviews int                  `bson:"totalReviews" json:"totalReviews"`
+	SubRatings   CinemaSubRatings     `bson:"subRatings" json:"subRatings"`
 	Images       []string             `bson:"images" json:"images"` // пути к файлам
-	CreatedAt    time.Time            `bson:"createdAt" json:"createdAt"`
+	// Категории билетов со скидками (пусто = категории по умолчанию)
+	TicketCategories []TicketCategory `bson:"ticketCategories,omitempty" json:"ticketCategories,omitempty"`
+	AgePolicy        string           `bson:"agePolicy,omitempty" json:"agePolicy,omitempty"` // "block" - отказ в брони младше возраста фильма, "warn" (по умолчанию) - проверка документа
+	CreatedAt        time.Time        `bson:"createdAt" json:"createdAt"`
+}
+
+// TicketCategory - категория билета (детский, студенческий и т.д.)
+type TicketCategory struct {
+	Code                 string  `bson:"code" json:"code"` // "adult", "child", "student", "senior", "pensioner"
+	Name                 string  `bson:"name" json:"name"`
+	DiscountPercent      float64 `bson:"discountPercent" json:"discountPercent"`           // 0-100
+	RequiresVerification bool    `bson:"requiresVerification" json:"requiresVerification"` // проверить документ при входе
+	MaxAge               int     `bson:"maxAge,omitempty" json:"maxAge,omitempty"`         // для детских: максимальный возраст зрителя
+	IsActive             bool    `bson:"isActive" json:"isActive"`
+}
+
+// CinemaSubRatings - средние оценки по критериям (1-5)
+type CinemaSubRatings struct {
+	Cleanliness float64 `bson:"cleanliness" json:"cleanliness"`
+	Sound       float64 `bson:"sound" json:"sound"`
+	Seats       float64 `bson:"seats" json:"seats"`
+	Staff       float64 `bson:"staff" json:"staff"`
+	Count       int     `bson:"count" json:"count"` // отзывов с оценками по критериям
+}
+
+// CinemaReview - отзыв зрителя о кинотеатре (коллекция cinema_reviews)
+type CinemaReview struct {
+	ID          primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	CinemaID    primitive.ObjectID `bson:"cinemaId" json:"cinemaId"`
+	UserID      primitive.ObjectID `bson:"userId" json:"userId"`
+	UserName    string             `bson:"userName" json:"userName"`
+	Rating      float64            `bson:"rating" json:"rating"` // общая оценка 1-5
+	Cleanliness float64            `bson:"cleanliness" json:"cleanliness"`
+	Sound       float64            `bson:"sound" json:"sound"`
+	Seats       float64            `bson:"seats" json:"seats"`
+	Staff       float64            `bson:"staff" json:"staff"`
+	Comment     string             `bson:"comment" json:"comment"`
+	CreatedAt   time.Time          `bson:"createdAt" json:"createdAt"`
+	UpdatedAt   time.Time          `bson:"updatedAt" json:"updatedAt"`
 }
 
 type Location struct {
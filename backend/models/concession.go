@@ -0,0 +1,41 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// ConcessionItem - позиция бара кинотеатра (попкорн, напитки, комбо)
+type ConcessionItem struct {
+	ID          primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	CinemaID    primitive.ObjectID `bson:"cinemaId" json:"cinemaId"`
+	Name        string             `bson:"name" json:"name"`
+	Category    string             `bson:"category" json:"category"` // "snack", "drink", "combo"
+	Description string             `bson:"description,omitempty" json:"description,omitempty"`
+	Price       float64            `bson:"price" json:"price"`
+	// Учет остатков: если TrackStock = false, позиция не ограничена
+	TrackStock bool `bson:"trackStock" json:"trackStock"`
+	Stock      int  `bson:"stock" json:"stock"`
+	// Состав комбо: при заказе списываются остатки входящих позиций
+	ComboItems []ComboItem `bson:"comboItems,omitempty" json:"comboItems,omitempty"`
+	IsActive   bool        `bson:"isActive" json:"isActive"`
+	CreatedAt  time.Time   `bson:"createdAt" json:"createdAt"`
+	UpdatedAt  time.Time   `bson:"updatedAt" json:"updatedAt"`
+}
+
+// ComboItem - позиция в составе комбо
+type ComboItem struct {
+	ItemID   primitive.ObjectID `bson:"itemId" json:"itemId"`
+	Quantity int                `bson:"quantity" json:"quantity"`
+}
+
+// BookingConcession - позиция бара в брони (Embedded в Booking)
+type BookingConcession struct {
+	ItemID     primitive.ObjectID `bson:"itemId" json:"itemId"`
+	Name       string             `bson:"name" json:"name"`
+	Quantity   int                `bson:"quantity" json:"quantity"`
+	UnitPrice  float64            `bson:"unitPrice" json:"unitPrice"`
+	Total      float64            `bson:"total" json:"total"`
+	ComboItems []ComboItem        `bson:"comboItems,omitempty" json:"comboItems,omitempty"` // для возврата остатков
+}
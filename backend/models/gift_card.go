@@ -0,0 +1,26 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type GiftCard struct {
+	ID             primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	Code           string             `bson:"code" json:"code"` // "GC-XXXX-XXXX-XXXX"
+	InitialAmount  float64            `bson:"initialAmount" json:"initialAmount"`
+	Balance        float64            `bson:"balance" json:"balance"`
+	Currency       string             `bson:"currency" json:"currency"` // "KZT"
+	Status         string             `bson:"status" json:"status"`     // "active", "redeemed", "disabled"
+	Source         string             `bson:"source" json:"source"`     // "purchase", "admin"
+	Batch          string             `bson:"batch,omitempty" json:"batch,omitempty"`
+	PurchasedBy    primitive.ObjectID `bson:"purchasedBy,omitempty" json:"purchasedBy,omitempty"`
+	RecipientName  string             `bson:"recipientName,omitempty" json:"recipientName,omitempty"`
+	RecipientEmail string             `bson:"recipientEmail,omitempty" json:"recipientEmail,omitempty"`
+	Message        string             `bson:"message,omitempty" json:"message,omitempty"`
+	RedeemedBy     primitive.ObjectID `bson:"redeemedBy,omitempty" json:"redeemedBy,omitempty"` // кто зачислил карту на кошелек
+	ExpiresAt      time.Time          `bson:"expiresAt" json:"expiresAt"`
+	CreatedAt      time.Time          `bson:"createdAt" json:"createdAt"`
+	UpdatedAt      time.Time          `bson:"updatedAt" json:"updatedAt"`
+}
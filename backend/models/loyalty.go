@@ -0,0 +1,34 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// LoyaltyAccount - баллы программы лояльности (Embedded в User)
+type LoyaltyAccount struct {
+	Points       int `bson:"points" json:"points"`             // текущий баланс (1 балл = 1 KZT)
+	LifetimeEarn int `bson:"lifetimeEarn" json:"lifetimeEarn"` // всего начислено
+}
+
+// LoyaltyTransaction - история начислений и списаний баллов
+type LoyaltyTransaction struct {
+	ID          primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	UserID      primitive.ObjectID `bson:"userId" json:"userId"`
+	Type        string             `bson:"type" json:"type"`     // "earn", "reverse", "redeem", "refund"
+	Points      int                `bson:"points" json:"points"` // + начисление, - списание
+	BookingID   primitive.ObjectID `bson:"bookingId,omitempty" json:"bookingId,omitempty"`
+	Description string             `bson:"description" json:"description"`
+	CreatedAt   time.Time          `bson:"createdAt" json:"createdAt"`
+}
+
+// LoyaltyRate - ставка начисления баллов для кинотеатра и/или формата
+type LoyaltyRate struct {
+	ID           primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	CinemaID     primitive.ObjectID `bson:"cinemaId,omitempty" json:"cinemaId,omitempty"` // пусто = все кинотеатры
+	Format       string             `bson:"format,omitempty" json:"format,omitempty"`     // пусто = все форматы
+	PointsPer100 float64            `bson:"pointsPer100" json:"pointsPer100"`             // баллов за каждые 100 KZT
+	IsActive     bool               `bson:"isActive" json:"isActive"`
+	CreatedAt    time.Time          `bson:"createdAt" json:"createdAt"`
+}
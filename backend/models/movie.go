@@ -8,31 +8,63 @@ import (
 
 type Movie struct {
 	ID             primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	ExternalID     string             `bson:"externalId,omitempty" json:"externalId,omitempty"`   // "imdb:tt1375666" или "tmdb:27205" (импорт метаданных)
+	ExternalIDs    []string           `bson:"externalIds,omitempty" json:"externalIds,omitempty"` // все известные ID источников (imdb и tmdb)
 	Title          string             `bson:"title" json:"title" validate:"required"`
 	TitleKz        string             `bson:"titleKz" json:"titleKz"`
 	TitleRu        string             `bson:"titleRu" json:"titleRu"`
 	Description    string             `bson:"description" json:"description"`
+	DescriptionKz  string             `bson:"descriptionKz,omitempty" json:"descriptionKz,omitempty"`
+	DescriptionRu  string             `bson:"descriptionRu,omitempty" json:"descriptionRu,omitempty"`
 	Director       string             `bson:"director" json:"director"`
 	Cast           []string           `bson:"cast" json:"cast"`
+	Credits        []MovieCredit      `bson:"credits,omitempty" json:"credits,omitempty"` // ссылки на people
 	Genres         []string           `bson:"genres" json:"genres"`
 	Duration       int                `bson:"duration" json:"duration"` // minutes
 	ReleaseDate    time.Time          `bson:"releaseDate" json:"releaseDate"`
 	Rating         string             `bson:"rating" json:"rating"` // "G", "PG", "PG-13", "R"
 	IMDBRating     float64            `bson:"imdbRating" json:"imdbRating"`
-	Language       []string           `bson:"language" json:"language"` // ["Kazakh", "Russian", "English"]
+	TMDbRating     float64            `bson:"tmdbRating,omitempty" json:"tmdbRating,omitempty"` // vote_average TMDb (импорт)
+	Language       []string           `bson:"language" json:"language"`                         // ["Kazakh", "Russian", "English"]
 	Subtitles      []string           `bson:"subtitles" json:"subtitles"`
 	PosterFileID   primitive.ObjectID `bson:"posterFileId,omitempty" json:"posterFileId,omitempty"` // GridFS
 	PosterURL      string             `bson:"posterUrl,omitempty" json:"posterUrl,omitempty"`
 	TrailerFileID  primitive.ObjectID `bson:"trailerFileId,omitempty" json:"trailerFileId,omitempty"` // GridFS
 	IsActive       bool               `bson:"isActive" json:"isActive"`
+	ReleasedAt     *time.Time         `bson:"releasedAt,omitempty" json:"releasedAt,omitempty"` // активирован в день релиза (UpdateMovieStatuses)
+	DeletedAt      *time.Time         `bson:"deletedAt,omitempty" json:"deletedAt,omitempty"`   // удален админом (soft delete)
 	AgeRestriction int                `bson:"ageRestriction" json:"ageRestriction"`
-	Reviews        []Review           `bson:"reviews" json:"reviews"` // Embedded
+	ReviewRating   float64            `bson:"reviewRating" json:"reviewRating"` // средняя оценка зрителей (1-10)
+	ReviewCount    int                `bson:"reviewCount" json:"reviewCount"`   // отзывы хранятся в movie_reviews
 	CreatedAt      time.Time          `bson:"createdAt" json:"createdAt"`
+	// Только в ответе API: переводы на язык запроса, исходные поля не меняются
+	Localized *MovieLocalization `bson:"-" json:"localized,omitempty"`
 }
 
-type Review struct {
-	UserID    primitive.ObjectID `bson:"userId" json:"userId"`
-	Rating    float64            `bson:"rating" json:"rating"` // 1-10
-	Comment   string             `bson:"comment" json:"comment"`
-	CreatedAt time.Time          `bson:"createdAt" json:"createdAt"`
+// MovieLocalization - название, описание и термины фильма на языке запроса
+type MovieLocalization struct {
+	Lang        string   `json:"lang"`
+	Title       string   `json:"title"`
+	Description string   `json:"description"`
+	Genres      []string `json:"genres"`
+	Language    []string `json:"language"`
+	Subtitles   []string `json:"subtitles"`
+}
+
+// MovieReview - отзыв зрителя о фильме (отдельная коллекция movie_reviews)
+type MovieReview struct {
+	ID       primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	MovieID  primitive.ObjectID `bson:"movieId" json:"movieId"`
+	UserID   primitive.ObjectID `bson:"userId" json:"userId"`
+	UserName string             `bson:"userName" json:"userName"`
+	Rating   float64            `bson:"rating" json:"rating"` // 1-10
+	Comment  string             `bson:"comment" json:"comment"`
+	Status   string             `bson:"status" json:"status"` // "published", "hidden"
+	// Модерация
+	Flagged     bool               `bson:"flagged,omitempty" json:"flagged,omitempty"`
+	FlagReason  string             `bson:"flagReason,omitempty" json:"flagReason,omitempty"`
+	ModeratedBy primitive.ObjectID `bson:"moderatedBy,omitempty" json:"moderatedBy,omitempty"`
+	ModeratedAt *time.Time         `bson:"moderatedAt,omitempty" json:"moderatedAt,omitempty"`
+	CreatedAt   time.Time          `bson:"createdAt" json:"createdAt"`
+	UpdatedAt   time.Time          `bson:"updatedAt" json:"updatedAt"`
 }
@@ -0,0 +1,26 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Типы уведомлений
+const (
+	NotificationTicketsOnSale = "tickets_on_sale" // появились сеансы фильма из watchlist
+)
+
+// Notification - уведомление пользователя (коллекция notifications).
+// Текст собирается при выдаче на языке запроса, в базе - только данные.
+type Notification struct {
+	ID        primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	UserID    primitive.ObjectID `bson:"userId" json:"userId"`
+	Type      string             `bson:"type" json:"type"`
+	MovieID   primitive.ObjectID `bson:"movieId,omitempty" json:"movieId,omitempty"`
+	City      string             `bson:"city,omitempty" json:"city,omitempty"`
+	Status    string             `bson:"status" json:"status"` // "pending" (ждет отправки письма), "sent"
+	SentAt    *time.Time         `bson:"sentAt,omitempty" json:"sentAt,omitempty"`
+	ReadAt    *time.Time         `bson:"readAt,omitempty" json:"readAt,omitempty"`
+	CreatedAt time.Time          `bson:"createdAt" json:"createdAt"`
+}
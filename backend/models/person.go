@@ -0,0 +1,37 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Роли персоны в фильме
+const (
+	PersonRoleDirector = "director"
+	PersonRoleActor    = "actor"
+)
+
+// Person - режиссер или актер (коллекция people)
+type Person struct {
+	ID     primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	Name   string             `bson:"name" json:"name"` // оригинальное написание (латиница)
+	NameRu string             `bson:"nameRu,omitempty" json:"nameRu,omitempty"`
+	NameKz string             `bson:"nameKz,omitempty" json:"nameKz,omitempty"`
+	// Нормализованное имя для сопоставления строк Director/Cast (не уникально: бывают однофамильцы)
+	NameKey     string             `bson:"nameKey" json:"-"`
+	Roles       []string           `bson:"roles" json:"roles"` // "director", "actor"
+	Bio         string             `bson:"bio,omitempty" json:"bio,omitempty"`
+	PhotoFileID primitive.ObjectID `bson:"photoFileId,omitempty" json:"photoFileId,omitempty"` // GridFS
+	PhotoURL    string             `bson:"photoUrl,omitempty" json:"photoUrl,omitempty"`
+	CreatedAt   time.Time          `bson:"createdAt" json:"createdAt"`
+	UpdatedAt   time.Time          `bson:"updatedAt" json:"updatedAt"`
+}
+
+// MovieCredit - участие персоны в фильме (Embedded в Movie)
+type MovieCredit struct {
+	PersonID  primitive.ObjectID `bson:"personId" json:"personId"`
+	Name      string             `bson:"name" json:"name"` // денормализовано для списков
+	Role      string             `bson:"role" json:"role"`
+	Character string             `bson:"character,omitempty" json:"character,omitempty"`
+}
@@ -0,0 +1,38 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Типы правил динамического ценообразования
+const (
+	PricingRuleTimeOfDay    = "time_of_day"   // время начала сеанса
+	PricingRuleWeekday      = "weekday"       // день недели
+	PricingRuleFormat       = "format"        // формат сеанса или тип зала (3D, IMAX, 4DX)
+	PricingRulePremiereWeek = "premiere_week" // первые дни после релиза
+	PricingRuleOccupancy    = "occupancy"     // заполненность зала
+)
+
+type PricingRule struct {
+	ID         primitive.ObjectID   `bson:"_id,omitempty" json:"id"`
+	Name       string               `bson:"name" json:"name"`
+	Type       string               `bson:"type" json:"type"`                               // см. константы PricingRule*
+	Multiplier float64              `bson:"multiplier" json:"multiplier"`                   // 1.2 = +20%
+	Surcharge  float64              `bson:"surcharge" json:"surcharge"`                     // фиксированная надбавка за место, KZT
+	CinemaIDs  []primitive.ObjectID `bson:"cinemaIds,omitempty" json:"cinemaIds,omitempty"` // пусто = все кинотеатры
+	Priority   int                  `bson:"priority" json:"priority"`                       // порядок применения (меньше - раньше)
+	IsActive   bool                 `bson:"isActive" json:"isActive"`
+
+	// Условия (используются в зависимости от типа)
+	StartHour    int      `bson:"startHour,omitempty" json:"startHour,omitempty"`       // time_of_day: [startHour, endHour)
+	EndHour      int      `bson:"endHour,omitempty" json:"endHour,omitempty"`           // если endHour < startHour - через полночь
+	Weekdays     []int    `bson:"weekdays,omitempty" json:"weekdays,omitempty"`         // weekday: 0 = воскресенье ... 6 = суббота
+	Formats      []string `bson:"formats,omitempty" json:"formats,omitempty"`           // format: ["3D", "IMAX", "4DX"]
+	PremiereDays int      `bson:"premiereDays,omitempty" json:"premiereDays,omitempty"` // premiere_week: по умолчанию 7
+	MinOccupancy float64  `bson:"minOccupancy,omitempty" json:"minOccupancy,omitempty"` // occupancy: 0.8 = от 80% занятых мест
+
+	CreatedAt time.Time `bson:"createdAt" json:"createdAt"`
+	UpdatedAt time.Time `bson:"updatedAt" json:"updatedAt"`
+}
@@ -0,0 +1,56 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type PromoCode struct {
+	ID            primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	Code          string             `bson:"code" json:"code"` // "SUMMER25" (всегда в верхнем регистре)
+	Description   string             `bson:"description" json:"description"`
+	Campaign      string             `bson:"campaign,omitempty" json:"campaign,omitempty"`       // партнерская кампания (пакетная генерация)
+	DiscountType  string             `bson:"discountType" json:"discountType"`                   // "percentage", "fixed"
+	DiscountValue float64            `bson:"discountValue" json:"discountValue"`                 // 25 = 25% или 25 KZT
+	MaxDiscount   float64            `bson:"maxDiscount,omitempty" json:"maxDiscount,omitempty"` // потолок скидки для "percentage"
+	ValidFrom     time.Time          `bson:"validFrom" json:"validFrom"`
+	ValidUntil    time.Time          `bson:"validUntil" json:"validUntil"`
+	UsageLimit    int                `bson:"usageLimit" json:"usageLimit"`     // всего использований (0 = без ограничений)
+	PerUserLimit  int                `bson:"perUserLimit" json:"perUserLimit"` // на пользователя (0 = без ограничений)
+	UsedCount     int                `bson:"usedCount" json:"usedCount"`
+
+	// Ограничения (пусто = без ограничений)
+	MovieIDs  []primitive.ObjectID `bson:"movieIds,omitempty" json:"movieIds,omitempty"`
+	CinemaIDs []primitive.ObjectID `bson:"cinemaIds,omitempty" json:"cinemaIds,omitempty"`
+	Formats   []string             `bson:"formats,omitempty" json:"formats,omitempty"`   // ["2D", "IMAX"]
+	Weekdays  []int                `bson:"weekdays,omitempty" json:"weekdays,omitempty"` // 0 = воскресенье ... 6 = суббота
+	MinSeats  int                  `bson:"minSeats,omitempty" json:"minSeats,omitempty"`
+
+	IsActive  bool      `bson:"isActive" json:"isActive"`
+	CreatedAt time.Time `bson:"createdAt" json:"createdAt"`
+	UpdatedAt time.Time `bson:"updatedAt" json:"updatedAt"`
+}
+
+// PromoRedemption - использование промокода в брони
+type PromoRedemption struct {
+	ID          primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	PromoCodeID primitive.ObjectID `bson:"promoCodeId" json:"promoCodeId"`
+	Code        string             `bson:"code" json:"code"`
+	UserID      primitive.ObjectID `bson:"userId" json:"userId"`
+	BookingID   primitive.ObjectID `bson:"bookingId" json:"bookingId"`
+	Discount    float64            `bson:"discount" json:"discount"`
+	Status      string             `bson:"status" json:"status"` // "active", "returned"
+	CreatedAt   time.Time          `bson:"createdAt" json:"createdAt"`
+	UpdatedAt   time.Time          `bson:"updatedAt" json:"updatedAt"`
+}
+
+// PromoUserUsage - сколько раз пользователь использовал промокод (коллекция promo_user_usage).
+// Увеличивается атомарно до создания брони, уменьшается при отмене.
+type PromoUserUsage struct {
+	ID          string             `bson:"_id" json:"id"` // "<promoCodeId>:<userId>"
+	PromoCodeID primitive.ObjectID `bson:"promoCodeId" json:"promoCodeId"`
+	UserID      primitive.ObjectID `bson:"userId" json:"userId"`
+	Used        int                `bson:"used" json:"used"`
+	UpdatedAt   time.Time          `bson:"updatedAt" json:"updatedAt"`
+}
@@ -0,0 +1,40 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Причины рекомендации
+const (
+	ReasonSimilar  = "similar"  // фильм смотрели те же зрители, что и ваш фильм
+	ReasonGenre    = "genre"    // любимый жанр
+	ReasonPerson   = "person"   // режиссер или актер из просмотренных фильмов
+	ReasonFormat   = "format"   // есть сеансы в любимом формате
+	ReasonLanguage = "language" // есть сеансы на любимом языке
+	ReasonPopular  = "popular"  // для пользователей без истории
+)
+
+// UserRecommendations - рассчитанные рекомендации пользователя (коллекция recommendations).
+// Пересчитываются пакетно (go run ./cmd compute-recommendations), API только читает кэш.
+type UserRecommendations struct {
+	ID         primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	UserID     primitive.ObjectID `bson:"userId" json:"userId"`
+	Items      []RecommendedMovie `bson:"items" json:"items"`
+	ComputedAt time.Time          `bson:"computedAt" json:"computedAt"`
+}
+
+// RecommendedMovie - фильм с оценкой и объяснением
+type RecommendedMovie struct {
+	MovieID primitive.ObjectID     `bson:"movieId" json:"movieId"`
+	Score   float64                `bson:"score" json:"score"`
+	Reasons []RecommendationReason `bson:"reasons" json:"reasons"` // от самой весомой
+}
+
+// RecommendationReason - почему фильм рекомендован
+type RecommendationReason struct {
+	Type    string             `bson:"type" json:"type"`
+	MovieID primitive.ObjectID `bson:"movieId,omitempty" json:"movieId,omitempty"` // для "similar": просмотренный фильм
+	Value   string             `bson:"value,omitempty" json:"value,omitempty"`     // название фильма, жанр, имя, формат или язык
+}
@@ -0,0 +1,40 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Типы записей журнала безопасности
+const (
+	AuditAccountLocked   = "account_locked"   // превышен порог неудачных входов в аккаунт
+	AuditIPLocked        = "ip_locked"        // превышен порог неудачных входов с IP
+	AuditAccountUnlocked = "account_unlocked" // по ссылке из письма или админом
+	AuditMFAEnabled      = "mfa_enabled"
+	AuditMFADisabled     = "mfa_disabled"           // пользователем или сброс админом
+	AuditMFARecoveryUsed = "mfa_recovery_code_used" // вход по резервному коду
+)
+
+// AuditEntry - запись журнала безопасности (коллекция audit_log)
+type AuditEntry struct {
+	ID        primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	Type      string             `bson:"type" json:"type"`
+	UserID    primitive.ObjectID `bson:"userId,omitempty" json:"userId,omitempty"`
+	ActorID   primitive.ObjectID `bson:"actorId,omitempty" json:"actorId,omitempty"` // админ, выполнивший действие
+	Email     string             `bson:"email,omitempty" json:"email,omitempty"`
+	IP        string             `bson:"ip,omitempty" json:"ip,omitempty"`
+	Details   string             `bson:"details,omitempty" json:"details,omitempty"`
+	CreatedAt time.Time          `bson:"createdAt" json:"createdAt"`
+}
+
+// LoginAttempts - неудачные попытки входа по аккаунту или IP (коллекция login_attempts).
+// Счетчик сбрасывается после часа без ошибок (TTL по expiresAt) или успешного входа.
+type LoginAttempts struct {
+	ID            string     `bson:"_id" json:"id"` // "account:<email>" или "ip:<адрес>"
+	Failures      int        `bson:"failures" json:"failures"`
+	LastFailureAt time.Time  `bson:"lastFailureAt" json:"lastFailureAt"`
+	LockedUntil   *time.Time `bson:"lockedUntil,omitempty" json:"lockedUntil,omitempty"`
+	Lockouts      int        `bson:"lockouts" json:"lockouts"`
+	ExpiresAt     time.Time  `bson:"expiresAt" json:"expiresAt"`
+}
@@ -0,0 +1,56 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// RefreshToken - refresh токен устройства (коллекция refresh_tokens).
+// Хранится только SHA-256 хеш. При каждом обновлении токен заменяется новым из того же
+// семейства (FamilyID); повторное использование замененного токена отзывает все семейство.
+type RefreshToken struct {
+	ID            primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	UserID        primitive.ObjectID `bson:"userId" json:"userId"`
+	FamilyID      primitive.ObjectID `bson:"familyId" json:"familyId"` // одна сессия входа
+	TokenHash     string             `bson:"tokenHash" json:"-"`
+	DeviceID      string             `bson:"deviceId,omitempty" json:"deviceId,omitempty"`
+	UserAgent     string             `bson:"userAgent,omitempty" json:"userAgent,omitempty"`
+	IP            string             `bson:"ip,omitempty" json:"ip,omitempty"`
+	AccessTokenID string             `bson:"accessTokenId" json:"-"` // jti access токена, выданного вместе с этим
+	AccessExpires time.Time          `bson:"accessExpires" json:"-"`
+	MFA           bool               `bson:"mfa" json:"mfa"` // вход подтвержден кодом TOTP
+	ExpiresAt     time.Time          `bson:"expiresAt" json:"expiresAt"`
+	UsedAt        *time.Time         `bson:"usedAt,omitempty" json:"usedAt,omitempty"` // заменен новым токеном
+	RevokedAt     *time.Time         `bson:"revokedAt,omitempty" json:"revokedAt,omitempty"`
+	CreatedAt     time.Time          `bson:"createdAt" json:"createdAt"`
+}
+
+// RevokedToken - отозванный access токен (коллекция revoked_tokens, удаляется по TTL после истечения)
+type RevokedToken struct {
+	ID        string             `bson:"_id" json:"id"` // jti
+	UserID    primitive.ObjectID `bson:"userId" json:"userId"`
+	ExpiresAt time.Time          `bson:"expiresAt" json:"expiresAt"`
+	RevokedAt time.Time          `bson:"revokedAt" json:"revokedAt"`
+}
+
+// Назначение одноразовых токенов (ссылки из писем, второй шаг входа)
+const (
+	UserTokenPasswordReset     = "password_reset"
+	UserTokenEmailVerification = "email_verification"
+	UserTokenAccountUnlock     = "account_unlock"
+	UserTokenMFAChallenge      = "mfa_challenge" // пароль проверен, ждем код TOTP
+)
+
+// UserToken - одноразовый токен из письма или второго шага входа (коллекция user_tokens, удаляется по TTL).
+// Хранится только SHA-256 хеш; токен действует для email, на который был отправлен.
+type UserToken struct {
+	ID        primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	UserID    primitive.ObjectID `bson:"userId" json:"userId"`
+	Purpose   string             `bson:"purpose" json:"purpose"`
+	TokenHash string             `bson:"tokenHash" json:"-"`
+	Email     string             `bson:"email" json:"email"`
+	ExpiresAt time.Time          `bson:"expiresAt" json:"expiresAt"`
+	UsedAt    *time.Time         `bson:"usedAt,omitempty" json:"usedAt,omitempty"`
+	CreatedAt time.Time          `bson:"createdAt" json:"createdAt"`
+}
@@ -0,0 +1,41 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// SubscriptionPlan - абонемент "N фильмов за период"
+type SubscriptionPlan struct {
+	ID             primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	Name           string             `bson:"name" json:"name"` // "Cinema Pass 4"
+	Description    string             `bson:"description,omitempty" json:"description,omitempty"`
+	Price          float64            `bson:"price" json:"price"`                   // KZT за период
+	FilmsPerPeriod int                `bson:"filmsPerPeriod" json:"filmsPerPeriod"` // 4
+	PeriodDays     int                `bson:"periodDays" json:"periodDays"`         // 30
+	// Ограничения (пусто = без ограничений)
+	AllowedFormats   []string  `bson:"allowedFormats,omitempty" json:"allowedFormats,omitempty"`     // ["2D"]
+	AllowedHallTypes []string  `bson:"allowedHallTypes,omitempty" json:"allowedHallTypes,omitempty"` // ["Standard"]
+	IsActive         bool      `bson:"isActive" json:"isActive"`
+	CreatedAt        time.Time `bson:"createdAt" json:"createdAt"`
+	UpdatedAt        time.Time `bson:"updatedAt" json:"updatedAt"`
+}
+
+// Subscription - абонемент пользователя (текущий расчетный период)
+type Subscription struct {
+	ID           primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	UserID       primitive.ObjectID `bson:"userId" json:"userId"`
+	PlanID       primitive.ObjectID `bson:"planId" json:"planId"`
+	PlanName     string             `bson:"planName" json:"planName"`
+	Status       string             `bson:"status" json:"status"` // "active", "cancelled", "expired"
+	AutoRenew    bool               `bson:"autoRenew" json:"autoRenew"`
+	PeriodStart  time.Time          `bson:"periodStart" json:"periodStart"`
+	PeriodEnd    time.Time          `bson:"periodEnd" json:"periodEnd"`
+	FilmsAllowed int                `bson:"filmsAllowed" json:"filmsAllowed"`
+	FilmsUsed    int                `bson:"filmsUsed" json:"filmsUsed"`
+	Renewals     int                `bson:"renewals" json:"renewals"`
+	CancelledAt  *time.Time         `bson:"cancelledAt,omitempty" json:"cancelledAt,omitempty"`
+	CreatedAt    time.Time          `bson:"createdAt" json:"createdAt"`
+	UpdatedAt    time.Time          `bson:"updatedAt" json:"updatedAt"`
+}
@@ -7,12 +7,14 @@ import (
 )
 
 type Transaction struct {
-	ID          primitive.ObjectID `bson:"_id,omitempty" json:"id"`
-	UserID      primitive.ObjectID `bson:"userId" json:"userId"`
-	Type        string             `bson:"type" json:"type"` // "booking", "refund", "wallet_topup"
-	Amount      float64            `bson:"amount" json:"amount"`
-	BookingID   primitive.ObjectID `bson:"bookingId,omitempty" json:"bookingId,omitempty"` // может быть null для topup
-	Status      string             `bson:"status" json:"status"`                           // "pending", "completed", "failed"
-	Description string             `bson:"description" json:"description"`
-	CreatedAt   time.Time          `bson:"createdAt" json:"createdAt"`
+	ID             primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	UserID         primitive.ObjectID `bson:"userId" json:"userId"`
+	Type           string             `bson:"type" json:"type"` // "booking", "refund", "wallet_topup", "gift_card_purchase", "gift_card_redeem", "gift_card_payment", "gift_card_refund", "subscription"
+	Amount         float64            `bson:"amount" json:"amount"`
+	BookingID      primitive.ObjectID `bson:"bookingId,omitempty" json:"bookingId,omitempty"` // может быть null для topup
+	Status         string             `bson:"status" json:"status"`                           // "pending", "completed", "failed"
+	Description    string             `bson:"description" json:"description"`
+	GiftCardID     primitive.ObjectID `bson:"giftCardId,omitempty" json:"giftCardId,omitempty"`
+	SubscriptionID primitive.ObjectID `bson:"subscriptionId,omitempty" json:"subscriptionId,omitempty"`
+	CreatedAt      time.Time          `bson:"createdAt" json:"createdAt"`
 }
@@ -12,10 +12,24 @@ type User struct {
 	Password  string             `bson:"password" json:"-"` // не возвращаем в JSON
 	FullName  string             `bson:"fullName" json:"fullName" validate:"required"`
 	Phone     string             `bson:"phone" json:"phone"`
-	Role      string             `bson:"role" json:"role"` // "user", "admin", "cinema_manager"
+	City      string             `bson:"city,omitempty" json:"city,omitempty"` // для уведомлений о сеансах
+	Role      string             `bson:"role" json:"role"`                     // "user", "admin", "cinema_manager"
 	Wallet    Wallet             `bson:"wallet" json:"wallet"`
+	Loyalty   LoyaltyAccount     `bson:"loyalty" json:"loyalty"`
 	CreatedAt time.Time          `bson:"createdAt" json:"createdAt"`
 	UpdatedAt time.Time          `bson:"updatedAt" json:"updatedAt"`
+	// Дата рождения: пользователь указывает один раз, изменить может только админ
+	DateOfBirth *time.Time `bson:"dateOfBirth,omitempty" json:"dateOfBirth,omitempty"`
+	// Email подтвержден по ссылке из письма (или при сбросе пароля)
+	EmailVerified   bool       `bson:"emailVerified" json:"emailVerified"`
+	EmailVerifiedAt *time.Time `bson:"emailVerifiedAt,omitempty" json:"emailVerifiedAt,omitempty"`
+	// Двухфакторная аутентификация (TOTP); обязательна для админов и менеджеров кинотеатров
+	MFAEnabled       bool       `bson:"mfaEnabled" json:"mfaEnabled"`
+	MFAEnabledAt     *time.Time `bson:"mfaEnabledAt,omitempty" json:"mfaEnabledAt,omitempty"`
+	MFASecret        string     `bson:"mfaSecret,omitempty" json:"-"`
+	MFAPendingSecret string     `bson:"mfaPendingSecret,omitempty" json:"-"` // выдан при настройке, еще не подтвержден кодом
+	MFALastStep      int64      `bson:"mfaLastStep,omitempty" json:"-"`      // шаг последнего принятого кода (защита от повтора)
+	MFARecoveryCodes []string   `bson:"mfaRecoveryCodes,omitempty" json:"-"` // SHA-256 хеши неиспользованных кодов
 }
 
 type Wallet struct {
@@ -0,0 +1,17 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// WatchlistItem - фильм в списке "Хочу посмотреть" (коллекция watchlist)
+type WatchlistItem struct {
+	ID      primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	UserID  primitive.ObjectID `bson:"userId" json:"userId"`
+	MovieID primitive.ObjectID `bson:"movieId" json:"movieId"`
+	// Уведомление о начале продаж уже отправлено (или сеансы уже были при добавлении)
+	NotifiedAt *time.Time `bson:"notifiedAt,omitempty" json:"notifiedAt,omitempty"`
+	CreatedAt  time.Time  `bson:"createdAt" json:"createdAt"`
+}
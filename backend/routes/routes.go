@@ -12,6 +12,7 @@ func SetupRoutes(router *gin.Engine) {
 	// Применить глобальные middleware
 	router.Use(middleware.ErrorHandler())
 	router.Use(middleware.CORSMiddleware())
+	router.Use(middleware.Localization())
 
 	// API группа
 	api := router.Group("/api")
@@ -29,17 +30,50 @@ func SetupRoutes(router *gin.Engine) {
 		{
 			auth.POST("/register", handlers.Register)
 			auth.POST("/login", handlers.Login)
+			auth.POST("/refresh", handlers.RefreshToken)
+			auth.POST("/logout", middleware.AuthMiddleware(), handlers.Logout)
+			auth.POST("/password/forgot", handlers.ForgotPassword)
+			auth.POST("/password/reset", handlers.ResetPassword)
+			auth.POST("/email/verify", handlers.VerifyEmail)
+			auth.POST("/email/resend", middleware.AuthMiddleware(), handlers.ResendVerificationEmail)
+			auth.POST("/unlock", handlers.UnlockAccount)
+
+			// Двухфакторная аутентификация (TOTP)
+			auth.POST("/mfa/verify", handlers.VerifyMFA)
+			auth.POST("/mfa/setup", middleware.AuthMiddleware(), handlers.SetupMFA)
+			auth.POST("/mfa/enable", middleware.AuthMiddleware(), handlers.EnableMFA)
+			auth.POST("/mfa/recovery-codes", middleware.AuthMiddleware(), handlers.RegenerateRecoveryCodes)
+			auth.POST("/mfa/disable", middleware.AuthMiddleware(), handlers.DisableMFA)
 		}
 
 		// Movies (публичные - можно смотреть без авторизации)
 		api.GET("/movies", handlers.GetMovies)
+		api.GET("/movies/now-showing", handlers.GetNowShowing)
+		api.GET("/movies/coming-soon", handlers.GetComingSoon)
 		api.GET("/movies/:id", handlers.GetMovieDetails)
+		api.GET("/movies/:id/reviews", handlers.GetMovieReviews)
+
+		// People (режиссеры и актеры с фильмографией)
+		api.GET("/people/:id", handlers.GetPerson)
 
 		// Cinemas (публичные)
 		api.GET("/cinemas", handlers.GetCinemas)
+		api.GET("/cinemas/:id/concessions", handlers.GetCinemaConcessions)
+		api.GET("/cinemas/:id/reviews", handlers.GetCinemaReviews)
 
 		// Showtimes (публичные)
 		api.GET("/showtimes", handlers.GetShowtimes)
+		api.GET("/showtimes/:id/price-quote", handlers.GetPriceQuote)
+
+		// Search (подсказки: фильмы, персоны, кинотеатры)
+		api.GET("/search/suggest", handlers.SearchSuggest)
+
+		// Subscription plans (публичные)
+		api.GET("/subscription-plans", handlers.GetSubscriptionPlans)
+
+		// Files (публичные - постеры и трейлеры из GridFS, с поддержкой Range)
+		api.GET("/files/:id", handlers.ServeFile)
+		api.HEAD("/files/:id", handlers.ServeFile)
 
 		// Protected routes (требуют авторизации)
 		authorized := api.Group("")
@@ -49,14 +83,52 @@ func SetupRoutes(router *gin.Engine) {
 			authorized.GET("/profile", handlers.GetProfile)
 			authorized.PUT("/profile", handlers.UpdateProfile)
 
+			// Платежи доступны только с подтвержденным email
+			verified := middleware.RequireVerifiedEmail()
+
 			// Wallet
-			authorized.POST("/wallet/topup", handlers.TopUpWallet)
+			authorized.POST("/wallet/topup", verified, handlers.TopUpWallet)
+
+			// Gift cards
+			authorized.POST("/gift-cards", verified, handlers.PurchaseGiftCard)
+			authorized.GET("/gift-cards/my", handlers.GetMyGiftCards)
+			authorized.GET("/gift-cards/:code", handlers.GetGiftCardBalance)
+			authorized.POST("/gift-cards/redeem", handlers.RedeemGiftCard)
+
+			// Loyalty
+			authorized.GET("/loyalty", handlers.GetLoyalty)
+			authorized.GET("/loyalty/history", handlers.GetLoyaltyHistory)
+
+			// Subscriptions (абонементы)
+			authorized.POST("/subscriptions", verified, handlers.Subscribe)
+			authorized.GET("/subscriptions/my", handlers.GetMySubscription)
+			authorized.DELETE("/subscriptions/my", handlers.CancelSubscription)
+
+			// Movie reviews (только зрители с прошедшим сеансом)
+			authorized.POST("/movies/:id/reviews", handlers.CreateMovieReview)
+			authorized.PUT("/movies/:id/reviews", handlers.UpdateMovieReview)
+			authorized.DELETE("/movies/:id/reviews", handlers.DeleteMovieReview)
+
+			// Cinema reviews (только посетители прошедших сеансов)
+			authorized.POST("/cinemas/:id/reviews", handlers.CreateCinemaReview)
+			authorized.PUT("/cinemas/:id/reviews", handlers.UpdateCinemaReview)
+			authorized.DELETE("/cinemas/:id/reviews", handlers.DeleteCinemaReview)
 
 			// Bookings (только для авторизованных пользователей)
-			authorized.POST("/bookings", handlers.CreateBooking)
+			authorized.POST("/bookings", verified, handlers.CreateBooking)
 			authorized.GET("/bookings/my", handlers.GetMyBookings)
-			authorized.POST("/bookings/:id/confirm", handlers.ConfirmBooking)
+			authorized.POST("/bookings/:id/confirm", verified, handlers.ConfirmBooking)
 			authorized.DELETE("/bookings/:id", handlers.CancelBooking)
+			authorized.POST("/bookings/:id/concessions", verified, handlers.AddBookingConcessions)
+
+			// Watchlist ("Хочу посмотреть") и уведомления о начале продаж
+			authorized.GET("/watchlist", handlers.GetWatchlist)
+			authorized.POST("/watchlist/:movieId", handlers.AddToWatchlist)
+			authorized.DELETE("/watchlist/:movieId", handlers.RemoveFromWatchlist)
+			authorized.GET("/notifications", handlers.GetMyNotifications)
+
+			// Recommendations (пересчитываются пакетно: compute-recommendations)
+			authorized.GET("/recommendations", handlers.GetRecommendations)
 
 			// Analytics
 			authorized.GET("/analytics/popular-movies", handlers.GetPopularMovies)
@@ -68,15 +140,98 @@ func SetupRoutes(router *gin.Engine) {
 		admin := api.Group("/admin")
 		admin.Use(middleware.AuthMiddleware())
 		admin.Use(middleware.RequireRole("admin"))
+		admin.Use(middleware.RequireMFA())
 		{
 			// Управление фильмами
 			admin.POST("/movies", handlers.CreateMovie)
+			admin.POST("/movies/import", handlers.ImportMovieMetadata)
 			admin.PUT("/movies/:id", handlers.UpdateMovie)
 			admin.DELETE("/movies/:id", handlers.DeleteMovie)
+			admin.GET("/analytics/anticipation", handlers.GetAnticipation)
+			admin.GET("/analytics/revenue-by-category", handlers.GetRevenueByCategory)
+			admin.POST("/movies/:id/poster", handlers.UploadMoviePoster)
+			admin.POST("/movies/:id/trailer", handlers.UploadMovieTrailer)
+			admin.PUT("/movies/:id/credits", handlers.SetMovieCredits)
+
+			// Режиссеры и актеры
+			admin.POST("/people", handlers.CreatePerson)
+			admin.PUT("/people/:id", handlers.UpdatePerson)
+			admin.POST("/people/:id/photo", handlers.UploadPersonPhoto)
 
 			// Управление сеансами
 			admin.POST("/showtimes", handlers.CreateShowtime)
 			admin.DELETE("/showtimes/:id", handlers.DeleteShowtime)
+
+			// Импорт/экспорт расписания (CSV, JSON)
+			admin.POST("/schedule/import", handlers.ImportSchedule)
+			admin.GET("/schedule/export", handlers.ExportSchedule)
+
+			// Правила динамического ценообразования
+			admin.GET("/pricing-rules", handlers.GetPricingRules)
+			admin.POST("/pricing-rules", handlers.CreatePricingRule)
+			admin.PUT("/pricing-rules/:id", handlers.UpdatePricingRule)
+			admin.DELETE("/pricing-rules/:id", handlers.DeletePricingRule)
+
+			// Категории билетов кинотеатра
+			admin.PUT("/cinemas/:id/ticket-categories", handlers.UpdateTicketCategories)
+
+			// Возрастные ограничения: политика кинотеатра и дата рождения пользователя
+			admin.PUT("/cinemas/:id/age-policy", handlers.UpdateCinemaAgePolicy)
+			admin.PUT("/users/:id/date-of-birth", handlers.SetUserDateOfBirth)
+
+			// Фото кинотеатра (с уменьшенными вариантами)
+			admin.POST("/cinemas/:id/images", handlers.UploadCinemaImage)
+			admin.DELETE("/cinemas/:id/images/:fileId", handlers.DeleteCinemaImage)
+
+			// Промокоды и кампании
+			admin.GET("/promo-codes", handlers.GetPromoCodes)
+			admin.POST("/promo-codes", handlers.CreatePromoCode)
+			admin.POST("/promo-codes/batch", handlers.GeneratePromoBatch)
+			admin.PUT("/promo-codes/:id", handlers.UpdatePromoCode)
+			admin.DELETE("/promo-codes/:id", handlers.DeletePromoCode)
+
+			// Подарочные карты
+			admin.GET("/gift-cards", handlers.GetGiftCards)
+			admin.POST("/gift-cards/batch", handlers.IssueGiftCards)
+			admin.DELETE("/gift-cards/:id", handlers.DisableGiftCard)
+
+			// Ставки начисления баллов лояльности
+			admin.GET("/loyalty/rates", handlers.GetLoyaltyRates)
+			admin.POST("/loyalty/rates", handlers.CreateLoyaltyRate)
+			admin.DELETE("/loyalty/rates/:id", handlers.DeleteLoyaltyRate)
+
+			// Абонементы
+			admin.POST("/subscription-plans", handlers.CreateSubscriptionPlan)
+			admin.PUT("/subscription-plans/:id", handlers.UpdateSubscriptionPlan)
+			admin.DELETE("/subscription-plans/:id", handlers.DeleteSubscriptionPlan)
+			admin.GET("/analytics/passes", handlers.GetPassAnalytics)
+
+			// Модерация отзывов
+			admin.GET("/reviews", handlers.GetReviewsForModeration)
+			admin.PATCH("/reviews/:id", handlers.ModerateMovieReview)
+
+			// Безопасность: блокировки входа и журнал
+			admin.POST("/users/:id/unlock", handlers.AdminUnlockUser)
+			admin.POST("/users/:id/mfa/reset", handlers.AdminResetMFA)
+			admin.GET("/audit", handlers.GetAuditLog)
+		}
+
+		// Staff routes (админы и менеджеры кинотеатров)
+		staff := api.Group("/staff")
+		staff.Use(middleware.AuthMiddleware())
+		staff.Use(middleware.RequireRole("admin", "cinema_manager"))
+		staff.Use(middleware.RequireMFA())
+		{
+			// Проход в зал (с проверкой документов для льготных билетов)
+			staff.POST("/bookings/:id/check-in", handlers.CheckInBooking)
+
+			// Бар: меню, остатки и очередь заказов
+			staff.POST("/concessions", handlers.CreateConcessionItem)
+			staff.PUT("/concessions/:id", handlers.UpdateConcessionItem)
+			staff.POST("/concessions/:id/restock", handlers.RestockConcessionItem)
+			staff.DELETE("/concessions/:id", handlers.DeleteConcessionItem)
+			staff.GET("/kitchen-queue", handlers.GetKitchenQueue)
+			staff.PATCH("/bookings/:id/concessions/status", handlers.UpdateConcessionStatus)
 		}
 	}
 }
@@ -0,0 +1,23 @@
+package scripts
+
+import (
+	"cinema-booking/handlers"
+	"context"
+	"log"
+	"time"
+)
+
+// ComputeRecommendations - пересчитать персональные рекомендации (запускать по cron раз в сутки)
+func ComputeRecommendations() bool {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Minute)
+	defer cancel()
+
+	users, err := handlers.ComputeRecommendations(ctx)
+	if err != nil {
+		log.Printf("❌ Failed to compute recommendations: %v", err)
+		return false
+	}
+
+	log.Printf("✅ Computed recommendations for %d users", users)
+	return true
+}
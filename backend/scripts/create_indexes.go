@@ -21,26 +21,129 @@ func CreateIndexes() {
 	showtimesCol := config.GetCollection("showtimes")
 	createCompoundIndex(ctx, showtimesCol, []string{"movieId", "cinemaId", "startTime"})
 	createIndex(ctx, showtimesCol, "startTime", false)
+	// Проверка пересечений сеансов в зале (импорт расписания, CreateShowtime)
+	createCompoundIndex(ctx, showtimesCol, []string{"hallId", "startTime"})
+	// Блокировки залов на время записи расписания; зависшие удаляются по истечении срока
+	createTTLIndex(ctx, config.GetCollection("hall_locks"), "expiresAt", 0)
 
 	// 3. Bookings indexes
 	bookingsCol := config.GetCollection("bookings")
 	createCompoundIndex(ctx, bookingsCol, []string{"userId", "createdAt"})
 	createIndex(ctx, bookingsCol, "bookingNumber", true) // unique
-	// TTL index для автоудаления expired bookings
-	createTTLIndex(ctx, bookingsCol, "expiresAt", 0)
+	createCompoundIndex(ctx, bookingsCol, []string{"showtimeId", "concessionStatus", "pickupTime"})
+	// Неоплаченные брони закрывает ExpirePendingBookings (с возвратом удержаний), а не TTL
+	dropIndex(ctx, bookingsCol, "expiresAt_1")
+	createCompoundIndex(ctx, bookingsCol, []string{"status", "expiresAt"})
 
 	// 4. Movies indexes (text search)
 	moviesCol := config.GetCollection("movies")
 	createTextIndex(ctx, moviesCol, []string{"title", "description"})
 	createIndex(ctx, moviesCol, "isActive", false)
+	createIndex(ctx, moviesCol, "releaseDate", false) // coming soon, активация в день релиза
 
 	// 5. Cinemas indexes (geospatial)
 	cinemasCol := config.GetCollection("cinemas")
 	create2DSphereIndex(ctx, cinemasCol, "location")
+	createIndex(ctx, cinemasCol, "rating", false)
 
 	// 6. Transactions indexes
 	transactionsCol := config.GetCollection("transactions")
 	createCompoundIndex(ctx, transactionsCol, []string{"userId", "createdAt"})
+	createCompoundIndex(ctx, transactionsCol, []string{"type", "createdAt"})
+
+	// 7. Pricing rules indexes
+	pricingRulesCol := config.GetCollection("pricing_rules")
+	createCompoundIndex(ctx, pricingRulesCol, []string{"isActive", "priority"})
+
+	// 8. Promo codes indexes
+	promoCodesCol := config.GetCollection("promo_codes")
+	createIndex(ctx, promoCodesCol, "code", true) // unique
+	createIndex(ctx, promoCodesCol, "campaign", false)
+	promoRedemptionsCol := config.GetCollection("promo_redemptions")
+	createCompoundIndex(ctx, promoRedemptionsCol, []string{"promoCodeId", "userId", "status"})
+	createIndex(ctx, promoRedemptionsCol, "bookingId", false)
+
+	// 9. Gift cards indexes
+	giftCardsCol := config.GetCollection("gift_cards")
+	createIndex(ctx, giftCardsCol, "code", true) // unique
+	createIndex(ctx, giftCardsCol, "purchasedBy", false)
+	createIndex(ctx, giftCardsCol, "batch", false)
+
+	// 10. Loyalty indexes
+	loyaltyTransactionsCol := config.GetCollection("loyalty_transactions")
+	createCompoundIndex(ctx, loyaltyTransactionsCol, []string{"userId", "createdAt"})
+	createIndex(ctx, loyaltyTransactionsCol, "bookingId", false)
+
+	loyaltyRatesCol := config.GetCollection("loyalty_rates")
+	createCompoundIndex(ctx, loyaltyRatesCol, []string{"isActive", "cinemaId", "format"})
+
+	// 11. Subscriptions indexes
+	subscriptionsCol := config.GetCollection("subscriptions")
+	createCompoundIndex(ctx, subscriptionsCol, []string{"userId", "status"})
+	createCompoundIndex(ctx, subscriptionsCol, []string{"status", "periodEnd"})
+	// Не больше одного действующего абонемента на пользователя
+	createPartialUniqueIndex(ctx, subscriptionsCol, "userId", bson.M{"status": "active"})
+
+	// 12. Concessions indexes
+	concessionsCol := config.GetCollection("concessions")
+	createCompoundIndex(ctx, concessionsCol, []string{"cinemaId", "isActive", "category"})
+
+	// 13. Movie reviews indexes
+	movieReviewsCol := config.GetCollection("movie_reviews")
+	createUniqueCompoundIndex(ctx, movieReviewsCol, []string{"movieId", "userId"}) // один отзыв на пользователя
+	createCompoundIndex(ctx, movieReviewsCol, []string{"movieId", "status", "createdAt"})
+	createCompoundIndex(ctx, movieReviewsCol, []string{"flagged", "createdAt"})
+
+	// 14. Cinema reviews indexes
+	cinemaReviewsCol := config.GetCollection("cinema_reviews")
+	createUniqueCompoundIndex(ctx, cinemaReviewsCol, []string{"cinemaId", "userId"}) // один отзыв на пользователя
+	createCompoundIndex(ctx, cinemaReviewsCol, []string{"cinemaId", "createdAt"})
+
+	// 15. People indexes
+	peopleCol := config.GetCollection("people")
+	// Однофамильцы - разные персоны: nameKey только для поиска по имени, не уникален
+	dropUniqueIndex(ctx, peopleCol, "nameKey_1")
+	createIndex(ctx, peopleCol, "nameKey", false)
+	createIndex(ctx, moviesCol, "credits.personId", false)
+
+	// 16. Recommendations indexes (кэш на пользователя)
+	recommendationsCol := config.GetCollection("recommendations")
+	createIndex(ctx, recommendationsCol, "userId", true) // unique
+	createIndex(ctx, recommendationsCol, "computedAt", false)
+
+	// 17. Watchlist & notifications indexes
+	watchlistCol := config.GetCollection("watchlist")
+	createUniqueCompoundIndex(ctx, watchlistCol, []string{"userId", "movieId"}) // фильм в списке один раз
+	createCompoundIndex(ctx, watchlistCol, []string{"movieId", "notifiedAt"})
+	notificationsCol := config.GetCollection("notifications")
+	createCompoundIndex(ctx, notificationsCol, []string{"userId", "createdAt"})
+	createCompoundIndex(ctx, notificationsCol, []string{"status", "createdAt"})
+
+	// 18. Movie metadata import (upsert по внешнему ID, у фильмов без импорта поля нет)
+	createSparseUniqueIndex(ctx, moviesCol, "externalId")
+	createIndex(ctx, moviesCol, "externalIds", false) // поиск по imdb или tmdb id
+
+	// 19. Sessions indexes (refresh токены и denylist access токенов)
+	refreshTokensCol := config.GetCollection("refresh_tokens")
+	createIndex(ctx, refreshTokensCol, "tokenHash", true) // unique
+	createIndex(ctx, refreshTokensCol, "familyId", false)
+	createCompoundIndex(ctx, refreshTokensCol, []string{"userId", "deviceId"})
+	createTTLIndex(ctx, refreshTokensCol, "expiresAt", 0) // удаляются по окончании сессии
+	revokedTokensCol := config.GetCollection("revoked_tokens")
+	createTTLIndex(ctx, revokedTokensCol, "expiresAt", 0) // после истечения токен не нужен
+
+	// 20. User tokens indexes (ссылки из писем: сброс пароля, подтверждение email)
+	userTokensCol := config.GetCollection("user_tokens")
+	createIndex(ctx, userTokensCol, "tokenHash", true) // unique
+	createCompoundIndex(ctx, userTokensCol, []string{"userId", "purpose", "createdAt"})
+	createTTLIndex(ctx, userTokensCol, "expiresAt", 0)
+
+	// 21. Login protection & audit indexes (счетчики неудачных входов, журнал безопасности)
+	loginAttemptsCol := config.GetCollection("login_attempts")
+	createTTLIndex(ctx, loginAttemptsCol, "expiresAt", 0) // окно без ошибок прошло или блокировка истекла
+	auditLogCol := config.GetCollection("audit_log")
+	createCompoundIndex(ctx, auditLogCol, []string{"type", "createdAt"})
+	createCompoundIndex(ctx, auditLogCol, []string{"userId", "createdAt"})
 
 	log.Println("✅ All indexes created successfully")
 }
@@ -59,6 +162,32 @@ func createIndex(ctx context.Context, col *mongo.Collection, field string, uniqu
 	}
 }
 
+func createSparseUniqueIndex(ctx context.Context, col *mongo.Collection, field string) {
+	indexModel := mongo.IndexModel{
+		Keys:    bson.D{{Key: field, Value: 1}},
+		Options: options.Index().SetUnique(true).SetSparse(true),
+	}
+	_, err := col.Indexes().CreateOne(ctx, indexModel)
+	if err != nil {
+		log.Printf("⚠️ Warning: Sparse unique index on %s.%s failed: %v", col.Name(), field, err)
+	} else {
+		log.Printf("✅ Created sparse unique index on %s.%s", col.Name(), field)
+	}
+}
+
+func createPartialUniqueIndex(ctx context.Context, col *mongo.Collection, field string, filter bson.M) {
+	indexModel := mongo.IndexModel{
+		Keys:    bson.D{{Key: field, Value: 1}},
+		Options: options.Index().SetUnique(true).SetPartialFilterExpression(filter),
+	}
+	_, err := col.Indexes().CreateOne(ctx, indexModel)
+	if err != nil {
+		log.Printf("⚠️ Warning: Partial unique index on %s.%s failed: %v", col.Name(), field, err)
+	} else {
+		log.Printf("✅ Created partial unique index on %s.%s", col.Name(), field)
+	}
+}
+
 func createCompoundIndex(ctx context.Context, col *mongo.Collection, fields []string) {
 	keys := bson.D{}
 	for _, field := range fields {
@@ -73,6 +202,23 @@ func createCompoundIndex(ctx context.Context, col *mongo.Collection, fields []st
 	}
 }
 
+func createUniqueCompoundIndex(ctx context.Context, col *mongo.Collection, fields []string) {
+	keys := bson.D{}
+	for _, field := range fields {
+		keys = append(keys, bson.E{Key: field, Value: 1})
+	}
+	indexModel := mongo.IndexModel{
+		Keys:    keys,
+		Options: options.Index().SetUnique(true),
+	}
+	_, err := col.Indexes().CreateOne(ctx, indexModel)
+	if err != nil {
+		log.Printf("⚠️ Warning: Unique compound index on %s failed: %v", col.Name(), err)
+	} else {
+		log.Printf("✅ Created unique compound index on %s: %v", col.Name(), fields)
+	}
+}
+
 func createTextIndex(ctx context.Context, col *mongo.Collection, fields []string) {
 	keys := bson.D{}
 	for _, field := range fields {
@@ -111,3 +257,37 @@ func createTTLIndex(ctx context.Context, col *mongo.Collection, field string, ex
 		log.Printf("✅ Created TTL index on %s.%s", col.Name(), field)
 	}
 }
+
+func dropIndex(ctx context.Context, col *mongo.Collection, name string) {
+	_, err := col.Indexes().DropOne(ctx, name)
+	if err != nil {
+		// Индекса может не быть (новая база или уже удален)
+		if cmdErr, ok := err.(mongo.CommandError); ok && cmdErr.Code == 27 {
+			return
+		}
+		log.Printf("⚠️ Warning: dropping index %s on %s failed: %v", name, col.Name(), err)
+	} else {
+		log.Printf("✅ Dropped index %s on %s", name, col.Name())
+	}
+}
+
+// dropUniqueIndex - удалить индекс, только если он уникальный (чтобы пересоздать его без уникальности)
+func dropUniqueIndex(ctx context.Context, col *mongo.Collection, name string) {
+	cursor, err := col.Indexes().List(ctx)
+	if err != nil {
+		log.Printf("⚠️ Warning: listing indexes on %s failed: %v", col.Name(), err)
+		return
+	}
+	var indexes []bson.M
+	if err := cursor.All(ctx, &indexes); err != nil {
+		log.Printf("⚠️ Warning: listing indexes on %s failed: %v", col.Name(), err)
+		return
+	}
+
+	for _, index := range indexes {
+		if index["name"] == name && index["unique"] == true {
+			dropIndex(ctx, col, name)
+			return
+		}
+	}
+}
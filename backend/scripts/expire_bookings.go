@@ -0,0 +1,23 @@
+package scripts
+
+import (
+	"cinema-booking/handlers"
+	"context"
+	"log"
+	"time"
+)
+
+// ExpireBookings - закрыть неоплаченные брони с истекшим сроком и вернуть удержания
+func ExpireBookings() bool {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Minute)
+	defer cancel()
+
+	expired, err := handlers.ExpirePendingBookings(ctx)
+	if err != nil {
+		log.Printf("❌ Failed to expire bookings: %v", err)
+		return false
+	}
+
+	log.Printf("✅ Expired %d unpaid bookings", expired)
+	return true
+}
@@ -0,0 +1,46 @@
+package scripts
+
+import (
+	"cinema-booking/handlers"
+	"context"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+)
+
+// ImportMovieMetadata - импортировать метаданные фильмов из JSON файла или каталога (CLI)
+// Возвращает false, если источник недоступен или часть документов не обработана.
+func ImportMovieMetadata(path string, dryRun bool) bool {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Minute)
+	defer cancel()
+
+	result, err := handlers.ImportMovies(ctx, handlers.FileMovieFetcher{Path: path}, dryRun)
+	if err != nil {
+		log.Printf("❌ %v", err)
+		return false
+	}
+
+	// Отчет по измененным фильмам
+	for _, movie := range result.Movies {
+		fmt.Printf("%s %s (%s)\n", movie.Action, movie.Title, movie.ExternalID)
+		for _, diff := range movie.Diffs {
+			fmt.Printf("    %s: %v -> %v\n", diff.Field, diff.Old, diff.New)
+		}
+		if len(movie.Warnings) > 0 {
+			fmt.Printf("    warnings: %s\n", strings.Join(movie.Warnings, "; "))
+		}
+	}
+	for _, importErr := range result.Errors {
+		fmt.Printf("%s: %s\n", importErr.Source, importErr.Error)
+	}
+
+	prefix := "✅ Imported"
+	if dryRun {
+		prefix = "🔎 Dry run:"
+	}
+	log.Printf("%s %d documents: %d created, %d updated, %d unchanged, %d failed",
+		prefix, result.Documents, result.Created, result.Updated, result.Unchanged, result.Failed)
+
+	return result.Failed == 0
+}
@@ -0,0 +1,52 @@
+package scripts
+
+import (
+	"cinema-booking/handlers"
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// ImportScheduleFile - импортировать расписание из CSV/JSON файла (CLI)
+// Возвращает false, если файл не удалось обработать или есть ошибки в строках.
+func ImportScheduleFile(path string, dryRun bool) bool {
+	file, err := os.Open(path)
+	if err != nil {
+		log.Printf("❌ Failed to open schedule file: %v", err)
+		return false
+	}
+	defer file.Close()
+
+	format := strings.TrimPrefix(strings.ToLower(filepath.Ext(path)), ".")
+	rows, err := handlers.ParseSchedule(file, format)
+	if err != nil {
+		log.Printf("❌ %v", err)
+		return false
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Minute)
+	defer cancel()
+
+	result, err := handlers.ImportScheduleRows(ctx, rows, dryRun)
+	if err != nil {
+		log.Printf("❌ %v", err)
+		return false
+	}
+
+	// Отчет по строкам с ошибками
+	for _, rowErr := range result.Errors {
+		fmt.Printf("Row %d: %s\n", rowErr.Row, strings.Join(rowErr.Errors, "; "))
+	}
+
+	if dryRun {
+		log.Printf("🔎 Dry run: %d of %d rows are valid, %d failed", len(result.Showtimes), result.TotalRows, result.Failed)
+	} else {
+		log.Printf("✅ Imported %d of %d rows, %d failed", result.Imported, result.TotalRows, result.Failed)
+	}
+
+	return result.Failed == 0
+}
@@ -0,0 +1,27 @@
+package scripts
+
+import (
+	"cinema-booking/handlers"
+	"context"
+	"log"
+	"time"
+)
+
+// MigrateEmailVerified - отметить email существующих аккаунтов подтвержденным (CLI и запуск сервера).
+// Без этого аккаунты, созданные до подтверждения email, теряют доступ к оплате и бронированию.
+// Повторный запуск безопасен: обновляются только документы без поля emailVerified.
+func MigrateEmailVerified() bool {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Minute)
+	defer cancel()
+
+	updated, err := handlers.MarkExistingEmailsVerified(ctx)
+	if err != nil {
+		log.Printf("❌ Failed to mark existing emails verified: %v", err)
+		return false
+	}
+
+	if updated > 0 {
+		log.Printf("✅ Marked %d existing accounts as email-verified", updated)
+	}
+	return true
+}
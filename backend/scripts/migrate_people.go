@@ -0,0 +1,27 @@
+package scripts
+
+import (
+	"cinema-booking/handlers"
+	"context"
+	"log"
+	"time"
+)
+
+// MigratePeople - перенести строки Director/Cast фильмов в коллекцию people (CLI)
+// Повторный запуск безопасен: фильмы с заполненными credits пропускаются.
+func MigratePeople() bool {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Minute)
+	defer cancel()
+
+	// Индекс по nameKey нужен до переноса: каждая строка Director/Cast ищется по имени
+	CreateIndexes()
+
+	movies, err := handlers.MigratePeople(ctx)
+	if err != nil {
+		log.Printf("❌ Failed to migrate people: %v", err)
+		return false
+	}
+
+	log.Printf("✅ Linked credits for %d movies", movies)
+	return true
+}
@@ -0,0 +1,27 @@
+package scripts
+
+import (
+	"cinema-booking/handlers"
+	"context"
+	"log"
+	"time"
+)
+
+// MigrateMovieReviews - перенести встроенные отзывы фильмов в коллекцию movie_reviews (CLI)
+// Повторный запуск безопасен: уже перенесенные отзывы пропускаются.
+func MigrateMovieReviews() bool {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Minute)
+	defer cancel()
+
+	// Уникальный индекс нужен до переноса, чтобы не создать дубликаты
+	CreateIndexes()
+
+	movies, reviews, err := handlers.MigrateEmbeddedReviews(ctx)
+	if err != nil {
+		log.Printf("❌ Failed to migrate reviews: %v", err)
+		return false
+	}
+
+	log.Printf("✅ Migrated %d reviews from %d movies", reviews, movies)
+	return true
+}
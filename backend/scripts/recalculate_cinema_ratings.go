@@ -0,0 +1,24 @@
+package scripts
+
+import (
+	"cinema-booking/handlers"
+	"context"
+	"log"
+	"time"
+)
+
+// RecalculateCinemaRatings - пересчитать рейтинги кинотеатров по отзывам (CLI)
+// Заменяет значения, не подтвержденные отзывами (например, из старого seed).
+func RecalculateCinemaRatings() bool {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Minute)
+	defer cancel()
+
+	cinemas, err := handlers.RecalculateCinemaRatings(ctx)
+	if err != nil {
+		log.Printf("❌ Failed to recalculate cinema ratings: %v", err)
+		return false
+	}
+
+	log.Printf("✅ Recalculated ratings for %d cinemas", cinemas)
+	return true
+}
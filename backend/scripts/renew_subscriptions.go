@@ -0,0 +1,23 @@
+package scripts
+
+import (
+	"cinema-booking/handlers"
+	"context"
+	"log"
+	"time"
+)
+
+// RenewSubscriptions - продлить абонементы с истекшим периодом (запускать по cron раз в час)
+func RenewSubscriptions() bool {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Minute)
+	defer cancel()
+
+	renewed, expired, err := handlers.RenewDueSubscriptions(ctx)
+	if err != nil {
+		log.Printf("❌ Failed to renew subscriptions: %v", err)
+		return false
+	}
+
+	log.Printf("✅ Renewed %d subscriptions, %d expired", renewed, expired)
+	return true
+}
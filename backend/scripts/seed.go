@@ -2,11 +2,13 @@ package scripts
 
 import (
 	"cinema-booking/config"
+	"cinema-booking/handlers"
 	"cinema-booking/models"
 	"context"
 	"log"
 	"time"
 
+	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 	"golang.org/x/crypto/bcrypt"
 )
@@ -54,7 +56,7 @@ func SeedDatabase() {
 
 // Очистка коллекций
 func clearCollections(ctx context.Context) {
-	collections := []string{"users", "cinemas", "halls", "movies", "showtimes", "bookings", "transactions"}
+	collections := []string{"users", "cinemas", "halls", "movies", "showtimes", "bookings", "transactions", "movie_reviews", "cinema_reviews", "people"}
 
 	for _, collName := range collections {
 		coll := config.GetCollection(collName)
@@ -144,7 +146,11 @@ func seedUsers(ctx context.Context) {
 		},
 	}
 
+	// Демо-аккаунты уже подтверждены: платежи требуют подтвержденный email
+	verifiedAt := time.Now()
 	for i := range users {
+		users[i].EmailVerified = true
+		users[i].EmailVerifiedAt = &verifiedAt
 		result, err := collection.InsertOne(ctx, users[i])
 		if err != nil {
 			log.Printf("❌ Error inserting user: %v", err)
@@ -169,12 +175,10 @@ func seedCinemas(ctx context.Context) {
 				Type:        "Point",
 				Coordinates: []float64{76.8512, 43.2061}, // [longitude, latitude]
 			},
-			Facilities:   []string{"IMAX", "4DX", "VIP", "Parking", "Food Court"},
-			HallIDs:      []primitive.ObjectID{}, // заполним позже
-			Rating:       4.6,
-			TotalReviews: 1523,
-			Images:       []string{"/uploads/cinemas/chaplin_mega.jpg"},
-			CreatedAt:    time.Now(),
+			Facilities: []string{"IMAX", "4DX", "VIP", "Parking", "Food Court"},
+			HallIDs:    []primitive.ObjectID{}, // заполним позже
+			Images:     []string{"/uploads/cinemas/chaplin_mega.jpg"},
+			CreatedAt:  time.Now(),
 		},
 		{
 			Name:    "Kinopark Sary-Arka",
@@ -184,12 +188,10 @@ func seedCinemas(ctx context.Context) {
 				Type:        "Point",
 				Coordinates: []float64{76.9286, 43.2425},
 			},
-			Facilities:   []string{"3D", "VIP", "Parking"},
-			HallIDs:      []primitive.ObjectID{},
-			Rating:       4.3,
-			TotalReviews: 892,
-			Images:       []string{"/uploads/cinemas/kinopark_saryarka.jpg"},
-			CreatedAt:    time.Now(),
+			Facilities: []string{"3D", "VIP", "Parking"},
+			HallIDs:    []primitive.ObjectID{},
+			Images:     []string{"/uploads/cinemas/kinopark_saryarka.jpg"},
+			CreatedAt:  time.Now(),
 		},
 		{
 			Name:    "Arman Cinema Dostyk Plaza",
@@ -199,12 +201,10 @@ func seedCinemas(ctx context.Context) {
 				Type:        "Point",
 				Coordinates: []float64{76.9539, 43.2324},
 			},
-			Facilities:   []string{"3D", "IMAX", "VIP", "Dolby Atmos"},
-			HallIDs:      []primitive.ObjectID{},
-			Rating:       4.7,
-			TotalReviews: 2105,
-			Images:       []string{"/uploads/cinemas/arman_dostyk.jpg"},
-			CreatedAt:    time.Now(),
+			Facilities: []string{"3D", "IMAX", "VIP", "Dolby Atmos"},
+			HallIDs:    []primitive.ObjectID{},
+			Images:     []string{"/uploads/cinemas/arman_dostyk.jpg"},
+			CreatedAt:  time.Now(),
 		},
 	}
 
@@ -394,7 +394,6 @@ func seedMovies(ctx context.Context) {
 			Subtitles:      []string{"Kazakh", "Russian", "English"},
 			IsActive:       true,
 			AgeRestriction: 13,
-			Reviews:        []models.Review{},
 			CreatedAt:      time.Now(),
 		},
 		{
@@ -414,7 +413,6 @@ func seedMovies(ctx context.Context) {
 			Subtitles:      []string{"Kazakh", "Russian"},
 			IsActive:       true,
 			AgeRestriction: 13,
-			Reviews:        []models.Review{},
 			CreatedAt:      time.Now(),
 		},
 		{
@@ -434,7 +432,6 @@ func seedMovies(ctx context.Context) {
 			Subtitles:      []string{"Kazakh", "Russian", "English"},
 			IsActive:       true,
 			AgeRestriction: 13,
-			Reviews:        []models.Review{},
 			CreatedAt:      time.Now(),
 		},
 		{
@@ -454,7 +451,6 @@ func seedMovies(ctx context.Context) {
 			Subtitles:      []string{"Kazakh", "Russian"},
 			IsActive:       true,
 			AgeRestriction: 13,
-			Reviews:        []models.Review{},
 			CreatedAt:      time.Now(),
 		},
 		{
@@ -474,7 +470,6 @@ func seedMovies(ctx context.Context) {
 			Subtitles:      []string{"Kazakh", "Russian"},
 			IsActive:       true,
 			AgeRestriction: 18,
-			Reviews:        []models.Review{},
 			CreatedAt:      time.Now(),
 		},
 		{
@@ -494,7 +489,6 @@ func seedMovies(ctx context.Context) {
 			Subtitles:      []string{"Kazakh", "Russian", "English"},
 			IsActive:       true,
 			AgeRestriction: 6,
-			Reviews:        []models.Review{},
 			CreatedAt:      time.Now(),
 		},
 		{
@@ -514,7 +508,6 @@ func seedMovies(ctx context.Context) {
 			Subtitles:      []string{"Kazakh", "Russian"},
 			IsActive:       true,
 			AgeRestriction: 16,
-			Reviews:        []models.Review{},
 			CreatedAt:      time.Now(),
 		},
 		{
@@ -535,38 +528,10 @@ func seedMovies(ctx context.Context) {
 			Subtitles:      []string{"Kazakh", "Russian"},
 			IsActive:       true,
 			AgeRestriction: 12,
-			Reviews:        []models.Review{},
 			CreatedAt:      time.Now(),
 		},
 	}
 
-	// Добавить тестовые отзывы к некоторым фильмам
-	if len(userIDs) >= 3 {
-		movies[0].Reviews = []models.Review{
-			{
-				UserID:    userIDs[2],
-				Rating:    9.5,
-				Comment:   "Шынайы ғылыми-фантастикалық шедевр! Көріністер таңқаларлық!",
-				CreatedAt: time.Now().Add(-48 * time.Hour),
-			},
-			{
-				UserID:    userIDs[3],
-				Rating:    9.0,
-				Comment:   "Лучший научно-фантастический фильм последних лет!",
-				CreatedAt: time.Now().Add(-24 * time.Hour),
-			},
-		}
-
-		movies[5].Reviews = []models.Review{
-			{
-				UserID:    userIDs[4],
-				Rating:    10.0,
-				Comment:   "Балаларға және ересектерге арналған керемет мультфильм!",
-				CreatedAt: time.Now().Add(-72 * time.Hour),
-			},
-		}
-	}
-
 	for i := range movies {
 		result, err := collection.InsertOne(ctx, movies[i])
 		if err != nil {
@@ -577,6 +542,66 @@ func seedMovies(ctx context.Context) {
 	}
 
 	log.Printf("✅ Created %d movies", len(movieIDs))
+
+	// Режиссеры и актеры - в коллекцию people
+	if linked, err := handlers.MigratePeople(ctx); err != nil {
+		log.Printf("❌ Error linking movie credits: %v", err)
+	} else {
+		log.Printf("✅ Linked credits for %d movies", linked)
+	}
+
+	// Добавить тестовые отзывы к некоторым фильмам
+	if len(userIDs) >= 5 && len(movieIDs) >= 6 {
+		seedMovieReviews(ctx)
+	}
+}
+
+// 4.1 MOVIE REVIEWS
+func seedMovieReviews(ctx context.Context) {
+	collection := config.GetCollection("movie_reviews")
+
+	reviews := []models.MovieReview{
+		{
+			MovieID:   movieIDs[0],
+			UserID:    userIDs[2],
+			Rating:    9.5,
+			Comment:   "Шынайы ғылыми-фантастикалық шедевр! Көріністер таңқаларлық!",
+			CreatedAt: time.Now().Add(-48 * time.Hour),
+		},
+		{
+			MovieID:   movieIDs[0],
+			UserID:    userIDs[3],
+			Rating:    9.0,
+			Comment:   "Лучший научно-фантастический фильм последних лет!",
+			CreatedAt: time.Now().Add(-24 * time.Hour),
+		},
+		{
+			MovieID:   movieIDs[5],
+			UserID:    userIDs[4],
+			Rating:    10.0,
+			Comment:   "Балаларға және ересектерге арналған керемет мультфильм!",
+			CreatedAt: time.Now().Add(-72 * time.Hour),
+		},
+	}
+
+	usersCol := config.GetCollection("users")
+	for i := range reviews {
+		var user models.User
+		if err := usersCol.FindOne(ctx, bson.M{"_id": reviews[i].UserID}).Decode(&user); err == nil {
+			reviews[i].UserName = user.FullName
+		}
+		reviews[i].Status = "published"
+		reviews[i].UpdatedAt = reviews[i].CreatedAt
+
+		if _, err := collection.InsertOne(ctx, reviews[i]); err != nil {
+			log.Printf("❌ Error inserting review: %v", err)
+		}
+	}
+
+	handlers.RecalculateMovieRating(ctx, movieIDs[0])
+	handlers.RecalculateMovieRating(ctx, movieIDs[5])
+
+	log.Printf("✅ Created %d movie reviews", len(reviews))
 }
 
 // 5. SHOWTIMES
@@ -683,6 +708,7 @@ func seedBookings(ctx context.Context) {
 		return
 	}
 
+	pendingExpiresAt := time.Now().Add(15 * time.Minute)
 	bookings := []models.Booking{
 		{
 			BookingNumber: "BK-20260201-001234",
@@ -701,7 +727,6 @@ func seedBookings(ctx context.Context) {
 				Status:        "completed",
 			},
 			QRCode:    "QR-BK-20260201-001234",
-			ExpiresAt: time.Now().Add(24 * time.Hour),
 			CreatedAt: time.Now().Add(-2 * time.Hour),
 			UpdatedAt: time.Now().Add(-2 * time.Hour),
 		},
@@ -721,7 +746,7 @@ func seedBookings(ctx context.Context) {
 				Status: "pending",
 			},
 			QRCode:    "QR-BK-20260201-001235",
-			ExpiresAt: time.Now().Add(15 * time.Minute),
+			ExpiresAt: &pendingExpiresAt,
 			CreatedAt: time.Now().Add(-5 * time.Minute),
 			UpdatedAt: time.Now().Add(-5 * time.Minute),
 		},
@@ -0,0 +1,24 @@
+package scripts
+
+import (
+	"cinema-booking/handlers"
+	"context"
+	"log"
+	"time"
+)
+
+// SendNotifications - отправить письма по уведомлениям из очереди (запускать по cron,
+// подхватывает то, что не отправилось сразу после импорта расписания)
+func SendNotifications() bool {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Minute)
+	defer cancel()
+
+	sent, err := handlers.SendPendingNotifications(ctx)
+	if err != nil {
+		log.Printf("❌ Failed to send notifications: %v", err)
+		return false
+	}
+
+	log.Printf("✅ Sent %d notification emails", sent)
+	return true
+}
@@ -0,0 +1,24 @@
+package scripts
+
+import (
+	"cinema-booking/handlers"
+	"context"
+	"log"
+	"time"
+)
+
+// UpdateMovieStatuses - активировать фильмы в день релиза и снять с проката фильмы
+// без будущих сеансов (запускать по cron раз в час)
+func UpdateMovieStatuses() bool {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Minute)
+	defer cancel()
+
+	activated, deactivated, err := handlers.UpdateMovieStatuses(ctx)
+	if err != nil {
+		log.Printf("❌ Failed to update movie statuses: %v", err)
+		return false
+	}
+
+	log.Printf("✅ Activated %d released movies, deactivated %d finished movies", activated, deactivated)
+	return true
+}
@@ -0,0 +1,218 @@
+package search
+
+import (
+	"sort"
+	"strings"
+)
+
+// Типы подсказок
+const (
+	TypeMovie  = "movie"
+	TypePerson = "person"
+	TypeCinema = "cinema"
+)
+
+// Entry - объект поиска (фильм, персона, кинотеатр)
+type Entry struct {
+	Type     string
+	ID       string
+	Labels   map[string]string // название по языкам (kk, ru, en)
+	Subtitle string
+	// Fields - тексты для поиска; первое поле - основное (название)
+	Fields []string
+	// Boost - небольшая добавка к релевантности (рейтинг, популярность), 0..0.1
+	Boost float64
+}
+
+// Suggestion - найденный объект с оценкой релевантности
+type Suggestion struct {
+	Entry *Entry
+	Score float64
+}
+
+type indexedField struct {
+	text   string   // нормализованный текст
+	tokens []string // его слова
+	weight float64
+}
+
+type indexedEntry struct {
+	entry  *Entry
+	fields []indexedField
+}
+
+// Index - неизменяемый поисковый индекс в памяти; при изменении данных строится новый
+type Index struct {
+	entries []indexedEntry
+}
+
+// Вес дополнительных полей (оригинальное название, город, актеры) ниже основного
+const secondaryFieldWeight = 0.8
+
+// NewIndex - построить индекс
+func NewIndex(entries []Entry) *Index {
+	idx := &Index{entries: make([]indexedEntry, 0, len(entries))}
+	for i := range entries {
+		e := &entries[i]
+		item := indexedEntry{entry: e}
+		for j, field := range e.Fields {
+			text := Normalize(field)
+			if text == "" {
+				continue
+			}
+			weight := 1.0
+			if j > 0 {
+				weight = secondaryFieldWeight
+			}
+			item.fields = append(item.fields, indexedField{text: text, tokens: strings.Fields(text), weight: weight})
+		}
+		if len(item.fields) > 0 {
+			idx.entries = append(idx.entries, item)
+		}
+	}
+	return idx
+}
+
+// Len - число объектов в индексе
+func (idx *Index) Len() int {
+	if idx == nil {
+		return 0
+	}
+	return len(idx.entries)
+}
+
+// Suggest - подсказки по запросу: совпадение с началом названия, префиксы слов
+// и опечатки (расстояние Дамерау-Левенштейна). types - фильтр по типу (пусто = все).
+func (idx *Index) Suggest(query string, limit int, types map[string]bool) []Suggestion {
+	if idx == nil {
+		return nil
+	}
+	normalized := Normalize(query)
+	queryTokens := strings.Fields(normalized)
+	if len(queryTokens) == 0 {
+		return nil
+	}
+
+	results := []Suggestion{}
+	for i := range idx.entries {
+		item := &idx.entries[i]
+		if len(types) > 0 && !types[item.entry.Type] {
+			continue
+		}
+
+		best := 0.0
+		for _, field := range item.fields {
+			if score := scoreField(normalized, queryTokens, field) * field.weight; score > best {
+				best = score
+			}
+		}
+		if best > 0 {
+			results = append(results, Suggestion{Entry: item.entry, Score: best + item.entry.Boost})
+		}
+	}
+
+	sort.SliceStable(results, func(i, j int) bool {
+		if results[i].Score != results[j].Score {
+			return results[i].Score > results[j].Score
+		}
+		return results[i].Entry.Fields[0] < results[j].Entry.Fields[0]
+	})
+
+	if limit > 0 && len(results) > limit {
+		results = results[:limit]
+	}
+	return results
+}
+
+// scoreField - релевантность поля: 1.0 точное совпадение, 0.95 начало названия,
+// иначе среднее по словам запроса (каждое слово должно найтись)
+func scoreField(query string, queryTokens []string, field indexedField) float64 {
+	if field.text == query {
+		return 1.0
+	}
+	if strings.HasPrefix(field.text, query) {
+		return 0.95
+	}
+
+	total := 0.0
+	for _, qt := range queryTokens {
+		best := 0.0
+		for _, token := range field.tokens {
+			if s := scoreToken(qt, token); s > best {
+				best = s
+			}
+		}
+		if best == 0 {
+			return 0
+		}
+		total += best
+	}
+	return 0.85 * total / float64(len(queryTokens))
+}
+
+// scoreToken - совпадение слова запроса со словом поля: полное, префикс или с опечатками
+func scoreToken(qt, token string) float64 {
+	if qt == token {
+		return 1.0
+	}
+	if strings.HasPrefix(token, qt) {
+		return 0.9
+	}
+
+	maxDist := typoTolerance(len(qt))
+	if maxDist == 0 {
+		return 0
+	}
+
+	// Опечатка в целом слове или в начале слова (пользователь еще печатает)
+	dist := editDistance(qt, token)
+	if len(token) > len(qt) {
+		if d := editDistance(qt, token[:len(qt)]); d < dist {
+			dist = d
+		}
+	}
+	if dist > maxDist {
+		return 0
+	}
+	return 0.75 - 0.15*float64(dist-1)
+}
+
+// typoTolerance - допустимое число опечаток по длине слова
+func typoTolerance(length int) int {
+	switch {
+	case length < 4:
+		return 0
+	case length < 8:
+		return 1
+	default:
+		return 2
+	}
+}
+
+// editDistance - расстояние Дамерау-Левенштейна (вариант с соседними перестановками)
+func editDistance(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+	rows := make([][]int, len(ra)+1)
+	for i := range rows {
+		rows[i] = make([]int, len(rb)+1)
+		rows[i][0] = i
+	}
+	for j := range rows[0] {
+		rows[0][j] = j
+	}
+
+	for i := 1; i <= len(ra); i++ {
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			d := min(rows[i-1][j]+1, rows[i][j-1]+1, rows[i-1][j-1]+cost)
+			if i > 1 && j > 1 && ra[i-1] == rb[j-2] && ra[i-2] == rb[j-1] {
+				d = min(d, rows[i-2][j-2]+1)
+			}
+			rows[i][j] = d
+		}
+	}
+	return rows[len(ra)][len(rb)]
+}
@@ -0,0 +1,69 @@
+package search
+
+import (
+	"strings"
+	"unicode"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+// Транслитерация кириллицы (русский + казахский алфавит) в латиницу,
+// чтобы "Аватар" и "Avatar" давали одинаковый ключ
+var cyrillicToLatin = map[rune]string{
+	'а': "a", 'б': "b", 'в': "v", 'г': "g", 'д': "d", 'е': "e", 'ё': "e",
+	'ж': "zh", 'з': "z", 'и': "i", 'й': "i", 'к': "k", 'л': "l", 'м': "m",
+	'н': "n", 'о': "o", 'п': "p", 'р': "r", 'с': "s", 'т': "t", 'у': "u",
+	'ф': "f", 'х': "h", 'ц': "ts", 'ч': "ch", 'ш': "sh", 'щ': "sh", 'ъ': "",
+	'ы': "i", 'ь': "", 'э': "e", 'ю': "iu", 'я': "ia",
+	// Казахские буквы
+	'ә': "a", 'ғ': "g", 'қ': "k", 'ң': "n", 'ө': "o", 'ұ': "u", 'ү': "u",
+	'һ': "h", 'і': "i",
+}
+
+// Упрощение латиницы: разные записи одного звука сводятся к одной
+// ("Kh"/"H", "Y"/"I", "W"/"V"), чтобы транслитерация совпадала с оригиналом
+var latinSkeleton = strings.NewReplacer(
+	"kh", "h",
+	"ph", "f",
+	"ck", "k",
+	"yu", "iu",
+	"ya", "ia",
+	"y", "i",
+	"j", "i",
+	"w", "v",
+	"q", "k",
+	"x", "ks",
+	"c", "k",
+)
+
+// Normalize - привести текст к поисковому ключу: нижний регистр, без диакритики,
+// кириллица в латинице, пунктуация заменена пробелами
+func Normalize(s string) string {
+	var b strings.Builder
+	b.Grow(len(s))
+
+	for _, r := range norm.NFD.String(strings.ToLower(s)) {
+		switch {
+		case unicode.Is(unicode.Mn, r):
+			// Диакритические знаки (é -> e, й после NFD -> и)
+			continue
+		case r >= 'a' && r <= 'z', r >= '0' && r <= '9':
+			b.WriteRune(r)
+		default:
+			if latin, ok := cyrillicToLatin[r]; ok {
+				b.WriteString(latin)
+			} else if unicode.IsLetter(r) {
+				b.WriteRune(r)
+			} else {
+				b.WriteByte(' ')
+			}
+		}
+	}
+
+	return strings.Join(strings.Fields(latinSkeleton.Replace(b.String())), " ")
+}
+
+// Tokens - слова нормализованного текста
+func Tokens(s string) []string {
+	return strings.Fields(Normalize(s))
+}
@@ -0,0 +1,41 @@
+package utils
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"math/big"
+)
+
+// Алфавит для генерации кодов (без похожих символов 0/O, 1/I)
+const codeAlphabet = "ABCDEFGHJKLMNPQRSTUVWXYZ23456789"
+
+// RandomCode - криптографически случайный код заданной длины
+func RandomCode(length int) (string, error) {
+	code := make([]byte, length)
+	max := big.NewInt(int64(len(codeAlphabet)))
+	for i := range code {
+		n, err := rand.Int(rand.Reader, max)
+		if err != nil {
+			return "", err
+		}
+		code[i] = codeAlphabet[n.Int64()]
+	}
+	return string(code), nil
+}
+
+// RandomToken - случайная строка из n байт (base64url, для токенов и jti)
+func RandomToken(n int) (string, error) {
+	b := make([]byte, n)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+// HashToken - SHA-256 хеш токена для хранения в базе (hex)
+func HashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,125 @@
+package utils
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"image"
+	"image/draw"
+	"image/gif"
+	"image/jpeg"
+	"image/png"
+
+	"github.com/HugoSmits86/nativewebp"
+	xdraw "golang.org/x/image/draw"
+	"golang.org/x/image/webp"
+)
+
+// Ограничения на загружаемые изображения (защита от "декомпрессионных бомб")
+const (
+	MaxImagePixels    = 40_000_000 // ~40 Мп
+	MaxImageDimension = 10000      // по любой стороне
+	jpegQuality       = 85
+)
+
+// ImageVariant - размер производной версии изображения
+type ImageVariant struct {
+	Name  string // "thumbnail", "card", "full"
+	Width int    // максимальная ширина (увеличение не делаем)
+}
+
+// ImageVariants - варианты, которые генерируются при загрузке
+var ImageVariants = []ImageVariant{
+	{Name: "thumbnail", Width: 200},
+	{Name: "card", Width: 500},
+	{Name: "full", Width: 1200},
+}
+
+// ImageFormats - форматы производных версий. JPEG сохраняется всегда; WebP кодируется
+// без потерь и для фотографий обычно крупнее JPEG, поэтому хранится, только если он меньше.
+var ImageFormats = []string{"jpeg", "webp"}
+
+// DecodeImage - проверить, что данные - настоящее изображение в допустимых размерах, и декодировать его.
+// Размеры проверяются по заголовку до полного декодирования.
+func DecodeImage(data []byte, contentType string) (image.Image, error) {
+	var decodeConfig func(r *bytes.Reader) (image.Config, error)
+	var decode func(r *bytes.Reader) (image.Image, error)
+
+	switch contentType {
+	case "image/jpeg":
+		decodeConfig = func(r *bytes.Reader) (image.Config, error) { return jpeg.DecodeConfig(r) }
+		decode = func(r *bytes.Reader) (image.Image, error) { return jpeg.Decode(r) }
+	case "image/png":
+		decodeConfig = func(r *bytes.Reader) (image.Config, error) { return png.DecodeConfig(r) }
+		decode = func(r *bytes.Reader) (image.Image, error) { return png.Decode(r) }
+	case "image/gif":
+		decodeConfig = func(r *bytes.Reader) (image.Config, error) { return gif.DecodeConfig(r) }
+		decode = func(r *bytes.Reader) (image.Image, error) { return gif.Decode(r) }
+	case "image/webp":
+		decodeConfig = func(r *bytes.Reader) (image.Config, error) { return webp.DecodeConfig(r) }
+		decode = func(r *bytes.Reader) (image.Image, error) { return webp.Decode(r) }
+	default:
+		return nil, fmt.Errorf("unsupported image type: %s", contentType)
+	}
+
+	cfg, err := decodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return nil, errors.New("file is not a valid image")
+	}
+	if cfg.Width <= 0 || cfg.Height <= 0 {
+		return nil, errors.New("file is not a valid image")
+	}
+	if cfg.Width > MaxImageDimension || cfg.Height > MaxImageDimension ||
+		int64(cfg.Width)*int64(cfg.Height) > MaxImagePixels {
+		return nil, fmt.Errorf("image is too large: %dx%d (max %d px per side, %d Mpx)",
+			cfg.Width, cfg.Height, MaxImageDimension, MaxImagePixels/1_000_000)
+	}
+
+	img, err := decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, errors.New("file is not a valid image")
+	}
+	return img, nil
+}
+
+// ResizeImage - уменьшить изображение до ширины width с сохранением пропорций.
+// Изображения уже нужной ширины не увеличиваются.
+func ResizeImage(src image.Image, width int) image.Image {
+	bounds := src.Bounds()
+	if width <= 0 || width >= bounds.Dx() {
+		return src
+	}
+
+	height := bounds.Dy() * width / bounds.Dx()
+	if height < 1 {
+		height = 1
+	}
+	dst := image.NewRGBA(image.Rect(0, 0, width, height))
+	xdraw.CatmullRom.Scale(dst, dst.Bounds(), src, bounds, draw.Src, nil)
+	return dst
+}
+
+// EncodeImage - закодировать изображение в "jpeg" (качество jpegQuality) или "webp" (lossless:
+// выигрывает только на графике с плоскими цветами). Возвращает данные и MIME тип.
+func EncodeImage(img image.Image, format string) ([]byte, string, error) {
+	var buf bytes.Buffer
+	switch format {
+	case "jpeg":
+		// JPEG не поддерживает прозрачность - подложить белый фон
+		bounds := img.Bounds()
+		flat := image.NewRGBA(bounds)
+		draw.Draw(flat, bounds, image.White, image.Point{}, draw.Src)
+		draw.Draw(flat, bounds, img, bounds.Min, draw.Over)
+		if err := jpeg.Encode(&buf, flat, &jpeg.Options{Quality: jpegQuality}); err != nil {
+			return nil, "", err
+		}
+		return buf.Bytes(), "image/jpeg", nil
+	case "webp":
+		if err := nativewebp.Encode(&buf, img, nil); err != nil {
+			return nil, "", err
+		}
+		return buf.Bytes(), "image/webp", nil
+	default:
+		return nil, "", fmt.Errorf("unsupported image format: %s", format)
+	}
+}
@@ -3,10 +3,14 @@ package utils
 import (
 	"cinema-booking/config"
 	"cinema-booking/models"
+	"context"
 	"errors"
 	"time"
 
 	"github.com/golang-jwt/jwt/v5"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo/options"
 )
 
 // JWTClaims - структура для JWT токена
@@ -14,21 +18,42 @@ type JWTClaims struct {
 	UserID string `json:"userId"`
 	Email  string `json:"email"`
 	Role   string `json:"role"`
+	MFA    bool   `json:"mfa,omitempty"` // вход подтвержден кодом TOTP
 	jwt.RegisteredClaims
 }
 
-// GenerateToken - создать JWT токен для пользователя
-func GenerateToken(user models.User) (string, error) {
-	// Получить срок действия из конфига
-	expirationTime := time.Now().Add(24 * time.Hour) // 24 часа
+// Срок действия access токена, если JWT_EXPIRATION не задан или некорректен
+const defaultAccessTokenTTL = 15 * time.Minute
 
+// AccessTokenTTL - срок действия access токена из конфига (JWT_EXPIRATION, например "15m")
+func AccessTokenTTL() time.Duration {
+	ttl, err := time.ParseDuration(config.AppConfig.JWTExpiration)
+	if err != nil || ttl <= 0 {
+		return defaultAccessTokenTTL
+	}
+	return ttl
+}
+
+// GenerateToken - создать access токен (JWT) для пользователя.
+// mfa - сессия прошла второй фактор (обязателен для админов и менеджеров).
+// Возвращает и claims: jti и срок нужны для отзыва токена.
+func GenerateToken(user models.User, mfa bool) (string, *JWTClaims, error) {
+	// Уникальный ID токена (jti) для denylist
+	jti, err := RandomToken(16)
+	if err != nil {
+		return "", nil, err
+	}
+
+	now := time.Now()
 	claims := JWTClaims{
 		UserID: user.ID.Hex(),
 		Email:  user.Email,
 		Role:   user.Role,
+		MFA:    mfa,
 		RegisteredClaims: jwt.RegisteredClaims{
-			ExpiresAt: jwt.NewNumericDate(expirationTime),
-			IssuedAt:  jwt.NewNumericDate(time.Now()),
+			ID:        jti,
+			ExpiresAt: jwt.NewNumericDate(now.Add(AccessTokenTTL())),
+			IssuedAt:  jwt.NewNumericDate(now),
 			Issuer:    "cinema-booking-system",
 		},
 	}
@@ -39,10 +64,10 @@ func GenerateToken(user models.User) (string, error) {
 	// Подписать токен секретным ключом
 	tokenString, err := token.SignedString([]byte(config.AppConfig.JWTSecret))
 	if err != nil {
-		return "", err
+		return "", nil, err
 	}
 
-	return tokenString, nil
+	return tokenString, &claims, nil
 }
 
 // ValidateToken - проверить JWT токен
@@ -67,3 +92,33 @@ func ValidateToken(tokenString string) (*JWTClaims, error) {
 
 	return nil, errors.New("invalid token")
 }
+
+// RevokeToken - добавить access токен в denylist до истечения его срока
+func RevokeToken(ctx context.Context, jti string, userID primitive.ObjectID, expiresAt time.Time) error {
+	if jti == "" || !expiresAt.After(time.Now()) {
+		return nil // токен без jti (выдан до denylist) или уже истек
+	}
+	_, err := config.GetCollection("revoked_tokens").UpdateOne(ctx,
+		bson.M{"_id": jti},
+		bson.M{"$setOnInsert": models.RevokedToken{
+			ID:        jti,
+			UserID:    userID,
+			ExpiresAt: expiresAt,
+			RevokedAt: time.Now(),
+		}},
+		options.Update().SetUpsert(true),
+	)
+	return err
+}
+
+// IsTokenRevoked - access токен в denylist
+func IsTokenRevoked(ctx context.Context, jti string) (bool, error) {
+	if jti == "" {
+		return false, nil
+	}
+	count, err := config.GetCollection("revoked_tokens").CountDocuments(ctx, bson.M{"_id": jti})
+	if err != nil {
+		return false, err
+	}
+	return count > 0, nil
+}
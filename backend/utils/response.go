@@ -1,6 +1,10 @@
 package utils
 
-import "github.com/gin-gonic/gin"
+import (
+	"cinema-booking/i18n"
+
+	"github.com/gin-gonic/gin"
+)
 
 // SuccessResponse - стандартный успешный ответ
 func SuccessResponse(c *gin.Context, statusCode int, data interface{}) {
@@ -10,11 +14,11 @@ func SuccessResponse(c *gin.Context, statusCode int, data interface{}) {
 	})
 }
 
-// ErrorResponse - стандартный ответ с ошибкой
+// ErrorResponse - стандартный ответ с ошибкой (сообщение переводится на язык запроса)
 func ErrorResponse(c *gin.Context, statusCode int, message string) {
 	c.JSON(statusCode, gin.H{
 		"success": false,
-		"error":   message,
+		"error":   i18n.T(i18n.FromContext(c), message),
 	})
 }
 
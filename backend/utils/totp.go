@@ -0,0 +1,97 @@
+package utils
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha1"
+	"crypto/subtle"
+	"encoding/base32"
+	"encoding/binary"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// Параметры TOTP (RFC 6238) - значения по умолчанию Google Authenticator и аналогов
+const (
+	totpPeriod = 30 // секунд
+	totpDigits = 6
+	totpSkew   = 1 // допустимое расхождение часов: ±1 шаг
+)
+
+var totpEncoding = base32.StdEncoding.WithPadding(base32.NoPadding)
+
+// Роли, которым нельзя работать без второго фактора
+var mfaRequiredRoles = map[string]bool{
+	"admin":          true,
+	"cinema_manager": true,
+}
+
+// RoleRequiresMFA - обязателен ли второй фактор для роли
+func RoleRequiresMFA(role string) bool {
+	return mfaRequiredRoles[role]
+}
+
+// GenerateTOTPSecret - новый секрет TOTP (160 бит, base32 без выравнивания)
+func GenerateTOTPSecret() (string, error) {
+	secret := make([]byte, 20)
+	if _, err := rand.Read(secret); err != nil {
+		return "", err
+	}
+	return totpEncoding.EncodeToString(secret), nil
+}
+
+// TOTPProvisioningURI - ссылка otpauth:// для QR кода в приложении-аутентификаторе
+func TOTPProvisioningURI(secret, issuer, account string) string {
+	params := url.Values{}
+	params.Set("secret", secret)
+	params.Set("issuer", issuer)
+	params.Set("algorithm", "SHA1")
+	params.Set("digits", fmt.Sprint(totpDigits))
+	params.Set("period", fmt.Sprint(totpPeriod))
+
+	label := url.PathEscape(issuer) + ":" + url.PathEscape(account)
+	// Пробел в issuer - %20: некоторые приложения показывают "+" как есть
+	return "otpauth://totp/" + label + "?" + strings.ReplaceAll(params.Encode(), "+", "%20")
+}
+
+// TOTPCode - код для шага времени (HOTP от номера 30-секундного интервала)
+func TOTPCode(secret string, step int64) (string, error) {
+	key, err := totpEncoding.DecodeString(strings.ToUpper(strings.TrimRight(secret, "=")))
+	if err != nil {
+		return "", err
+	}
+
+	var counter [8]byte
+	binary.BigEndian.PutUint64(counter[:], uint64(step))
+	mac := hmac.New(sha1.New, key)
+	mac.Write(counter[:])
+	sum := mac.Sum(nil)
+
+	// Динамическое усечение (RFC 4226, 5.3)
+	offset := sum[len(sum)-1] & 0x0f
+	value := binary.BigEndian.Uint32(sum[offset:offset+4]) & 0x7fffffff
+	return fmt.Sprintf("%0*d", totpDigits, value%1000000), nil
+}
+
+// ValidateTOTP - проверить код на момент t с учетом расхождения часов.
+// Возвращает шаг, которому соответствует код: повторно использовать его нельзя.
+func ValidateTOTP(secret, code string, t time.Time) (int64, bool) {
+	code = strings.ReplaceAll(strings.TrimSpace(code), " ", "")
+	if len(code) != totpDigits {
+		return 0, false
+	}
+
+	current := t.Unix() / totpPeriod
+	for step := current - totpSkew; step <= current+totpSkew; step++ {
+		expected, err := TOTPCode(secret, step)
+		if err != nil {
+			return 0, false
+		}
+		if subtle.ConstantTimeCompare([]byte(expected), []byte(code)) == 1 {
+			return step, true
+		}
+	}
+	return 0, false
+}
@@ -5,6 +5,9 @@ import SeatSelection from '../views/SeatSelection.vue'
 import Profile from '../views/Profile.vue'
 import Login from '../views/Login.vue'
 import TopUp from '../views/TopUp.vue'
+import VerifyEmail from '../views/VerifyEmail.vue'
+import ResetPassword from '../views/ResetPassword.vue'
+import UnlockAccount from '../views/UnlockAccount.vue'
 import NotFound from '../views/NotFound.vue'
 import ServerError from '../views/ServerError.vue'
 
@@ -42,6 +45,21 @@ const routes = [
     name: 'Login',
     component: Login
   },
+  {
+    path: '/verify-email',
+    name: 'VerifyEmail',
+    component: VerifyEmail
+  },
+  {
+    path: '/reset-password',
+    name: 'ResetPassword',
+    component: ResetPassword
+  },
+  {
+    path: '/unlock-account',
+    name: 'UnlockAccount',
+    component: UnlockAccount
+  },
   {
     path: '/error',
     name: 'ServerError',
@@ -25,17 +25,78 @@ api.interceptors.request.use(
   }
 )
 
+// Обновление access токена по refresh токену (один запрос на все параллельные 401)
+let refreshPromise = null
+
+const wait = (ms) => new Promise((resolve) => setTimeout(resolve, ms))
+
+// Токены лежат в общем localStorage: обмен сериализуется между вкладками (Web Locks API)
+const withRefreshLock = (callback) =>
+  navigator.locks ? navigator.locks.request('auth-refresh', callback) : callback()
+
+const requestNewTokens = async (seenRefreshToken, attempt = 0) => {
+  const refreshToken = localStorage.getItem('refreshToken')
+  if (!refreshToken) {
+    throw new Error('No refresh token')
+  }
+  // Пока ждали, другая вкладка уже обменяла токен - взять ее результат
+  if (refreshToken !== seenRefreshToken && localStorage.getItem('token')) {
+    return localStorage.getItem('token')
+  }
+
+  try {
+    const response = await axios.post(`${API_BASE_URL}/auth/refresh`, { refreshToken })
+    const { token, refreshToken: nextRefreshToken } = response.data.data
+    localStorage.setItem('token', token)
+    localStorage.setItem('refreshToken', nextRefreshToken)
+    return token
+  } catch (error) {
+    // 409 - токен только что заменен в другой вкладке: дождаться нового токена в хранилище
+    if (error.response?.status === 409 && attempt < 3) {
+      await wait(500)
+      return requestNewTokens(seenRefreshToken, attempt + 1)
+    }
+    throw error
+  }
+}
+
+const refreshAccessToken = () => {
+  if (!refreshPromise) {
+    const seenRefreshToken = localStorage.getItem('refreshToken')
+    refreshPromise = withRefreshLock(() => requestNewTokens(seenRefreshToken))
+      .finally(() => {
+        refreshPromise = null
+      })
+  }
+  return refreshPromise
+}
+
 // Interceptor для обработки ошибок
 api.interceptors.response.use(
   (response) => response,
-  (error) => {
+  async (error) => {
     const status = error.response?.status
+    const original = error.config
+
+    // Access токен истек - обновить и повторить запрос один раз
+    if (status === 401 && original && !original._retry && !original.url.startsWith('/auth/')) {
+      original._retry = true
+      try {
+        const token = await refreshAccessToken()
+        original.headers.Authorization = `Bearer ${token}`
+        return api(original)
+      } catch (refreshError) {
+        // refresh токен недействителен - нужен повторный вход
+      }
+    }
 
     if (status === 401) {
       localStorage.removeItem('token')
+      localStorage.removeItem('refreshToken')
       localStorage.removeItem('user')
       window.location.href = '/login'
-    } else if (status === 403) {
+    } else if (status === 403 && (!original || original.method === 'get')) {
+      // Нет доступа к странице; отказ в действии (например, email не подтвержден) страница показывает сама
       window.location.href = `/error?code=403&from=${encodeURIComponent(window.location.pathname)}`
     } else if (status === 500 || status === 502 || status === 503) {
       window.location.href = `/error?code=${status}&from=${encodeURIComponent(window.location.pathname)}`
@@ -54,6 +115,27 @@ export default {
   login(data) {
     return api.post('/auth/login', data)
   },
+  logout(data) {
+    return api.post('/auth/logout', data)
+  },
+  verifyMfa(data) {
+    return api.post('/auth/mfa/verify', data)
+  },
+  forgotPassword(data) {
+    return api.post('/auth/password/forgot', data)
+  },
+  resetPassword(data) {
+    return api.post('/auth/password/reset', data)
+  },
+  verifyEmail(data) {
+    return api.post('/auth/email/verify', data)
+  },
+  resendVerificationEmail() {
+    return api.post('/auth/email/resend')
+  },
+  unlockAccount(data) {
+    return api.post('/auth/unlock', data)
+  },
   getProfile() {
     return api.get('/profile')
   },
@@ -69,6 +151,10 @@ export default {
     return api.get(`/movies/${id}`)
   },
 
+  getMovieReviews(id, params = {}) {
+    return api.get(`/movies/${id}/reviews`, { params })
+  },
+
   // Cinemas
   getCinemas(params) {
     return api.get('/cinemas', { params })
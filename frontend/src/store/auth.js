@@ -12,15 +12,14 @@ export const useAuthStore = defineStore('auth', {
     async login(credentials) {
       try {
         const response = await api.login(credentials)
-        const { token, user } = response.data.data
+        const data = response.data.data
 
-        this.token = token
-        this.user = user
-        this.isAuthenticated = true
-
-        localStorage.setItem('token', token)
-        localStorage.setItem('user', JSON.stringify(user))
+        // Включена 2FA: нужен второй шаг с кодом из приложения
+        if (data.mfaRequired) {
+          return { success: false, mfaRequired: true, mfaToken: data.mfaToken }
+        }
 
+        this.setSession(data)
         return { success: true }
       } catch (error) {
         return { 
@@ -30,16 +29,40 @@ export const useAuthStore = defineStore('auth', {
       }
     },
 
+    async verifyMfa(mfaToken, code) {
+      try {
+        const response = await api.verifyMfa({ mfaToken, code })
+        this.setSession(response.data.data)
+        return { success: true }
+      } catch (error) {
+        return {
+          success: false,
+          error: error.response?.data?.error || 'Verification failed'
+        }
+      }
+    },
+
+    setSession({ token, refreshToken, user }) {
+      this.token = token
+      this.user = user
+      this.isAuthenticated = true
+
+      localStorage.setItem('token', token)
+      localStorage.setItem('refreshToken', refreshToken)
+      localStorage.setItem('user', JSON.stringify(user))
+    },
+
     async register(userData) {
       try {
         const response = await api.register(userData)
-        const { token, user } = response.data.data
+        const { token, refreshToken, user } = response.data.data
 
         this.token = token
         this.user = user
         this.isAuthenticated = true
 
         localStorage.setItem('token', token)
+        localStorage.setItem('refreshToken', refreshToken)
         localStorage.setItem('user', JSON.stringify(user))
 
         return { success: true }
@@ -51,12 +74,25 @@ export const useAuthStore = defineStore('auth', {
       }
     },
 
-    logout() {
+    async logout() {
+      // Отозвать сессию на сервере (ошибки не мешают выйти локально)
+      try {
+        await api.logout({ refreshToken: localStorage.getItem('refreshToken') })
+      } catch (error) {
+        console.error('Failed to log out on server:', error)
+      }
+
+      this.clearSession()
+    },
+
+    // Забыть токены локально (например, после сброса пароля сервер уже закрыл все сессии)
+    clearSession() {
       this.user = null
       this.token = null
       this.isAuthenticated = false
 
       localStorage.removeItem('token')
+      localStorage.removeItem('refreshToken')
       localStorage.removeItem('user')
     },
 